@@ -0,0 +1,62 @@
+// Package app is the application container. It builds every layer from a
+// config and a database handle and passes each its dependencies explicitly,
+// so several instances can live in one process.
+package app
+
+import (
+	"base-gin/config"
+	"base-gin/mailer"
+	"base-gin/repository"
+	"base-gin/rest"
+	"base-gin/search"
+	"base-gin/server"
+	"base-gin/service"
+	"base-gin/util"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type App struct {
+	Config       *config.Config
+	DB           *gorm.DB
+	KeySet       *util.KeySet
+	Repositories *repository.Repositories
+	Services     *service.Services
+	Handler      *server.Handler
+	Engine       *gin.Engine
+}
+
+func New(cfg *config.Config, db *gorm.DB) (*App, error) {
+	keys, err := util.NewKeySet(cfg.AuthN)
+	if err != nil {
+		return nil, err
+	}
+
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := repository.NewRepositories(db)
+	index, err := search.New(cfg, repos.Search)
+	if err != nil {
+		return nil, err
+	}
+
+	services := service.NewServices(cfg, keys, repos, repository.NewUnitOfWork(db, repos), mail, index)
+	hr := server.NewHandler(cfg, keys, repos.Account, repos.Session, repos.APIKey, server.NewMemoryThrottleStore())
+
+	engine := server.NewEngine()
+	rest.SetupRestHandlers(engine, hr, services)
+
+	return &App{
+		Config:       cfg,
+		DB:           db,
+		KeySet:       keys,
+		Repositories: repos,
+		Services:     services,
+		Handler:      hr,
+		Engine:       engine,
+	}, nil
+}
@@ -18,25 +18,55 @@ type AppConfig struct {
 }
 
 type DBConfig struct {
+	Driver        string `env:"DB_DRIVER" envDefault:"mysql"` // mysql, sqlite or postgres
 	DSN           string `env:"DB_DSN"`
 	MaxOpenPool   int    `env:"DB_MAX_OPEN_POOL" envDefault:"25"`
 	MaxIdlePool   int    `env:"DB_MAX_IDLE_POOL" envDefault:"25"`
 	MaxIdleSecond int    `env:"DB_MAX_IDLE_SECOND" envDefault:"300"`
+	QueryTimeout  int    `env:"DB_QUERY_TIMEOUT" envDefault:"5"` // in seconds, 0 for none
 }
 
 type AuthNConfig struct {
-	LoginThrottleTTL         int    `env:"LOGIN_THROTTLE_TTL" envDefault:"300"` // in seconds
-	LoginMaxAttempt          int    `env:"LOGIN_MAX_ATTEMPT" envDefault:"10"`
-	JWTSecretKey             string `env:"JWT_SECRET"`
-	JWTAuthTTL               int    `env:"JWT_AUTH_TTL" envDefault:"3600"`
-	JWTRefreshTTL            int    `env:"JWT_REFRESH_TTL" envDefault:"2592000"`
-	PasswordEncryptionSecret string `env:"PWD_SECRET_32CHAR"`
+	LoginThrottleTTL         int      `env:"LOGIN_THROTTLE_TTL" envDefault:"300"` // in seconds
+	LoginMaxAttempt          int      `env:"LOGIN_MAX_ATTEMPT" envDefault:"10"`
+	JWTSecretKey             string   `env:"JWT_SECRET" envDefault:""`
+	JWTSigningKeyFile        string   `env:"JWT_SIGNING_KEY_FILE" envDefault:""`                  // RSA or Ed25519 private key, PEM
+	JWTVerifyKeyFiles        []string `env:"JWT_VERIFY_KEY_FILES" envSeparator:"," envDefault:""` // previous public keys, PEM
+	JWTLegacyHS256Until      string   `env:"JWT_ACCEPT_LEGACY_HS256_UNTIL" envDefault:""`         // RFC 3339; JWT_SECRET still verifies next to a signing key until then
+	JWTIssuer                string   `env:"JWT_ISSUER" envDefault:"plus.quranbest.com"`
+	JWTAuthTTL               int      `env:"JWT_AUTH_TTL" envDefault:"3600"`
+	JWTRefreshTTL            int      `env:"JWT_REFRESH_TTL" envDefault:"2592000"`
+	PasswordEncryptionSecret string   `env:"PWD_SECRET_32CHAR"`
+	PasswordResetTTL         int      `env:"PASSWORD_RESET_TTL" envDefault:"3600"` // in seconds
+	PasswordResetURL         string   `env:"PASSWORD_RESET_URL" envDefault:""`
+}
+
+type MailConfig struct {
+	Driver  string `env:"MAIL_DRIVER" envDefault:"log"` // log or file
+	From    string `env:"MAIL_FROM" envDefault:"noreply@localhost"`
+	FileDir string `env:"MAIL_FILE_DIR" envDefault:"./mail"`
+}
+
+type SearchConfig struct {
+	Driver string `env:"SEARCH_DRIVER" envDefault:"database"` // database or memory
+}
+
+type LibraryConfig struct {
+	LoanPeriodDays     int   `env:"LOAN_PERIOD_DAYS" envDefault:"14"`
+	RenewalLimit       int   `env:"RENEWAL_LIMIT" envDefault:"2"`
+	FinePerDay         int64 `env:"FINE_PER_DAY" envDefault:"1000"`
+	FineMax            int64 `env:"FINE_MAX" envDefault:"50000"`
+	FineBlockThreshold int64 `env:"FINE_BLOCK_THRESHOLD" envDefault:"20000"`
+	HoldPickupDays     int   `env:"HOLD_PICKUP_DAYS" envDefault:"3"`
 }
 
 type Config struct {
-	App   AppConfig
-	DB    DBConfig
-	AuthN AuthNConfig
+	App     AppConfig
+	DB      DBConfig
+	AuthN   AuthNConfig
+	Library LibraryConfig
+	Mail    MailConfig
+	Search  SearchConfig
 }
 
 func NewConfig() Config {
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"base-gin/domain"
 	"base-gin/util"
 	"time"
 )
@@ -9,14 +10,22 @@ type Account struct {
 	ID        uint `gorm:"primarykey"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Username  string `gorm:"size:16;not null;uniqueIndex:user_pass;"`
-	Password  string `gorm:"size:255;not null;uniqueIndex:user_pass;"`
-	Token     string `gorm:"size:255;not null;uniqueIndex:user_pass;"`
+	Username  string          `gorm:"size:16;not null;uniqueIndex:user_pass;"`
+	Password  string          `gorm:"size:255;not null;uniqueIndex:user_pass;"`
+	Role      domain.TypeRole `gorm:"size:16;not null;default:member;"`
+	Email     *string         `gorm:"size:128;uniqueIndex;"`
+
+	// TOTPSecret is encrypted with PWD_SECRET_32CHAR. It is set during
+	// enrollment but only enforced once TOTPEnabledAt is set.
+	TOTPSecret    string `gorm:"size:255;"`
+	TOTPEnabledAt *time.Time
+	TOTPLastStep  int64 `gorm:"not null;default:0;"`
 }
 
 func NewUser(uname, paswd, secret string) (Account, error) {
 	account := Account{
 		Username: uname,
+		Role:     domain.RoleMember,
 	}
 
 	if err := account.SetPassword(paswd, secret); err != nil {
@@ -26,6 +35,16 @@ func NewUser(uname, paswd, secret string) (Account, error) {
 	return account, nil
 }
 
+// IsStaff reports whether the account works the desk, i.e. may manage the
+// catalogue and circulation on behalf of any patron.
+func (t *Account) IsStaff() bool {
+	return t.Role == domain.RoleAdmin || t.Role == domain.RoleLibrarian
+}
+
+func (t *Account) HasTwoFactor() bool {
+	return t.TOTPEnabledAt != nil
+}
+
 func (t *Account) VerifyPassword(plainPaswd string) bool {
 	return util.VerifyPasswordHash(t.Password, plainPaswd)
 }
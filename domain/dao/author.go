@@ -6,12 +6,12 @@ import (
 )
 
 type Author struct {
-	ID        uint 				 `gorm:"primarykey"`
+	ID        uint               `gorm:"primarykey"`
 	Fullname  string             `gorm:"size:56;not null;"`
-	Gender    *domain.TypeGender `gorm:"type:enum('f','m');"`
+	Gender    *domain.TypeGender `gorm:"size:1;check:chk_authors_gender,gender IN ('f','m');"`
 	BirthDate *time.Time
 }
 
-func (Author) TableName() string {	
+func (Author) TableName() string {
 	return "authors"
-}
\ No newline at end of file
+}
@@ -4,14 +4,20 @@ import "gorm.io/gorm"
 
 type Book struct {
 	gorm.Model
-	Title 			string 		`gorm:"size:56;not null;"`
-	Subtitle 		string 		`gorm:"size:64;not null;"`
-	AuthorID 		uint 		`gorm:"not null;"`
-	BookAuthor  	*Author		`gorm:"foreignKey:AuthorID;"`
-	PublisherID 	uint 		`gorm:"not null;"`
-	BookPublisher   *Publisher	`gorm:"foreignKey:PublisherID;"`
+	Title           string            `gorm:"size:56;not null;"`
+	Subtitle        string            `gorm:"size:64;not null;"`
+	Contributors    []BookContributor `gorm:"foreignKey:BookID;"`
+	Categories      []Category        `gorm:"many2many:book_categories;"`
+	PublisherID     uint              `gorm:"not null;"`
+	BookPublisher   *Publisher        `gorm:"foreignKey:PublisherID;"`
+	ISBN            *string           `gorm:"column:isbn;size:13;uniqueIndex;"` // ISBN-13
+	Edition         string            `gorm:"size:32;not null;"`
+	PublicationYear *int
+	Language        string `gorm:"size:2;not null;"` // ISO 639-1
+	Pages           *int
+	Description     string `gorm:"type:text;not null;"`
 }
 
 func (Book) TableName() string {
 	return "books"
-}
\ No newline at end of file
+}
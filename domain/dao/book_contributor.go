@@ -0,0 +1,18 @@
+package dao
+
+import "base-gin/domain"
+
+// BookContributor links a book to one of its authors in a role. An author
+// may hold several roles on the same book. Position orders the contributors
+// of a book as they are credited, starting at 1.
+type BookContributor struct {
+	BookID   uint                       `gorm:"primaryKey;autoIncrement:false;"`
+	AuthorID uint                       `gorm:"primaryKey;autoIncrement:false;index;"`
+	Author   *Author                    `gorm:"foreignKey:AuthorID;"`
+	Role     domain.TypeContributorRole `gorm:"primaryKey;size:16;"`
+	Position int                        `gorm:"not null;"`
+}
+
+func (BookContributor) TableName() string {
+	return "book_contributors"
+}
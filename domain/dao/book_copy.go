@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BookCopy is a physical item on the shelf. A Book may own many copies and
+// borrowings are always made against a single copy.
+type BookCopy struct {
+	gorm.Model
+	BookID          uint                     `gorm:"not null;index;"`
+	Book            *Book                    `gorm:"foreignKey:BookID;"`
+	Barcode         string                   `gorm:"size:32;not null;uniqueIndex;"`
+	ShelfLocation   string                   `gorm:"size:32;"`
+	Condition       domain.TypeCopyCondition `gorm:"size:16;not null;"`
+	AcquisitionDate *time.Time
+	Status          domain.TypeCopyStatus `gorm:"size:16;not null;index;"`
+}
+
+func (BookCopy) TableName() string {
+	return "book_copies"
+}
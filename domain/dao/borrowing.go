@@ -3,15 +3,32 @@ package dao
 import "time"
 
 type Borrowing struct {
-	ID				uint		`gorm:"primarykey"`
-	BorrowDate 		*time.Time
-	ReturnDate 		*time.Time
-	BookID 			uint 		`gorm:"not null;"`
-	BorrowedBook 	*Book		`gorm:"foreignKey:BookID;"`
-	PersonID 		uint		`gorm:"not null;"`
-	BorrowerPerson 	*Person		`gorm:"foreignKey:PersonID;"`
+	ID             uint `gorm:"primarykey"`
+	BorrowDate     *time.Time
+	ReturnDate     *time.Time
+	DueDate        *time.Time `gorm:"index;"`
+	RenewalCount   int        `gorm:"not null;default:0;"`
+	BookID         uint       `gorm:"not null;"`
+	BorrowedBook   *Book      `gorm:"foreignKey:BookID;"`
+	BookCopyID     uint       `gorm:"not null;index;"`
+	BorrowedCopy   *BookCopy  `gorm:"foreignKey:BookCopyID;"`
+	PersonID       uint       `gorm:"not null;"`
+	BorrowerPerson *Person    `gorm:"foreignKey:PersonID;"`
+	ReturnedByID   *uint
+	ReturnedBy     *Account `gorm:"foreignKey:ReturnedByID;"`
 }
 
 func (Borrowing) TableName() string {
 	return "borrowings"
-}
\ No newline at end of file
+}
+
+// IsOverdue reports whether the borrowing is still open after its due date.
+func (t *Borrowing) IsOverdue(now time.Time) bool {
+	return t.ReturnDate == nil && t.DueDate != nil && now.After(*t.DueDate)
+}
+
+// CirculationPolicy holds the rules applied when a borrowing is returned.
+type CirculationPolicy struct {
+	Fine       FinePolicy
+	HoldPickup time.Duration
+}
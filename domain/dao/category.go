@@ -0,0 +1,26 @@
+package dao
+
+// Category is a subject in the catalogue's classification tree. Code is the
+// class number in the scheme the library uses, such as Dewey "005.133" or
+// UDC "004.43"; it is unique across the tree.
+type Category struct {
+	ID       uint      `gorm:"primarykey"`
+	ParentID *uint     `gorm:"index;"`
+	Parent   *Category `gorm:"foreignKey:ParentID;"`
+	Code     string    `gorm:"size:32;not null;uniqueIndex;"`
+	Name     string    `gorm:"size:64;not null;"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+// BookCategory assigns a book to a category.
+type BookCategory struct {
+	BookID     uint `gorm:"primaryKey;autoIncrement:false;"`
+	CategoryID uint `gorm:"primaryKey;autoIncrement:false;index;"`
+}
+
+func (BookCategory) TableName() string {
+	return "book_categories"
+}
@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// Fine is an entry in a person's ledger. Fines are stored as positive
+// amounts, payments and waivers as negative ones, so a person's outstanding
+// balance is the sum of their entries.
+type Fine struct {
+	ID           uint `gorm:"primarykey"`
+	CreatedAt    time.Time
+	PersonID     uint                `gorm:"not null;index;"`
+	Person       *Person             `gorm:"foreignKey:PersonID;"`
+	BorrowingID  *uint               `gorm:"index;"`
+	Borrowing    *Borrowing          `gorm:"foreignKey:BorrowingID;"`
+	WaivedFineID *uint               `gorm:"uniqueIndex;"`
+	Kind         domain.TypeFineKind `gorm:"size:16;not null;"`
+	Amount       int64               `gorm:"not null;"`
+	Note         string              `gorm:"size:255;"`
+	RecordedByID *uint
+}
+
+func (Fine) TableName() string {
+	return "fines"
+}
+
+// FinePolicy describes how late returns are charged.
+type FinePolicy struct {
+	PerDay int64
+	Max    int64
+}
+
+// Assess returns the fine owed for returning b at returnDate. Every started
+// day past the due date is charged, up to Max.
+func (p FinePolicy) Assess(b *Borrowing, returnDate time.Time) int64 {
+	if b.DueDate == nil || !returnDate.After(*b.DueDate) {
+		return 0
+	}
+
+	late := returnDate.Sub(*b.DueDate)
+	days := int64(late / (24 * time.Hour))
+	if late%(24*time.Hour) > 0 {
+		days++
+	}
+
+	amount := days * p.PerDay
+	if p.Max > 0 && amount > p.Max {
+		amount = p.Max
+	}
+
+	return amount
+}
@@ -0,0 +1,33 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// Hold is a patron's place in the queue for a book. When a copy comes back
+// the oldest waiting hold becomes ready and the copy is set aside for that
+// patron until ExpiresAt.
+type Hold struct {
+	ID         uint `gorm:"primarykey"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	BookID     uint                  `gorm:"not null;index;"`
+	Book       *Book                 `gorm:"foreignKey:BookID;"`
+	PersonID   uint                  `gorm:"not null;index;"`
+	Person     *Person               `gorm:"foreignKey:PersonID;"`
+	BookCopyID *uint                 `gorm:"index;"`
+	BookCopy   *BookCopy             `gorm:"foreignKey:BookCopyID;"`
+	Status     domain.TypeHoldStatus `gorm:"size:16;not null;index;"`
+	ReadyAt    *time.Time
+	ExpiresAt  *time.Time
+}
+
+func (Hold) TableName() string {
+	return "holds"
+}
+
+// IsActive reports whether the hold is still in the queue.
+func (t *Hold) IsActive() bool {
+	return t.Status == domain.HoldStatusWaiting || t.Status == domain.HoldStatusReady
+}
@@ -0,0 +1,19 @@
+package dao
+
+import "time"
+
+// PasswordReset is a single-use token for setting a new password without
+// knowing the current one. Only the token's SHA-256 digest is stored.
+type PasswordReset struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	AccountID uint     `gorm:"not null;index;"`
+	Account   *Account `gorm:"foreignKey:AccountID;"`
+	TokenHash string   `gorm:"size:64;not null;uniqueIndex;"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+func (PasswordReset) TableName() string {
+	return "password_resets"
+}
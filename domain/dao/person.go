@@ -12,7 +12,7 @@ type Person struct {
 	AccountID *uint              `gorm:"uniqueIndex;"`
 	Account   *Account           `gorm:"foreignKey:AccountID;"`
 	Fullname  string             `gorm:"size:56;not null;"`
-	Gender    *domain.TypeGender `gorm:"type:enum('f','m');"`
+	Gender    *domain.TypeGender `gorm:"size:1;check:chk_persons_gender,gender IN ('f','m');"`
 	BirthDate *time.Time
 }
 
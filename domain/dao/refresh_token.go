@@ -0,0 +1,21 @@
+package dao
+
+import "time"
+
+// RefreshToken records an issued refresh token by its jti. The tokens of a
+// session form one family; each refresh marks the presented token as used
+// and issues a successor in the same session.
+type RefreshToken struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	TokenID   string   `gorm:"size:36;not null;uniqueIndex;"`
+	SessionID string   `gorm:"size:36;not null;index;"`
+	Session   *Session `gorm:"foreignKey:SessionID;"`
+	AccountID uint     `gorm:"not null;index;"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,26 @@
+package dao
+
+import "time"
+
+// Session is one login of an account on a device. Every token issued from
+// that login is bound to it, so revoking the session logs the device out.
+type Session struct {
+	ID        string `gorm:"size:36;primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	AccountID uint     `gorm:"not null;index;"`
+	Account   *Account `gorm:"foreignKey:AccountID;"`
+	IPAddress string   `gorm:"size:45;"`
+	UserAgent string   `gorm:"size:255;"`
+	UserOS    string   `gorm:"size:64;"`
+	ExpiresAt time.Time
+	RevokedAt *time.Time `gorm:"index;"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+func (t *Session) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && t.ExpiresAt.After(now)
+}
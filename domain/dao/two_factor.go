@@ -0,0 +1,33 @@
+package dao
+
+import (
+	"base-gin/domain"
+	"time"
+)
+
+// RecoveryCode is a single-use substitute for a TOTP code. Only the code's
+// SHA-256 digest is stored.
+type RecoveryCode struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	AccountID uint     `gorm:"not null;index;"`
+	Account   *Account `gorm:"foreignKey:AccountID;"`
+	CodeHash  string   `gorm:"size:64;not null;"`
+	UsedAt    *time.Time
+}
+
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+// RolePolicy holds settings an admin applies to every account of a role.
+// Roles without a row use the defaults.
+type RolePolicy struct {
+	Role             domain.TypeRole `gorm:"size:16;primarykey"`
+	UpdatedAt        time.Time
+	RequireTwoFactor bool `gorm:"not null;default:false;"`
+}
+
+func (RolePolicy) TableName() string {
+	return "role_policies"
+}
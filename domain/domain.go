@@ -6,3 +6,78 @@ const (
 	GenderMale   TypeGender = "m"
 	GenderFemale TypeGender = "f"
 )
+
+// TypeContributorRole is the part an author played in making a book.
+type TypeContributorRole string
+
+const (
+	ContributorRoleAuthor      TypeContributorRole = "author"
+	ContributorRoleEditor      TypeContributorRole = "editor"
+	ContributorRoleTranslator  TypeContributorRole = "translator"
+	ContributorRoleIllustrator TypeContributorRole = "illustrator"
+)
+
+type TypeCopyStatus string
+
+const (
+	CopyStatusCirculating TypeCopyStatus = "circulating"
+	CopyStatusReference   TypeCopyStatus = "reference"
+	CopyStatusRepair      TypeCopyStatus = "repair"
+	CopyStatusLost        TypeCopyStatus = "lost"
+	CopyStatusWithdrawn   TypeCopyStatus = "withdrawn"
+)
+
+type TypeCopyCondition string
+
+const (
+	CopyConditionNew     TypeCopyCondition = "new"
+	CopyConditionGood    TypeCopyCondition = "good"
+	CopyConditionFair    TypeCopyCondition = "fair"
+	CopyConditionPoor    TypeCopyCondition = "poor"
+	CopyConditionDamaged TypeCopyCondition = "damaged"
+)
+
+type TypeFineKind string
+
+const (
+	FineKindFine    TypeFineKind = "fine"
+	FineKindPayment TypeFineKind = "payment"
+	FineKindWaiver  TypeFineKind = "waiver"
+)
+
+type TypeHoldStatus string
+
+const (
+	HoldStatusWaiting   TypeHoldStatus = "waiting"
+	HoldStatusReady     TypeHoldStatus = "ready"
+	HoldStatusFulfilled TypeHoldStatus = "fulfilled"
+	HoldStatusCancelled TypeHoldStatus = "cancelled"
+	HoldStatusExpired   TypeHoldStatus = "expired"
+)
+
+type TypeRole string
+
+const (
+	RoleAdmin     TypeRole = "admin"
+	RoleLibrarian TypeRole = "librarian"
+	RoleMember    TypeRole = "member"
+)
+
+// TypeScope limits what an API key may do. A key never grants more than its
+// owner's role allows.
+type TypeScope string
+
+const (
+	// ScopeCatalogRead is granted to read-only sync clients; catalogue
+	// reads are public, so no route requires it today.
+	ScopeCatalogRead     TypeScope = "catalog:read"
+	ScopeCatalogWrite    TypeScope = "catalog:write"
+	ScopePersonsRead     TypeScope = "persons:read"
+	ScopePersonsWrite    TypeScope = "persons:write"
+	ScopeBorrowingsRead  TypeScope = "borrowings:read"
+	ScopeBorrowingsWrite TypeScope = "borrowings:write"
+	ScopeFinesRead       TypeScope = "fines:read"
+	ScopeFinesWrite      TypeScope = "fines:write"
+	ScopeHoldsRead       TypeScope = "holds:read"
+	ScopeHoldsWrite      TypeScope = "holds:write"
+)
@@ -12,9 +12,23 @@ type AccountLoginReq struct {
 	Password string `json:"paswd" binding:"required,min=8,max=255"`
 }
 
+// AccountLoginResp carries either the token pair or, when the account owes a
+// second factor, a challenge token to present to the 2FA login endpoints.
 type AccountLoginResp struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken    string   `json:"access_token,omitempty"`
+	RefreshToken   string   `json:"refresh_token,omitempty"`
+	ChallengeToken string   `json:"challenge_token,omitempty"`
+	TwoFactorSetup bool     `json:"two_factor_setup,omitempty"`
+	RecoveryCodes  []string `json:"recovery_codes,omitempty"`
+}
+
+type AccountLoginChallengeReq struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type AccountLoginTwoFactorReq struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
 }
 
 type AccountProfileResp struct {
@@ -48,16 +62,56 @@ func (o *AccountProfileResp) FromPerson(person *dao.Person) {
 type AccountCreateReq struct {
 	Username string `json:"uname" binding:"required,max=16"`
 	Password string `json:"paswd" binding:"required,min=8,max=255"`
+	Email    string `json:"email" binding:"omitempty,email,max=128"`
 }
 
 func (o *AccountCreateReq) ToEntity() dao.Account {
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(o.Password), bcrypt.DefaultCost)
 
-
-	return dao.Account{
+	account := dao.Account{
 		Username: o.Username,
 		Password: string(hashedPassword),
+		Role:     domain.RoleMember,
+	}
+	if o.Email != "" {
+		account.Email = &o.Email
+	}
+
+	return account
+}
+
+// AccountRegisterReq signs up a member together with their person profile.
+type AccountRegisterReq struct {
+	Username     string `json:"uname" binding:"required,max=16"`
+	Password     string `json:"paswd" binding:"required,min=8,max=255"`
+	Email        string `json:"email" binding:"omitempty,email,max=128"`
+	Fullname     string `json:"fullname" binding:"required,min=4,max=56"`
+	Gender       string `json:"gender" binding:"required,oneof=m f"`
+	BirthDateStr string `json:"birth_date" binding:"required,datetime=2006-01-02"`
+}
+
+func (o *AccountRegisterReq) ToPerson() (dao.Person, error) {
+	birthDate, err := parseBirthDate(o.BirthDateStr)
+	if err != nil {
+		return dao.Person{}, err
 	}
+
+	gender := domain.GenderMale
+	if o.Gender == "f" {
+		gender = domain.GenderFemale
+	}
+
+	return dao.Person{
+		Fullname:  o.Fullname,
+		Gender:    &gender,
+		BirthDate: &birthDate,
+	}, nil
+}
+
+type AccountRegisterResp struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	PersonID uint   `json:"person_id"`
 }
 
 type AccountCreateResp struct{
@@ -68,6 +122,8 @@ type AccountCreateResp struct{
 type AccountResp struct {
 	ID uint `json:"id"`
 	Username string `json:"username"`
+	Email    *string `json:"email"`
+	Role     domain.TypeRole `json:"role"`
 	Fullname string `json:"fullname"`
 	Gender   string `json:"gender"`
 	Age      int    `json:"age"`
@@ -77,6 +133,49 @@ type AccountUpdateReq struct {
 	ID       uint   `json:"id" binding:"required"`
 	Username string `json:"uname" binding:"required,max=16"`
 	Password string `json:"paswd" binding:"required,min=8,max=255"`
-	Email    string `json:"email" binding:"required,email"`
+	Email    string `json:"email" binding:"omitempty,email,max=128"`
+}
+
+type AccountPasswordChangeReq struct {
+	CurrentPassword string `json:"current_paswd" binding:"required"`
+	NewPassword     string `json:"new_paswd" binding:"required,min=8,max=255"`
+}
+
+type AccountPasswordForgotReq struct {
+	Email string `json:"email" binding:"required,email,max=128"`
+}
+
+type AccountPasswordResetReq struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_paswd" binding:"required,min=8,max=255"`
 }
 
+type AccountRoleUpdateReq struct {
+	ID   uint            `json:"-"`
+	Role domain.TypeRole `json:"role" binding:"required,oneof=admin librarian member"`
+}
+
+type SessionResp struct {
+	ID         string    `json:"id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	UserOS     string    `json:"user_os"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+func (o *SessionResp) FromEntity(item *dao.Session) {
+	o.ID = item.ID
+	o.IPAddress = item.IPAddress
+	o.UserAgent = item.UserAgent
+	o.UserOS = item.UserOS
+	o.CreatedAt = item.CreatedAt
+	o.LastUsedAt = item.UpdatedAt
+	o.ExpiresAt = item.ExpiresAt
+}
+
+type SessionRevokeResp struct {
+	Revoked int `json:"revoked"`
+}
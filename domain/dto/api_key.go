@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"time"
+)
+
+type APIKeyCreateReq struct {
+	Name      string             `json:"name" binding:"required,max=64"`
+	Scopes    []domain.TypeScope `json:"scopes" binding:"required,min=1,dive,oneof=catalog:read catalog:write persons:read persons:write borrowings:read borrowings:write fines:read fines:write holds:read holds:write"`
+	ExpiresAt *time.Time         `json:"expires_at"`
+}
+
+type APIKeyResp struct {
+	ID         uint               `json:"id"`
+	Name       string             `json:"name"`
+	Prefix     string             `json:"prefix"`
+	Scopes     []domain.TypeScope `json:"scopes"`
+	CreatedAt  time.Time          `json:"created_at"`
+	ExpiresAt  *time.Time         `json:"expires_at"`
+	LastUsedAt *time.Time         `json:"last_used_at"`
+	LastUsedIP string             `json:"last_used_ip"`
+}
+
+func (o *APIKeyResp) FromEntity(item *dao.APIKey) {
+	o.ID = item.ID
+	o.Name = item.Name
+	o.Prefix = item.Prefix
+	o.Scopes = item.ScopeList()
+	o.CreatedAt = item.CreatedAt
+	o.ExpiresAt = item.ExpiresAt
+	o.LastUsedAt = item.LastUsedAt
+	o.LastUsedIP = item.LastUsedIP
+}
+
+// APIKeyCreateResp carries the key itself, which is not retrievable later.
+type APIKeyCreateResp struct {
+	APIKeyResp
+	Key string `json:"key"`
+}
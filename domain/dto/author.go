@@ -3,6 +3,7 @@ package dto
 import (
 	"base-gin/domain"
 	"base-gin/domain/dao"
+	"base-gin/query"
 	"time"
 )
 
@@ -50,3 +51,10 @@ type AuthorUpdateReq struct {
 	Gender    *domain.TypeGender    `json:"gender" binding:"omitempty,oneof=f m"`
 	BirthDate *time.Time 			`json:"birth_date" binding:"omitempty"`
 }
+
+// AuthorQuery is what author lists can be sorted and filtered by.
+var AuthorQuery = query.Spec{
+	"fullname":   {Column: "fullname", Type: query.TypeString, Sort: true},
+	"gender":     {Column: "gender", Type: query.TypeString, Ops: opsEqual},
+	"birth_date": {Column: "birth_date", Type: query.TypeDate, Ops: opsRange, Sort: true},
+}
@@ -1,48 +1,178 @@
 package dto
 
-import "base-gin/domain/dao"
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/query"
+)
+
+// BookContributorReq credits an author on a book. Contributors are credited
+// in the order they are listed.
+type BookContributorReq struct {
+	AuthorID uint   `json:"author_id" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=author editor translator illustrator"`
+}
+
+func toContributors(reqs []BookContributorReq) []dao.BookContributor {
+	items := make([]dao.BookContributor, len(reqs))
+	for i, req := range reqs {
+		items[i] = dao.BookContributor{
+			AuthorID: req.AuthorID,
+			Role:     domain.TypeContributorRole(req.Role),
+			Position: i + 1,
+		}
+	}
+
+	return items
+}
+
+// BookDetailReq holds the bibliographic details shared by create and update.
+// ISBN may be given as ISBN-10 or ISBN-13; it is stored as ISBN-13.
+type BookDetailReq struct {
+	ISBN            string `json:"isbn" binding:"omitempty,isbn"`
+	Edition         string `json:"edition" binding:"omitempty,max=32"`
+	PublicationYear *int   `json:"publication_year" binding:"omitempty,min=1000,max=9999"`
+	Language        string `json:"language" binding:"omitempty,len=2,alpha,lowercase"`
+	Pages           *int   `json:"pages" binding:"omitempty,min=1"`
+	Description     string `json:"description" binding:"omitempty,max=2000"`
+}
 
 type BookCreateReq struct {
-	Title       string `json:"title" binding:"required,max=56"`
-	Subtitle    string `json:"subtitle" binding:"required,max=64"`
-	AuthorID    uint   `json:"author_id" binding:"required"`
-	PublisherID uint   `json:"publisher_id" binding:"required"`
+	Title        string               `json:"title" binding:"required,max=56"`
+	Subtitle     string               `json:"subtitle" binding:"required,max=64"`
+	Contributors []BookContributorReq `json:"contributors" binding:"required,min=1,dive"`
+	PublisherID  uint                 `json:"publisher_id" binding:"required"`
+	CategoryIDs  []uint               `json:"category_ids" binding:"omitempty,dive,required"`
+	BookDetailReq
 }
 
 func (o *BookCreateReq) ToEntity() dao.Book {
 	var item dao.Book
-	item.Title = o.Title 
-	item.Subtitle = o.Subtitle 
-	item.AuthorID = o.AuthorID
+	item.Title = o.Title
+	item.Subtitle = o.Subtitle
+	item.Contributors = toContributors(o.Contributors)
 	item.PublisherID = o.PublisherID
+	if o.ISBN != "" {
+		isbn := o.ISBN
+		item.ISBN = &isbn
+	}
+	item.Edition = o.Edition
+	item.PublicationYear = o.PublicationYear
+	item.Language = o.Language
+	item.Pages = o.Pages
+	item.Description = o.Description
+	item.Categories = make([]dao.Category, len(o.CategoryIDs))
+	for i, id := range o.CategoryIDs {
+		item.Categories[i].ID = id
+	}
 
 	return item
 }
 
+type BookContributorResp struct {
+	AuthorID int                        `json:"author_id"`
+	Fullname string                     `json:"fullname"`
+	Role     domain.TypeContributorRole `json:"role"`
+}
+
+// BookCategoryResp is a category the book is assigned to. Path holds the
+// names from the root of the tree down to the category.
+type BookCategoryResp struct {
+	ID   int      `json:"id"`
+	Code string   `json:"code"`
+	Name string   `json:"name"`
+	Path []string `json:"path"`
+}
+
 type BookResp struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title"`
-	Subtitle  string `json:"subtitle"`
-	Author    string `json:"author"`
-	Publisher string `json:"publisher"`
+	ID              int                   `json:"id"`
+	Title           string                `json:"title"`
+	Subtitle        string                `json:"subtitle"`
+	Contributors    []BookContributorResp `json:"contributors"`
+	Publisher       string                `json:"publisher"`
+	ISBN            *string               `json:"isbn"`
+	Edition         string                `json:"edition"`
+	PublicationYear *int                  `json:"publication_year"`
+	Language        string                `json:"language"`
+	Pages           *int                  `json:"pages"`
+	Description     string                `json:"description"`
+	Categories      []BookCategoryResp    `json:"categories"`
+	TotalCopies     int                   `json:"total_copies"`
+	AvailableCopies int                   `json:"available_copies"`
 }
 
 func (o *BookResp) FromEntity(item *dao.Book) {
 	o.ID = int(item.ID)
 	o.Title = item.Title
 	o.Subtitle = item.Subtitle
-	if item.BookAuthor != nil {
-        o.Author = item.BookAuthor.Fullname
-    }
-    if item.BookPublisher != nil {
-        o.Publisher = item.BookPublisher.Name
-    }
+	o.Contributors = make([]BookContributorResp, len(item.Contributors))
+	for i, contributor := range item.Contributors {
+		o.Contributors[i].AuthorID = int(contributor.AuthorID)
+		o.Contributors[i].Role = contributor.Role
+		if contributor.Author != nil {
+			o.Contributors[i].Fullname = contributor.Author.Fullname
+		}
+	}
+	if item.BookPublisher != nil {
+		o.Publisher = item.BookPublisher.Name
+	}
+	o.ISBN = item.ISBN
+	o.Edition = item.Edition
+	o.PublicationYear = item.PublicationYear
+	o.Language = item.Language
+	o.Pages = item.Pages
+	o.Description = item.Description
+	o.Categories = make([]BookCategoryResp, len(item.Categories))
+	for i, category := range item.Categories {
+		o.Categories[i].ID = int(category.ID)
+		o.Categories[i].Code = category.Code
+		o.Categories[i].Name = category.Name
+	}
+}
+
+func (o *BookResp) SetCopyCount(total, available int64) {
+	o.TotalCopies = int(total)
+	o.AvailableCopies = int(available)
 }
 
 type BookUpdateReq struct {
-	ID          uint   `json:"-"`
-	Title       string `json:"title" binding:"required,max=56"`
-	Subtitle    string `json:"subtitle" binding:"required,max=64"`
-	AuthorID    uint   `json:"author_id" binding:"required"`
-	PublisherID uint   `json:"publisher_id" binding:"required"`
+	ID           uint                 `json:"-"`
+	Title        string               `json:"title" binding:"required,max=56"`
+	Subtitle     string               `json:"subtitle" binding:"required,max=64"`
+	Contributors []BookContributorReq `json:"contributors" binding:"required,min=1,dive"`
+	PublisherID  uint                 `json:"publisher_id" binding:"required"`
+	CategoryIDs  []uint               `json:"category_ids" binding:"omitempty,dive,required"`
+	BookDetailReq
+}
+
+// ToContributors returns the contributors that replace the book's current
+// ones.
+func (o *BookUpdateReq) ToContributors() []dao.BookContributor {
+	return toContributors(o.Contributors)
+}
+
+// BookQuery is what book lists can be sorted and filtered by.
+var BookQuery = query.Spec{
+	"title":            {Column: "books.title", Type: query.TypeString, Sort: true},
+	"created_at":       {Column: "books.created_at", Type: query.TypeDate, Ops: opsRange, Sort: true},
+	"publication_year": {Column: "books.publication_year", Type: query.TypeInt, Ops: opsRange, Sort: true},
+	"language":         {Column: "books.language", Type: query.TypeString, Ops: opsEqual, Sort: true},
+	"publisher_id":     {Column: "books.publisher_id", Type: query.TypeInt, Ops: opsEqual},
+	"author_id": {
+		Column:  "books.id",
+		Type:    query.TypeInt,
+		Ops:     opsEqual,
+		Through: &query.Through{Table: "book_contributors", Key: "book_id", Column: "author_id"},
+	},
+}
+
+// BookFilter is a book list request, optionally narrowed to an ISBN or a
+// category.
+type BookFilter struct {
+	Filter
+	ISBN       string `form:"isbn" binding:"omitempty,isbn"`
+	CategoryID uint   `form:"category_id"`
+	// CategoryIDs is CategoryID and all of its descendants, filled in by
+	// the service.
+	CategoryIDs []uint `form:"-"`
 }
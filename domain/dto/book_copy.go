@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/query"
+	"time"
+)
+
+type BookCopyCreateReq struct {
+	BookID          uint       `json:"-"`
+	Barcode         string     `json:"barcode" binding:"required,max=32"`
+	ShelfLocation   string     `json:"shelf_location" binding:"omitempty,max=32"`
+	Condition       string     `json:"condition" binding:"required,oneof=new good fair poor damaged"`
+	AcquisitionDate *time.Time `json:"acquisition_date" binding:"omitempty"`
+	Status          string     `json:"status" binding:"omitempty,oneof=circulating reference repair lost withdrawn"`
+}
+
+func (o *BookCopyCreateReq) ToEntity() dao.BookCopy {
+	status := domain.TypeCopyStatus(o.Status)
+	if status == "" {
+		status = domain.CopyStatusCirculating
+	}
+
+	return dao.BookCopy{
+		BookID:          o.BookID,
+		Barcode:         o.Barcode,
+		ShelfLocation:   o.ShelfLocation,
+		Condition:       domain.TypeCopyCondition(o.Condition),
+		AcquisitionDate: o.AcquisitionDate,
+		Status:          status,
+	}
+}
+
+type BookCopyResp struct {
+	ID              int                      `json:"id"`
+	BookID          int                      `json:"book_id"`
+	Barcode         string                   `json:"barcode"`
+	ShelfLocation   string                   `json:"shelf_location"`
+	Condition       domain.TypeCopyCondition `json:"condition"`
+	AcquisitionDate *time.Time               `json:"acquisition_date"`
+	Status          domain.TypeCopyStatus    `json:"status"`
+	OnLoan          bool                     `json:"on_loan"`
+}
+
+func (o *BookCopyResp) FromEntity(item *dao.BookCopy) {
+	o.ID = int(item.ID)
+	o.BookID = int(item.BookID)
+	o.Barcode = item.Barcode
+	o.ShelfLocation = item.ShelfLocation
+	o.Condition = item.Condition
+	o.AcquisitionDate = item.AcquisitionDate
+	o.Status = item.Status
+}
+
+type BookCopyUpdateReq struct {
+	ID              uint       `json:"-"`
+	BookID          uint       `json:"-"`
+	Barcode         string     `json:"barcode" binding:"required,max=32"`
+	ShelfLocation   string     `json:"shelf_location" binding:"omitempty,max=32"`
+	Condition       string     `json:"condition" binding:"required,oneof=new good fair poor damaged"`
+	AcquisitionDate *time.Time `json:"acquisition_date" binding:"omitempty"`
+	Status          string     `json:"status" binding:"required,oneof=circulating reference repair lost withdrawn"`
+}
+
+// BookCopyQuery is what lists of copies can be sorted and filtered by.
+var BookCopyQuery = query.Spec{
+	"barcode":          {Column: "barcode", Type: query.TypeString, Sort: true},
+	"shelf_location":   {Column: "shelf_location", Type: query.TypeString, Ops: opsEqual, Sort: true},
+	"condition":        {Column: "condition", Type: query.TypeString, Ops: opsEqual},
+	"status":           {Column: "status", Type: query.TypeString, Ops: opsEqual, Sort: true},
+	"acquisition_date": {Column: "acquisition_date", Type: query.TypeDate, Ops: opsRange, Sort: true},
+}
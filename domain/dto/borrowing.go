@@ -2,31 +2,61 @@ package dto
 
 import (
 	"base-gin/domain/dao"
+	"base-gin/query"
 	"time"
 )
 
+// BorrowingCreateReq lends a copy from now on. The borrow date is stamped
+// by the server and a loan is only closed through a return.
 type BorrowingCreateReq struct {
-	BorrowDate 	*time.Time `json:"borrow_date" binding:"omitempty"`
-	ReturnDate 	*time.Time `json:"return_date" binding:"omitempty"`
-	BookID    	uint   `json:"book_id" binding:"required"`
-	PersonID 	uint   `json:"person_id" binding:"required"`
+	BookCopyID uint `json:"book_copy_id" binding:"required"`
+	PersonID   uint `json:"person_id" binding:"required"`
 }
 
 func (o *BorrowingCreateReq) ToEntity() dao.Borrowing {
 	var item dao.Borrowing
-	item.BorrowDate = o.BorrowDate 
-	item.ReturnDate = o.ReturnDate 
-	item.BookID = o.BookID
+	item.BookCopyID = o.BookCopyID
 	item.PersonID = o.PersonID
 
 	return item
 }
 
+// BorrowingQuery is what borrowing lists can be sorted and filtered by.
+// returned tells whether the book has been returned.
+var BorrowingQuery = query.Spec{
+	"borrow_date":  {Column: "borrowings.borrow_date", Type: query.TypeDate, Ops: opsRange, Sort: true},
+	"due_date":     {Column: "borrowings.due_date", Type: query.TypeDate, Ops: opsRange, Sort: true},
+	"return_date":  {Column: "borrowings.return_date", Type: query.TypeDate, Ops: opsRange, Sort: true},
+	"returned":     {Column: "borrowings.return_date", Type: query.TypePresence, Ops: opsIs},
+	"book_id":      {Column: "borrowings.book_id", Type: query.TypeInt, Ops: opsEqual},
+	"book_copy_id": {Column: "borrowings.book_copy_id", Type: query.TypeInt, Ops: opsEqual},
+}
+
+// OverdueQuery is what the overdue list can be sorted and filtered by. It
+// holds unreturned loans only, so it has no return filters.
+var OverdueQuery = query.Spec{
+	"borrow_date":  BorrowingQuery["borrow_date"],
+	"due_date":     BorrowingQuery["due_date"],
+	"book_id":      BorrowingQuery["book_id"],
+	"book_copy_id": BorrowingQuery["book_copy_id"],
+}
+
+type BorrowingFilter struct {
+	Filter
+	PersonID uint `form:"person_id" binding:"omitempty"`
+}
+
 type BorrowingResp struct {
 	ID 				int 		`json:"id"`
 	BorrowDate 		*time.Time 	`json:"borrow_date"`
 	ReturnDate 		*time.Time 	`json:"return_date"`
+	DueDate 		*time.Time 	`json:"due_date"`
+	RenewalCount 	int 		`json:"renewal_count"`
+	Overdue 		bool 		`json:"overdue"`
+	ReturnedByID 	*uint 		`json:"returned_by_id"`
 	BorrowedBook   	string		`json:"borrowed_book"`
+	Barcode 		string		`json:"barcode"`
+	PersonID 		uint 		`json:"person_id"`
 	BorrowerPerson 	string  	`json:"borrower_person"`
 }
 
@@ -34,18 +64,18 @@ func (o *BorrowingResp) FromEntity(item *dao.Borrowing) {
 	o.ID = int(item.ID)
 	o.BorrowDate = item.BorrowDate
 	o.ReturnDate = item.ReturnDate
+	o.DueDate = item.DueDate
+	o.RenewalCount = item.RenewalCount
+	o.Overdue = item.IsOverdue(time.Now())
+	o.ReturnedByID = item.ReturnedByID
+	o.PersonID = item.PersonID
 	if item.BorrowedBook != nil {
         o.BorrowedBook = item.BorrowedBook.Title
     }
+    if item.BorrowedCopy != nil {
+        o.Barcode = item.BorrowedCopy.Barcode
+    }
     if item.BorrowerPerson != nil {
         o.BorrowerPerson = item.BorrowerPerson.Fullname
     }
 }
-
-type BorrowingUpdateReq struct {
-	ID 			uint 		`json:"-"`
-	BorrowDate 	*time.Time 	`json:"borrow_date" binding:"omitempty"`
-	ReturnDate 	*time.Time 	`json:"return_date" binding:"omitempty"`
-	BookID    	uint   		`json:"book_id" binding:"required"`
-	PersonID 	uint    	`json:"person_id" binding:"required"`
-}
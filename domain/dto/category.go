@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/query"
+)
+
+type CategoryCreateReq struct {
+	ParentID *uint  `json:"parent_id" binding:"omitempty"`
+	Code     string `json:"code" binding:"required,max=32"`
+	Name     string `json:"name" binding:"required,max=64"`
+}
+
+func (o *CategoryCreateReq) ToEntity() dao.Category {
+	return dao.Category{
+		ParentID: o.ParentID,
+		Code:     o.Code,
+		Name:     o.Name,
+	}
+}
+
+type CategoryResp struct {
+	ID       int      `json:"id"`
+	ParentID *uint    `json:"parent_id"`
+	Code     string   `json:"code"`
+	Name     string   `json:"name"`
+	Path     []string `json:"path"` // names from the root down to this category
+}
+
+func (o *CategoryResp) FromEntity(item *dao.Category) {
+	o.ID = int(item.ID)
+	o.ParentID = item.ParentID
+	o.Code = item.Code
+	o.Name = item.Name
+}
+
+type CategoryUpdateReq struct {
+	ID       uint   `json:"-"`
+	ParentID *uint  `json:"parent_id" binding:"omitempty"`
+	Code     string `json:"code" binding:"required,max=32"`
+	Name     string `json:"name" binding:"required,max=64"`
+}
+
+// CategoryQuery is what category lists can be sorted and filtered by.
+// has_parent false keeps the roots of the tree.
+var CategoryQuery = query.Spec{
+	"code":       {Column: "code", Type: query.TypeString, Sort: true},
+	"name":       {Column: "name", Type: query.TypeString, Sort: true},
+	"parent_id":  {Column: "parent_id", Type: query.TypeInt, Ops: opsEqual},
+	"has_parent": {Column: "parent_id", Type: query.TypePresence, Ops: opsIs},
+}
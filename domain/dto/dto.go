@@ -1,5 +1,7 @@
 package dto
 
+import "base-gin/query"
+
 type SuccessResponse[T any] struct {
 	Success bool   `json:"success" binding:"default:true" example:"true"`
 	Message string `json:"message"`
@@ -28,4 +30,24 @@ type Filter struct {
 	Keyword string `form:"q" binding:"omitempty"`
 	Start   int    `form:"s" binding:"omitempty,min=0"`
 	Limit   int    `form:"l" binding:"omitempty,min=1"`
+	// Query is the sort and field filters of the request, parsed against
+	// the spec of the list.
+	Query query.Params `form:"-"`
+}
+
+func (o *Filter) ListFilter() *Filter {
+	return o
 }
+
+// ListQuery is a list request: a Filter, possibly with parameters of its
+// own.
+type ListQuery interface {
+	ListFilter() *Filter
+}
+
+// Operators of the spec fields.
+var (
+	opsEqual = []query.Op{query.OpEq, query.OpIn}
+	opsRange = []query.Op{query.OpEq, query.OpGt, query.OpGte, query.OpLt, query.OpLte}
+	opsIs    = []query.Op{query.OpEq}
+)
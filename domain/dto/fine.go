@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/query"
+	"time"
+)
+
+type FineResp struct {
+	ID           int                 `json:"id"`
+	Kind         domain.TypeFineKind `json:"kind"`
+	Amount       int64               `json:"amount"`
+	BorrowingID  *uint               `json:"borrowing_id"`
+	WaivedFineID *uint               `json:"waived_fine_id,omitempty"`
+	Note         string              `json:"note"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+func (o *FineResp) FromEntity(item *dao.Fine) {
+	o.ID = int(item.ID)
+	o.Kind = item.Kind
+	o.Amount = item.Amount
+	o.BorrowingID = item.BorrowingID
+	o.WaivedFineID = item.WaivedFineID
+	o.Note = item.Note
+	o.CreatedAt = item.CreatedAt
+}
+
+type FineBalanceResp struct {
+	PersonID int        `json:"person_id"`
+	Balance  int64      `json:"balance"`
+	Entries  []FineResp `json:"entries"`
+}
+
+type FinePaymentReq struct {
+	PersonID uint   `json:"-"`
+	Amount   int64  `json:"amount" binding:"required,min=1"`
+	Note     string `json:"note" binding:"omitempty,max=255"`
+}
+
+// ToEntity returns the payment as a ledger entry. Payments reduce the
+// balance, so the amount is stored negated.
+func (o *FinePaymentReq) ToEntity() dao.Fine {
+	return dao.Fine{
+		PersonID: o.PersonID,
+		Kind:     domain.FineKindPayment,
+		Amount:   -o.Amount,
+		Note:     o.Note,
+	}
+}
+
+type FineWaiveReq struct {
+	Note string `json:"note" binding:"omitempty,max=255"`
+}
+
+// FineQuery is what fine ledgers can be sorted and filtered by.
+var FineQuery = query.Spec{
+	"kind":         {Column: "kind", Type: query.TypeString, Ops: opsEqual},
+	"amount":       {Column: "amount", Type: query.TypeInt, Ops: opsRange, Sort: true},
+	"borrowing_id": {Column: "borrowing_id", Type: query.TypeInt, Ops: opsEqual},
+	"created_at":   {Column: "created_at", Type: query.TypeDate, Ops: opsRange, Sort: true},
+}
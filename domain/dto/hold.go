@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"time"
+)
+
+type HoldCreateReq struct {
+	BookID   uint `json:"-"`
+	PersonID uint `json:"person_id" binding:"required"`
+}
+
+func (o *HoldCreateReq) ToEntity() dao.Hold {
+	return dao.Hold{
+		BookID:   o.BookID,
+		PersonID: o.PersonID,
+	}
+}
+
+type HoldResp struct {
+	ID         int                   `json:"id"`
+	BookID     int                   `json:"book_id"`
+	PersonID   int                   `json:"person_id"`
+	Person     string                `json:"person"`
+	Status     domain.TypeHoldStatus `json:"status"`
+	Position   int                   `json:"position"`
+	BookCopyID *uint                 `json:"book_copy_id"`
+	ReadyAt    *time.Time            `json:"ready_at"`
+	ExpiresAt  *time.Time            `json:"expires_at"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
+
+func (o *HoldResp) FromEntity(item *dao.Hold) {
+	o.ID = int(item.ID)
+	o.BookID = int(item.BookID)
+	o.PersonID = int(item.PersonID)
+	if item.Person != nil {
+		o.Person = item.Person.Fullname
+	}
+	o.Status = item.Status
+	o.BookCopyID = item.BookCopyID
+	o.ReadyAt = item.ReadyAt
+	o.ExpiresAt = item.ExpiresAt
+	o.CreatedAt = item.CreatedAt
+}
@@ -3,9 +3,19 @@ package dto
 import (
 	"base-gin/domain"
 	"base-gin/domain/dao"
+	"base-gin/query"
 	"time"
 )
 
+// PersonQuery is what person lists can be sorted and filtered by.
+var PersonQuery = query.Spec{
+	"fullname":    {Column: "fullname", Type: query.TypeString, Sort: true},
+	"gender":      {Column: "gender", Type: query.TypeString, Ops: opsEqual},
+	"birth_date":  {Column: "birth_date", Type: query.TypeDate, Ops: opsRange, Sort: true},
+	"created_at":  {Column: "created_at", Type: query.TypeDate, Ops: opsRange, Sort: true},
+	"has_account": {Column: "account_id", Type: query.TypePresence, Ops: opsIs},
+}
+
 type PersonDetailResp struct {
 	ID       int    `json:"id"`
 	Fullname string `json:"fullname"`
@@ -54,6 +64,11 @@ func (o *PersonUpdateReq) GetBirthDate() (time.Time, error) {
 	return time.Parse("2006-01-02", o.BirthDateStr)
 }
 
+type PersonAccountLinkReq struct {
+	ID        uint `json:"-"`
+	AccountID uint `json:"account_id" binding:"required"`
+}
+
 type PersonCreateReq struct {
 	Fullname string `json:"fullname" binding:"required,min=4,max=56"`
 	Gender   string `json:"gender" binding:"required,oneof=m f"`
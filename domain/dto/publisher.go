@@ -1,6 +1,9 @@
 package dto
 
-import "base-gin/domain/dao"
+import (
+	"base-gin/domain/dao"
+	"base-gin/query"
+)
 
 type PublisherCreateReq struct {
 	Name string `json:"name" binding:"required,min=2,max=48"`
@@ -32,3 +35,10 @@ type PublisherUpdateReq struct {
 	Name string `json:"name" binding:"required,min=2,max=48"`
 	City string `json:"city" binding:"required,max=32"`
 }
+
+// PublisherQuery is what publisher lists can be sorted and filtered by.
+var PublisherQuery = query.Spec{
+	"name":       {Column: "name", Type: query.TypeString, Sort: true},
+	"city":       {Column: "city", Type: query.TypeString, Ops: opsEqual, Sort: true},
+	"created_at": {Column: "created_at", Type: query.TypeDate, Ops: opsRange, Sort: true},
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "base-gin/search"
+
+type SearchReq struct {
+	Query string `form:"q" binding:"required,max=100"`
+	Type  string `form:"type" binding:"omitempty,oneof=book author publisher"`
+	Limit int    `form:"l" binding:"omitempty,min=1,max=100"`
+}
+
+// SearchResp is one result. Highlights holds the fields that matched as
+// HTML, every match wrapped in <em>.
+type SearchResp struct {
+	Type       string            `json:"type"`
+	ID         int               `json:"id"`
+	Title      string            `json:"title"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+func (o *SearchResp) FromHit(hit *search.Hit) {
+	o.Type = string(hit.Kind)
+	o.ID = int(hit.ID)
+	o.Title = hit.Label
+	o.Score = hit.Score
+	o.Highlights = hit.Highlights
+}
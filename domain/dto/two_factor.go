@@ -0,0 +1,25 @@
+package dto
+
+import "base-gin/domain"
+
+type TwoFactorSetupResp struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type TwoFactorEnableReq struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type TwoFactorDisableReq struct {
+	Password string `json:"paswd" binding:"required"`
+}
+
+type TwoFactorRecoveryResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RoleTwoFactorReq struct {
+	Role     domain.TypeRole `json:"-"`
+	Required *bool           `json:"required" binding:"required"`
+}
@@ -7,12 +7,49 @@ import (
 )
 
 var (
-	ErrBearerTokenInvalid = errors.New("format token bearer tidak sesuai")
-	ErrDataNotFound       = errors.New("data tidak ditemukan")
-	ErrDateParsing        = errors.New("periksa input tanggal")
-	ErrUserConflict       = errors.New("akun pengguna sudah terdaftar")
-	ErrUserNotFound       = errors.New("akun tidak ditemukan")
-	ErrUserLoginFailed    = errors.New("username/password salah")
+	ErrBearerTokenInvalid   = errors.New("format token bearer tidak sesuai")
+	ErrBookUnavailable      = errors.New("buku sedang dipinjam")
+	ErrBookOnHold           = errors.New("buku sedang disiapkan untuk pemesan lain")
+	ErrBorrowingOverdue     = errors.New("peminjaman sudah melewati jatuh tempo")
+	ErrBorrowingReturned    = errors.New("buku sudah dikembalikan")
+	ErrRenewalLimit         = errors.New("batas perpanjangan peminjaman sudah tercapai")
+	ErrResetTokenInvalid    = errors.New("token atur ulang kata sandi tidak valid atau kedaluwarsa")
+	ErrSessionRevoked       = errors.New("sesi sudah berakhir, silakan login kembali")
+	ErrDataNotFound         = errors.New("data tidak ditemukan")
+	ErrDateParsing          = errors.New("periksa input tanggal")
+	ErrFineBalanceTooHigh   = errors.New("denda belum dilunasi melebihi batas")
+	ErrFineNotWaivable      = errors.New("denda tidak dapat dihapuskan")
+	ErrForbidden            = errors.New("akses ditolak")
+	ErrHoldExists           = errors.New("pemesanan untuk buku ini sudah ada")
+	ErrHoldNotActive        = errors.New("pemesanan sudah tidak aktif")
+	ErrHoldNotRequired      = errors.New("masih ada eksemplar yang tersedia")
+	ErrHoldPending          = errors.New("buku sedang dipesan anggota lain")
+	ErrPaymentTooLarge      = errors.New("pembayaran melebihi saldo denda")
+	ErrRefreshTokenInvalid  = errors.New("token refresh tidak valid")
+	ErrRefreshTokenReused   = errors.New("token refresh sudah pernah digunakan")
+	ErrUserConflict         = errors.New("akun pengguna sudah terdaftar")
+	ErrUserNotFound         = errors.New("akun tidak ditemukan")
+	ErrUserLoginFailed      = errors.New("username/password salah")
+	ErrTwoFactorCodeInvalid = errors.New("kode verifikasi salah")
+	ErrTwoFactorEnabled     = errors.New("verifikasi dua langkah sudah aktif")
+	ErrTwoFactorNotSetup    = errors.New("verifikasi dua langkah belum disiapkan")
+	ErrTwoFactorRequired    = errors.New("verifikasi dua langkah wajib untuk peran akun ini")
+	ErrChallengeInvalid     = errors.New("sesi login tidak valid atau kedaluwarsa")
+	ErrAPIKeyInvalid        = errors.New("kunci API tidak valid")
+	ErrAPIKeyScope          = errors.New("kunci API tidak memiliki izin untuk aksi ini")
+	ErrAPIKeyExpiry         = errors.New("tanggal kedaluwarsa harus di masa depan")
+	ErrPersonLinked         = errors.New("data orang sudah terhubung ke akun")
+	ErrPersonNotLinked      = errors.New("data orang belum terhubung ke akun")
+	ErrAccountLinked        = errors.New("akun sudah terhubung ke data orang lain")
+	ErrPasswordMismatch     = errors.New("kata sandi saat ini salah")
+	ErrAuthorNotFound       = errors.New("penulis tidak ditemukan")
+	ErrCategoryNotFound     = errors.New("kategori tidak ditemukan")
+	ErrCategoryParent       = errors.New("kategori induk tidak ditemukan")
+	ErrCategoryConflict     = errors.New("kode klasifikasi sudah digunakan kategori lain")
+	ErrCategoryCycle        = errors.New("kategori tidak boleh berada di bawah dirinya sendiri")
+	ErrCategoryHasChildren  = errors.New("kategori masih memiliki subkategori")
+	ErrISBNConflict         = errors.New("ISBN sudah terdaftar untuk buku lain")
+	ErrContributorDuplicate = errors.New("penulis tidak boleh dicantumkan dua kali dengan peran yang sama")
 )
 
 func LogError(err error, message string) {
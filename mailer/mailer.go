@@ -0,0 +1,83 @@
+package mailer
+
+import (
+	"base-gin/config"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email. The log and file mailers are meant for
+// local use; production deployments plug in one backed by a real provider.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New returns the mailer selected by MAIL_DRIVER.
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.Mail.Driver {
+	case DriverLog:
+		return &LogMailer{from: cfg.Mail.From}, nil
+	case DriverFile:
+		return &FileMailer{from: cfg.Mail.From, dir: cfg.Mail.FileDir}, nil
+	default:
+		return nil, fmt.Errorf("mailer: unknown driver %q", cfg.Mail.Driver)
+	}
+}
+
+// LogMailer records in the application log that a message was sent. The
+// body is left out as it may hold secrets such as password-reset tokens;
+// use the file mailer to read messages locally.
+type LogMailer struct {
+	from string
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	log.Info().
+		Str("from", m.from).
+		Str("to", msg.To).
+		Str("subject", msg.Subject).
+		Msg("Mailer.Send")
+
+	return nil
+}
+
+// FileMailer writes every message to its own file in dir.
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString())
+
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0o600)
+}
@@ -1,14 +1,15 @@
 package main
 
 import (
+	"base-gin/app"
 	"base-gin/config"
 	_ "base-gin/docs"
-	"base-gin/repository"
-	"base-gin/rest"
+	"base-gin/migrations"
 	"base-gin/server"
-	"base-gin/service"
 	"base-gin/storage"
+	"os"
 
+	"github.com/rs/zerolog/log"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -30,22 +31,38 @@ import (
 //	@name						Authorization
 //	@description				Bearer auth containing JWT
 
+//	@securityDefinitions.apiKey	APIKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				API key for machine clients, limited to its scopes
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
 
 	cfg := config.NewConfig()
-	storage.InitDB(cfg)
-	repository.SetupRepositories()
-	service.SetupServices(&cfg)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
 
-	app := server.Init(&cfg, repository.GetAccountRepo())
-	rest.SetupRestHandlers(app)
+	db := storage.NewDB(cfg)
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load migrations")
+	}
+	if err := migrator.Check(); err != nil {
+		log.Fatal().Err(err).Msg("Refusing to start")
+	}
+
+	application, err := app.New(&cfg, db)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to build the application")
+	}
 
 	// Swagger
 	if cfg.App.Mode == "debug" {
-		app.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+		application.Engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	}
 
-	server.Serve(app)
+	server.Serve(application.Engine)
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"base-gin/config"
+	"base-gin/migrations"
+	"base-gin/storage"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const migrateUsage = `usage: migrate <command>
+
+commands:
+  up             apply every pending migration
+  down [n]       roll back the latest n migrations (default 1)
+  status         list migrations and when they were applied
+  create <name>  add an empty up/down pair per dialect to ` + migrations.Dir
+
+// runMigrate handles the `migrate` subcommand and returns the exit code.
+func runMigrate(cfg config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+
+		paths, err := migrations.Create(migrations.Dir, args[1])
+		for _, path := range paths {
+			fmt.Println("created", path)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	migrator, err := migrations.New(storage.NewDB(cfg))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up()
+		for _, item := range done {
+			fmt.Printf("applied %04d_%s\n", item.Version, item.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(done) == 0 {
+			fmt.Println("nothing to apply")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+
+		done, err := migrator.Down(steps)
+		for _, item := range done {
+			fmt.Printf("rolled back %04d_%s\n", item.Version, item.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "status":
+		items, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, item := range items {
+			applied := "pending"
+			if item.AppliedAt != nil {
+				applied = item.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", item.Version, item.Name, applied)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
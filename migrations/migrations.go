@@ -0,0 +1,386 @@
+// Package migrations versions the database schema. Each migration is a pair
+// of SQL files named <version>_<name>.up.sql and .down.sql, written once per
+// supported dialect under sql/<dialect>/, and is embedded in the binary.
+// Every dialect carries the same versions. Applied versions are tracked in
+// schema_migrations.
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Dir is where `migrate create` writes new files, relative to the
+// repository root. It holds one directory per dialect.
+const Dir = "migrations/sql"
+
+// Dialects are the database dialects migrations are written for, named as
+// gorm reports them.
+var Dialects = []string{"mysql", "sqlite", "postgres"}
+
+var (
+	ErrMigrationInvalid = errors.New("berkas migrasi tidak valid")
+	ErrSchemaBehind     = errors.New("skema database belum diperbarui, jalankan `migrate up`")
+	ErrDialectUnknown   = errors.New("dialek database tidak didukung migrasi")
+
+	//go:embed sql/*/*.sql
+	files embed.FS
+
+	fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration is a row of the tracking table.
+type SchemaMigration struct {
+	Version   uint   `gorm:"primarykey;autoIncrement:false"`
+	Name      string `gorm:"size:128;not null;"`
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New loads the migrations written for the dialect of db.
+func New(db *gorm.DB) (*Migrator, error) {
+	dialect := db.Dialector.Name()
+	if !isDialect(dialect) {
+		return nil, fmt.Errorf("%w: %s", ErrDialectUnknown, dialect)
+	}
+
+	migrations, err := load(files, path.Join("sql", dialect))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, name := range entries {
+		m := fileNamePattern.FindStringSubmatch(path.Base(name))
+		if m == nil {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationInvalid, name)
+		}
+
+		version, _ := strconv.ParseUint(m[1], 10, 32)
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		item, ok := byVersion[uint(version)]
+		if !ok {
+			item = &Migration{Version: uint(version), Name: m[2]}
+			byVersion[uint(version)] = item
+		} else if item.Name != m[2] {
+			return nil, fmt.Errorf("%w: version %d is used twice", ErrMigrationInvalid, version)
+		}
+
+		if m[3] == "up" {
+			item.Up = string(content)
+		} else {
+			item.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, item := range byVersion {
+		if item.Up == "" || item.Down == "" {
+			return nil, fmt.Errorf("%w: version %d needs both up and down", ErrMigrationInvalid, item.Version)
+		}
+		migrations = append(migrations, *item)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func (m *Migrator) applied() (map[uint]SchemaMigration, error) {
+	if err := m.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// Up applies every pending migration in order and returns those applied.
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, item := range m.migrations {
+		if _, ok := applied[item.Version]; ok {
+			continue
+		}
+
+		err := m.run(item.Up, func(tx *gorm.DB) error {
+			return tx.Create(&SchemaMigration{
+				Version:   item.Version,
+				Name:      item.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migrate up %d_%s: %w", item.Version, item.Name, err)
+		}
+		done = append(done, item)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the latest steps applied migrations and returns those
+// rolled back.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		item := m.migrations[i]
+		if _, ok := applied[item.Version]; !ok {
+			continue
+		}
+
+		err := m.run(item.Down, func(tx *gorm.DB) error {
+			return tx.Delete(&SchemaMigration{}, item.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migrate down %d_%s: %w", item.Version, item.Name, err)
+		}
+		done = append(done, item)
+	}
+
+	return done, nil
+}
+
+// run executes the statements of a migration file and records it. MySQL
+// commits DDL implicitly, so a failing file may be left half applied there;
+// keep each file small enough to fix by hand.
+//
+// SQLite can only change most of a table by rebuilding it, which its
+// foreign keys forbid. There the file runs on one connection with foreign
+// keys switched off, and they are checked before the commit instead.
+func (m *Migrator) run(script string, record func(tx *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		conn = conn.Session(&gorm.Session{})
+		sqlite := conn.Dialector.Name() == "sqlite"
+		if sqlite {
+			restore, err := disableForeignKeys(conn)
+			if err != nil {
+				return err
+			}
+			defer restore()
+		}
+
+		return conn.Transaction(func(tx *gorm.DB) error {
+			for _, stmt := range splitStatements(script) {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return err
+				}
+			}
+
+			if sqlite {
+				if err := checkForeignKeys(tx); err != nil {
+					return err
+				}
+			}
+
+			return record(tx)
+		})
+	})
+}
+
+// disableForeignKeys switches SQLite foreign key enforcement off for conn
+// and returns a func restoring the previous setting.
+func disableForeignKeys(conn *gorm.DB) (func(), error) {
+	var enabled int
+	if err := conn.Raw("PRAGMA foreign_keys").Scan(&enabled).Error; err != nil {
+		return nil, err
+	}
+	if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+		return nil, err
+	}
+
+	return func() {
+		conn.Exec(fmt.Sprintf("PRAGMA foreign_keys = %d", enabled))
+	}, nil
+}
+
+// checkForeignKeys fails when a row refers to a missing parent row.
+func checkForeignKeys(tx *gorm.DB) error {
+	var violations []struct {
+		Table  string
+		Parent string
+	}
+	if err := tx.Raw("PRAGMA foreign_key_check").Scan(&violations).Error; err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		v := violations[0]
+		return fmt.Errorf("foreign key check failed: %s refers to a missing %s row", v.Table, v.Parent)
+	}
+
+	return nil
+}
+
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Status, len(m.migrations))
+	for i, item := range m.migrations {
+		items[i].Migration = item
+		if row, ok := applied[item.Version]; ok {
+			appliedAt := row.AppliedAt
+			items[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return items, nil
+}
+
+// Check returns ErrSchemaBehind when a migration embedded in the binary has
+// not been applied yet.
+func (m *Migrator) Check() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, item := range m.migrations {
+		if _, ok := applied[item.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%04d_%s", item.Version, item.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaBehind, strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// Create writes an empty up/down pair for the next version into the
+// directory of every dialect under dir and returns their paths.
+func Create(dir, name string) ([]string, error) {
+	if !regexp.MustCompile(`^\w+$`).MatchString(name) {
+		return nil, fmt.Errorf("%w: name may only contain letters, digits and _", ErrMigrationInvalid)
+	}
+
+	var next uint = 1
+	for _, dialect := range Dialects {
+		existing, err := load(os.DirFS(filepath.Join(dir, dialect)), ".")
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 && existing[len(existing)-1].Version >= next {
+			next = existing[len(existing)-1].Version + 1
+		}
+	}
+
+	var paths []string
+	for _, dialect := range Dialects {
+		base := filepath.Join(dir, dialect, fmt.Sprintf("%04d_%s", next, name))
+		up, down := base+".up.sql", base+".down.sql"
+		if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+			return paths, err
+		}
+		if err := os.WriteFile(down, []byte("-- revert "+name+"\n"), 0o644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, up, down)
+	}
+
+	return paths, nil
+}
+
+func isDialect(name string) bool {
+	for _, dialect := range Dialects {
+		if dialect == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitStatements splits a script on semicolons ending a line and drops
+// comment-only lines. A statement with semicolons of its own, such as a
+// trigger, is kept on one line.
+func splitStatements(script string) []string {
+	var stmts []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if s := strings.TrimSpace(current.String()); s != "" {
+		stmts = append(stmts, s)
+	}
+
+	return stmts
+}
@@ -0,0 +1,30 @@
+CREATE TABLE accounts (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    username varchar(16) NOT NULL,
+    password varchar(255) NOT NULL,
+    role varchar(16) NOT NULL DEFAULT 'member',
+    email varchar(128) NULL,
+    totp_secret varchar(255) NULL,
+    totp_enabled_at datetime(3) NULL,
+    totp_last_step bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    UNIQUE INDEX user_pass (username, password),
+    UNIQUE INDEX idx_accounts_email (email)
+);
+
+CREATE TABLE persons (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    account_id bigint unsigned NULL,
+    fullname varchar(56) NOT NULL,
+    gender enum('f','m') NULL,
+    birth_date datetime(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_persons_account_id (account_id),
+    INDEX idx_persons_deleted_at (deleted_at),
+    CONSTRAINT fk_persons_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
@@ -0,0 +1,53 @@
+CREATE TABLE publishers (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    name varchar(48) NOT NULL,
+    city varchar(32) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_publishers_name (name),
+    INDEX idx_publishers_deleted_at (deleted_at)
+);
+
+CREATE TABLE authors (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    fullname varchar(56) NOT NULL,
+    gender enum('f','m') NULL,
+    birth_date datetime(3) NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE books (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    title varchar(56) NOT NULL,
+    subtitle varchar(64) NOT NULL,
+    author_id bigint unsigned NOT NULL,
+    publisher_id bigint unsigned NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_books_deleted_at (deleted_at),
+    CONSTRAINT fk_books_book_author FOREIGN KEY (author_id) REFERENCES authors (id),
+    CONSTRAINT fk_books_book_publisher FOREIGN KEY (publisher_id) REFERENCES publishers (id)
+);
+
+CREATE TABLE book_copies (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    book_id bigint unsigned NOT NULL,
+    barcode varchar(32) NOT NULL,
+    shelf_location varchar(32) NULL,
+    `condition` varchar(16) NOT NULL,
+    acquisition_date datetime(3) NULL,
+    status varchar(16) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_book_copies_deleted_at (deleted_at),
+    INDEX idx_book_copies_book_id (book_id),
+    UNIQUE INDEX idx_book_copies_barcode (barcode),
+    INDEX idx_book_copies_status (status),
+    CONSTRAINT fk_book_copies_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
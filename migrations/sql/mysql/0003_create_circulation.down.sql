@@ -0,0 +1,3 @@
+DROP TABLE holds;
+DROP TABLE fines;
+DROP TABLE borrowings;
@@ -0,0 +1,56 @@
+CREATE TABLE borrowings (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    borrow_date datetime(3) NULL,
+    return_date datetime(3) NULL,
+    due_date datetime(3) NULL,
+    renewal_count bigint NOT NULL DEFAULT 0,
+    book_id bigint unsigned NOT NULL,
+    book_copy_id bigint unsigned NOT NULL,
+    person_id bigint unsigned NOT NULL,
+    returned_by_id bigint unsigned NULL,
+    PRIMARY KEY (id),
+    INDEX idx_borrowings_due_date (due_date),
+    INDEX idx_borrowings_book_copy_id (book_copy_id),
+    CONSTRAINT fk_borrowings_borrowed_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_borrowings_borrowed_copy FOREIGN KEY (book_copy_id) REFERENCES book_copies (id),
+    CONSTRAINT fk_borrowings_borrower_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_borrowings_returned_by FOREIGN KEY (returned_by_id) REFERENCES accounts (id)
+);
+
+CREATE TABLE fines (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    person_id bigint unsigned NOT NULL,
+    borrowing_id bigint unsigned NULL,
+    waived_fine_id bigint unsigned NULL,
+    kind varchar(16) NOT NULL,
+    amount bigint NOT NULL,
+    note varchar(255) NULL,
+    recorded_by_id bigint unsigned NULL,
+    PRIMARY KEY (id),
+    INDEX idx_fines_person_id (person_id),
+    INDEX idx_fines_borrowing_id (borrowing_id),
+    UNIQUE INDEX idx_fines_waived_fine_id (waived_fine_id),
+    CONSTRAINT fk_fines_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_fines_borrowing FOREIGN KEY (borrowing_id) REFERENCES borrowings (id)
+);
+
+CREATE TABLE holds (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    book_id bigint unsigned NOT NULL,
+    person_id bigint unsigned NOT NULL,
+    book_copy_id bigint unsigned NULL,
+    status varchar(16) NOT NULL,
+    ready_at datetime(3) NULL,
+    expires_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_holds_book_id (book_id),
+    INDEX idx_holds_person_id (person_id),
+    INDEX idx_holds_book_copy_id (book_copy_id),
+    INDEX idx_holds_status (status),
+    CONSTRAINT fk_holds_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_holds_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_holds_book_copy FOREIGN KEY (book_copy_id) REFERENCES book_copies (id)
+);
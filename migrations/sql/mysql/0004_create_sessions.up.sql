@@ -0,0 +1,43 @@
+CREATE TABLE sessions (
+    id varchar(36) NOT NULL,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    account_id bigint unsigned NOT NULL,
+    ip_address varchar(45) NULL,
+    user_agent varchar(255) NULL,
+    user_os varchar(64) NULL,
+    expires_at datetime(3) NULL,
+    revoked_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_sessions_account_id (account_id),
+    INDEX idx_sessions_revoked_at (revoked_at),
+    CONSTRAINT fk_sessions_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+
+CREATE TABLE refresh_tokens (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    token_id varchar(36) NOT NULL,
+    session_id varchar(36) NOT NULL,
+    account_id bigint unsigned NOT NULL,
+    expires_at datetime(3) NULL,
+    used_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_refresh_tokens_token_id (token_id),
+    INDEX idx_refresh_tokens_session_id (session_id),
+    INDEX idx_refresh_tokens_account_id (account_id),
+    CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (session_id) REFERENCES sessions (id)
+);
+
+CREATE TABLE password_resets (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    account_id bigint unsigned NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at datetime(3) NULL,
+    used_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_password_resets_account_id (account_id),
+    UNIQUE INDEX idx_password_resets_token_hash (token_hash),
+    CONSTRAINT fk_password_resets_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
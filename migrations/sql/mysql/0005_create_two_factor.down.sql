@@ -0,0 +1,2 @@
+DROP TABLE role_policies;
+DROP TABLE recovery_codes;
@@ -0,0 +1,17 @@
+CREATE TABLE recovery_codes (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    account_id bigint unsigned NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_recovery_codes_account_id (account_id),
+    CONSTRAINT fk_recovery_codes_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+
+CREATE TABLE role_policies (
+    role varchar(16) NOT NULL,
+    updated_at datetime(3) NULL,
+    require_two_factor boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (role)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE api_keys (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    account_id bigint unsigned NOT NULL,
+    name varchar(64) NOT NULL,
+    prefix varchar(16) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    scopes varchar(255) NOT NULL,
+    expires_at datetime(3) NULL,
+    last_used_at datetime(3) NULL,
+    last_used_ip varchar(45) NULL,
+    revoked_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_api_keys_account_id (account_id),
+    UNIQUE INDEX idx_api_keys_key_hash (key_hash),
+    CONSTRAINT fk_api_keys_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
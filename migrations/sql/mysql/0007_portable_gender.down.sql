@@ -0,0 +1,7 @@
+ALTER TABLE authors
+    DROP CHECK chk_authors_gender,
+    MODIFY gender enum('f','m') NULL;
+
+ALTER TABLE persons
+    DROP CHECK chk_persons_gender,
+    MODIFY gender enum('f','m') NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE persons
+    MODIFY gender varchar(1) NULL,
+    ADD CONSTRAINT chk_persons_gender CHECK (gender IN ('f','m'));
+
+ALTER TABLE authors
+    MODIFY gender varchar(1) NULL,
+    ADD CONSTRAINT chk_authors_gender CHECK (gender IN ('f','m'));
@@ -0,0 +1,17 @@
+CREATE TABLE book_contributors (
+    book_id bigint unsigned NOT NULL,
+    author_id bigint unsigned NOT NULL,
+    role varchar(16) NOT NULL,
+    position bigint NOT NULL,
+    PRIMARY KEY (book_id, author_id, role),
+    INDEX idx_book_contributors_author_id (author_id),
+    CONSTRAINT fk_books_contributors FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_contributors_author FOREIGN KEY (author_id) REFERENCES authors (id)
+);
+
+-- every book keeps its single author as its first contributor
+INSERT INTO book_contributors (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 1 FROM books;
+
+ALTER TABLE books DROP FOREIGN KEY fk_books_book_author;
+ALTER TABLE books DROP COLUMN author_id;
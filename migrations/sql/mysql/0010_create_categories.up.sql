@@ -0,0 +1,19 @@
+CREATE TABLE categories (
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    parent_id bigint unsigned NULL,
+    code varchar(32) NOT NULL,
+    name varchar(64) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_categories_code (code),
+    INDEX idx_categories_parent_id (parent_id),
+    CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+
+CREATE TABLE book_categories (
+    book_id bigint unsigned NOT NULL,
+    category_id bigint unsigned NOT NULL,
+    PRIMARY KEY (book_id, category_id),
+    INDEX idx_book_categories_category_id (category_id),
+    CONSTRAINT fk_book_categories_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
@@ -0,0 +1,4 @@
+DROP INDEX idx_publishers_name_fts ON publishers;
+DROP INDEX idx_authors_fullname_fts ON authors;
+DROP INDEX idx_books_subtitle_fts ON books;
+DROP INDEX idx_books_title_fts ON books;
@@ -0,0 +1,5 @@
+-- InnoDB builds one FULLTEXT index per statement
+CREATE FULLTEXT INDEX idx_books_title_fts ON books (title);
+CREATE FULLTEXT INDEX idx_books_subtitle_fts ON books (subtitle);
+CREATE FULLTEXT INDEX idx_authors_fullname_fts ON authors (fullname);
+CREATE FULLTEXT INDEX idx_publishers_name_fts ON publishers (name);
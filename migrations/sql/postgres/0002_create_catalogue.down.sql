@@ -0,0 +1,4 @@
+DROP TABLE book_copies;
+DROP TABLE books;
+DROP TABLE authors;
+DROP TABLE publishers;
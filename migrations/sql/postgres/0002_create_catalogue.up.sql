@@ -0,0 +1,53 @@
+CREATE TABLE publishers (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    updated_at timestamptz NULL,
+    deleted_at timestamptz NULL,
+    name varchar(48) NOT NULL,
+    city varchar(32) NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_publishers_name ON publishers (name);
+CREATE INDEX idx_publishers_deleted_at ON publishers (deleted_at);
+
+CREATE TABLE authors (
+    id bigserial NOT NULL,
+    fullname varchar(56) NOT NULL,
+    gender varchar(1) NULL CONSTRAINT chk_authors_gender CHECK (gender IN ('f','m')),
+    birth_date timestamptz NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE books (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    updated_at timestamptz NULL,
+    deleted_at timestamptz NULL,
+    title varchar(56) NOT NULL,
+    subtitle varchar(64) NOT NULL,
+    author_id bigint NOT NULL,
+    publisher_id bigint NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_books_book_author FOREIGN KEY (author_id) REFERENCES authors (id),
+    CONSTRAINT fk_books_book_publisher FOREIGN KEY (publisher_id) REFERENCES publishers (id)
+);
+CREATE INDEX idx_books_deleted_at ON books (deleted_at);
+
+CREATE TABLE book_copies (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    updated_at timestamptz NULL,
+    deleted_at timestamptz NULL,
+    book_id bigint NOT NULL,
+    barcode varchar(32) NOT NULL,
+    shelf_location varchar(32) NULL,
+    "condition" varchar(16) NOT NULL,
+    acquisition_date timestamptz NULL,
+    status varchar(16) NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_book_copies_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+CREATE INDEX idx_book_copies_deleted_at ON book_copies (deleted_at);
+CREATE INDEX idx_book_copies_book_id ON book_copies (book_id);
+CREATE UNIQUE INDEX idx_book_copies_barcode ON book_copies (barcode);
+CREATE INDEX idx_book_copies_status ON book_copies (status);
@@ -0,0 +1,56 @@
+CREATE TABLE borrowings (
+    id bigserial NOT NULL,
+    borrow_date timestamptz NULL,
+    return_date timestamptz NULL,
+    due_date timestamptz NULL,
+    renewal_count bigint NOT NULL DEFAULT 0,
+    book_id bigint NOT NULL,
+    book_copy_id bigint NOT NULL,
+    person_id bigint NOT NULL,
+    returned_by_id bigint NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_borrowings_borrowed_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_borrowings_borrowed_copy FOREIGN KEY (book_copy_id) REFERENCES book_copies (id),
+    CONSTRAINT fk_borrowings_borrower_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_borrowings_returned_by FOREIGN KEY (returned_by_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_borrowings_due_date ON borrowings (due_date);
+CREATE INDEX idx_borrowings_book_copy_id ON borrowings (book_copy_id);
+
+CREATE TABLE fines (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    person_id bigint NOT NULL,
+    borrowing_id bigint NULL,
+    waived_fine_id bigint NULL,
+    kind varchar(16) NOT NULL,
+    amount bigint NOT NULL,
+    note varchar(255) NULL,
+    recorded_by_id bigint NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_fines_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_fines_borrowing FOREIGN KEY (borrowing_id) REFERENCES borrowings (id)
+);
+CREATE INDEX idx_fines_person_id ON fines (person_id);
+CREATE INDEX idx_fines_borrowing_id ON fines (borrowing_id);
+CREATE UNIQUE INDEX idx_fines_waived_fine_id ON fines (waived_fine_id);
+
+CREATE TABLE holds (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    updated_at timestamptz NULL,
+    book_id bigint NOT NULL,
+    person_id bigint NOT NULL,
+    book_copy_id bigint NULL,
+    status varchar(16) NOT NULL,
+    ready_at timestamptz NULL,
+    expires_at timestamptz NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_holds_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_holds_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_holds_book_copy FOREIGN KEY (book_copy_id) REFERENCES book_copies (id)
+);
+CREATE INDEX idx_holds_book_id ON holds (book_id);
+CREATE INDEX idx_holds_person_id ON holds (person_id);
+CREATE INDEX idx_holds_book_copy_id ON holds (book_copy_id);
+CREATE INDEX idx_holds_status ON holds (status);
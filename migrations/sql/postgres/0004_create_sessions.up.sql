@@ -0,0 +1,43 @@
+CREATE TABLE sessions (
+    id varchar(36) NOT NULL,
+    created_at timestamptz NULL,
+    updated_at timestamptz NULL,
+    account_id bigint NOT NULL,
+    ip_address varchar(45) NULL,
+    user_agent varchar(255) NULL,
+    user_os varchar(64) NULL,
+    expires_at timestamptz NULL,
+    revoked_at timestamptz NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_sessions_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_sessions_account_id ON sessions (account_id);
+CREATE INDEX idx_sessions_revoked_at ON sessions (revoked_at);
+
+CREATE TABLE refresh_tokens (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    token_id varchar(36) NOT NULL,
+    session_id varchar(36) NOT NULL,
+    account_id bigint NOT NULL,
+    expires_at timestamptz NULL,
+    used_at timestamptz NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (session_id) REFERENCES sessions (id)
+);
+CREATE UNIQUE INDEX idx_refresh_tokens_token_id ON refresh_tokens (token_id);
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE INDEX idx_refresh_tokens_account_id ON refresh_tokens (account_id);
+
+CREATE TABLE password_resets (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    account_id bigint NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamptz NULL,
+    used_at timestamptz NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_password_resets_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_password_resets_account_id ON password_resets (account_id);
+CREATE UNIQUE INDEX idx_password_resets_token_hash ON password_resets (token_hash);
@@ -0,0 +1,17 @@
+CREATE TABLE recovery_codes (
+    id bigserial NOT NULL,
+    created_at timestamptz NULL,
+    account_id bigint NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamptz NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_recovery_codes_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_recovery_codes_account_id ON recovery_codes (account_id);
+
+CREATE TABLE role_policies (
+    role varchar(16) NOT NULL,
+    updated_at timestamptz NULL,
+    require_two_factor boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (role)
+);
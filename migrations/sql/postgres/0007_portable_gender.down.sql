@@ -0,0 +1 @@
+-- nothing to revert, see the up migration
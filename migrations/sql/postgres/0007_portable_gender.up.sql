@@ -0,0 +1 @@
+-- gender is created as varchar(1) with a check constraint by 0001 and 0002
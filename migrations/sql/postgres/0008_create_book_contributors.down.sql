@@ -0,0 +1,15 @@
+ALTER TABLE books ADD COLUMN author_id bigint NULL;
+
+-- the first credited author, or the first contributor of any role
+UPDATE books SET author_id = (
+    SELECT c.author_id FROM book_contributors c
+    WHERE c.book_id = books.id
+    ORDER BY c.role <> 'author', c.position
+    LIMIT 1
+);
+
+ALTER TABLE books
+    ALTER COLUMN author_id SET NOT NULL,
+    ADD CONSTRAINT fk_books_book_author FOREIGN KEY (author_id) REFERENCES authors (id);
+
+DROP TABLE book_contributors;
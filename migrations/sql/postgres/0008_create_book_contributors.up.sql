@@ -0,0 +1,16 @@
+CREATE TABLE book_contributors (
+    book_id bigint NOT NULL,
+    author_id bigint NOT NULL,
+    role varchar(16) NOT NULL,
+    position bigint NOT NULL,
+    PRIMARY KEY (book_id, author_id, role),
+    CONSTRAINT fk_books_contributors FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_contributors_author FOREIGN KEY (author_id) REFERENCES authors (id)
+);
+CREATE INDEX idx_book_contributors_author_id ON book_contributors (author_id);
+
+-- every book keeps its single author as its first contributor
+INSERT INTO book_contributors (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 1 FROM books;
+
+ALTER TABLE books DROP COLUMN author_id;
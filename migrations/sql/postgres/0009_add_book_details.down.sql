@@ -0,0 +1,8 @@
+DROP INDEX idx_books_isbn;
+ALTER TABLE books
+    DROP COLUMN isbn,
+    DROP COLUMN edition,
+    DROP COLUMN publication_year,
+    DROP COLUMN language,
+    DROP COLUMN pages,
+    DROP COLUMN description;
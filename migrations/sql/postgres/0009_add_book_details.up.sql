@@ -0,0 +1,8 @@
+ALTER TABLE books
+    ADD COLUMN isbn varchar(13) NULL,
+    ADD COLUMN edition varchar(32) NOT NULL DEFAULT '',
+    ADD COLUMN publication_year bigint NULL,
+    ADD COLUMN language varchar(2) NOT NULL DEFAULT '',
+    ADD COLUMN pages bigint NULL,
+    ADD COLUMN description text NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn);
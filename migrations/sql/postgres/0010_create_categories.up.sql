@@ -0,0 +1,19 @@
+CREATE TABLE categories (
+    id bigserial NOT NULL,
+    parent_id bigint NULL,
+    code varchar(32) NOT NULL,
+    name varchar(64) NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+CREATE UNIQUE INDEX idx_categories_code ON categories (code);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+
+CREATE TABLE book_categories (
+    book_id bigint NOT NULL,
+    category_id bigint NOT NULL,
+    PRIMARY KEY (book_id, category_id),
+    CONSTRAINT fk_book_categories_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_categories_category FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+CREATE INDEX idx_book_categories_category_id ON book_categories (category_id);
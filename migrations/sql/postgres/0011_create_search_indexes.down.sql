@@ -0,0 +1,4 @@
+DROP INDEX idx_publishers_name_fts;
+DROP INDEX idx_authors_fullname_fts;
+DROP INDEX idx_books_subtitle_fts;
+DROP INDEX idx_books_title_fts;
@@ -0,0 +1,5 @@
+-- the search repository matches on these exact expressions
+CREATE INDEX idx_books_title_fts ON books USING gin (to_tsvector('simple', title));
+CREATE INDEX idx_books_subtitle_fts ON books USING gin (to_tsvector('simple', subtitle));
+CREATE INDEX idx_authors_fullname_fts ON authors USING gin (to_tsvector('simple', fullname));
+CREATE INDEX idx_publishers_name_fts ON publishers USING gin (to_tsvector('simple', name));
@@ -0,0 +1,28 @@
+CREATE TABLE accounts (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    updated_at datetime NULL,
+    username varchar(16) NOT NULL,
+    password varchar(255) NOT NULL,
+    role varchar(16) NOT NULL DEFAULT 'member',
+    email varchar(128) NULL,
+    totp_secret varchar(255) NULL,
+    totp_enabled_at datetime NULL,
+    totp_last_step bigint NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX user_pass ON accounts (username, password);
+CREATE UNIQUE INDEX idx_accounts_email ON accounts (email);
+
+CREATE TABLE persons (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    updated_at datetime NULL,
+    deleted_at datetime NULL,
+    account_id integer NULL,
+    fullname varchar(56) NOT NULL,
+    gender varchar(1) NULL CONSTRAINT chk_persons_gender CHECK (gender IN ('f','m')),
+    birth_date datetime NULL,
+    CONSTRAINT fk_persons_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE UNIQUE INDEX idx_persons_account_id ON persons (account_id);
+CREATE INDEX idx_persons_deleted_at ON persons (deleted_at);
@@ -0,0 +1,53 @@
+CREATE TABLE borrowings (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    borrow_date datetime NULL,
+    return_date datetime NULL,
+    due_date datetime NULL,
+    renewal_count bigint NOT NULL DEFAULT 0,
+    book_id integer NOT NULL,
+    book_copy_id integer NOT NULL,
+    person_id integer NOT NULL,
+    returned_by_id integer NULL,
+    CONSTRAINT fk_borrowings_borrowed_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_borrowings_borrowed_copy FOREIGN KEY (book_copy_id) REFERENCES book_copies (id),
+    CONSTRAINT fk_borrowings_borrower_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_borrowings_returned_by FOREIGN KEY (returned_by_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_borrowings_due_date ON borrowings (due_date);
+CREATE INDEX idx_borrowings_book_copy_id ON borrowings (book_copy_id);
+
+CREATE TABLE fines (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    person_id integer NOT NULL,
+    borrowing_id integer NULL,
+    waived_fine_id integer NULL,
+    kind varchar(16) NOT NULL,
+    amount bigint NOT NULL,
+    note varchar(255) NULL,
+    recorded_by_id integer NULL,
+    CONSTRAINT fk_fines_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_fines_borrowing FOREIGN KEY (borrowing_id) REFERENCES borrowings (id)
+);
+CREATE INDEX idx_fines_person_id ON fines (person_id);
+CREATE INDEX idx_fines_borrowing_id ON fines (borrowing_id);
+CREATE UNIQUE INDEX idx_fines_waived_fine_id ON fines (waived_fine_id);
+
+CREATE TABLE holds (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    updated_at datetime NULL,
+    book_id integer NOT NULL,
+    person_id integer NOT NULL,
+    book_copy_id integer NULL,
+    status varchar(16) NOT NULL,
+    ready_at datetime NULL,
+    expires_at datetime NULL,
+    CONSTRAINT fk_holds_book FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_holds_person FOREIGN KEY (person_id) REFERENCES persons (id),
+    CONSTRAINT fk_holds_book_copy FOREIGN KEY (book_copy_id) REFERENCES book_copies (id)
+);
+CREATE INDEX idx_holds_book_id ON holds (book_id);
+CREATE INDEX idx_holds_person_id ON holds (person_id);
+CREATE INDEX idx_holds_book_copy_id ON holds (book_copy_id);
+CREATE INDEX idx_holds_status ON holds (status);
@@ -0,0 +1,16 @@
+CREATE TABLE recovery_codes (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    account_id integer NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at datetime NULL,
+    CONSTRAINT fk_recovery_codes_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_recovery_codes_account_id ON recovery_codes (account_id);
+
+CREATE TABLE role_policies (
+    role varchar(16) NOT NULL,
+    updated_at datetime NULL,
+    require_two_factor boolean NOT NULL DEFAULT false,
+    PRIMARY KEY (role)
+);
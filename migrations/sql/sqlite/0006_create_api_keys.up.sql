@@ -0,0 +1,17 @@
+CREATE TABLE api_keys (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    updated_at datetime NULL,
+    account_id integer NOT NULL,
+    name varchar(64) NOT NULL,
+    prefix varchar(16) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    scopes varchar(255) NOT NULL,
+    expires_at datetime NULL,
+    last_used_at datetime NULL,
+    last_used_ip varchar(45) NULL,
+    revoked_at datetime NULL,
+    CONSTRAINT fk_api_keys_account FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+CREATE INDEX idx_api_keys_account_id ON api_keys (account_id);
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,26 @@
+CREATE TABLE books_new (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    updated_at datetime NULL,
+    deleted_at datetime NULL,
+    title varchar(56) NOT NULL,
+    subtitle varchar(64) NOT NULL,
+    author_id integer NOT NULL,
+    publisher_id integer NOT NULL,
+    CONSTRAINT fk_books_book_author FOREIGN KEY (author_id) REFERENCES authors (id),
+    CONSTRAINT fk_books_book_publisher FOREIGN KEY (publisher_id) REFERENCES publishers (id)
+);
+
+-- the first credited author, or the first contributor of any role
+INSERT INTO books_new (id, created_at, updated_at, deleted_at, title, subtitle, author_id, publisher_id)
+SELECT id, created_at, updated_at, deleted_at, title, subtitle, (
+    SELECT c.author_id FROM book_contributors c
+    WHERE c.book_id = books.id
+    ORDER BY c.role <> 'author', c.position
+    LIMIT 1
+), publisher_id FROM books;
+
+DROP TABLE book_contributors;
+DROP TABLE books;
+ALTER TABLE books_new RENAME TO books;
+CREATE INDEX idx_books_deleted_at ON books (deleted_at);
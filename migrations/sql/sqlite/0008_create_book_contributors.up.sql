@@ -0,0 +1,31 @@
+CREATE TABLE book_contributors (
+    book_id integer NOT NULL,
+    author_id integer NOT NULL,
+    role varchar(16) NOT NULL,
+    position integer NOT NULL,
+    PRIMARY KEY (book_id, author_id, role),
+    CONSTRAINT fk_books_contributors FOREIGN KEY (book_id) REFERENCES books (id),
+    CONSTRAINT fk_book_contributors_author FOREIGN KEY (author_id) REFERENCES authors (id)
+);
+CREATE INDEX idx_book_contributors_author_id ON book_contributors (author_id);
+
+-- every book keeps its single author as its first contributor
+INSERT INTO book_contributors (book_id, author_id, role, position)
+SELECT id, author_id, 'author', 1 FROM books;
+
+-- SQLite cannot drop a column used by a foreign key, so books is rebuilt
+CREATE TABLE books_new (
+    id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at datetime NULL,
+    updated_at datetime NULL,
+    deleted_at datetime NULL,
+    title varchar(56) NOT NULL,
+    subtitle varchar(64) NOT NULL,
+    publisher_id integer NOT NULL,
+    CONSTRAINT fk_books_book_publisher FOREIGN KEY (publisher_id) REFERENCES publishers (id)
+);
+INSERT INTO books_new (id, created_at, updated_at, deleted_at, title, subtitle, publisher_id)
+SELECT id, created_at, updated_at, deleted_at, title, subtitle, publisher_id FROM books;
+DROP TABLE books;
+ALTER TABLE books_new RENAME TO books;
+CREATE INDEX idx_books_deleted_at ON books (deleted_at);
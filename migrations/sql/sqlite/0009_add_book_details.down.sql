@@ -0,0 +1,7 @@
+DROP INDEX idx_books_isbn;
+ALTER TABLE books DROP COLUMN isbn;
+ALTER TABLE books DROP COLUMN edition;
+ALTER TABLE books DROP COLUMN publication_year;
+ALTER TABLE books DROP COLUMN language;
+ALTER TABLE books DROP COLUMN pages;
+ALTER TABLE books DROP COLUMN description;
@@ -0,0 +1,7 @@
+ALTER TABLE books ADD COLUMN isbn varchar(13) NULL;
+ALTER TABLE books ADD COLUMN edition varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN publication_year integer NULL;
+ALTER TABLE books ADD COLUMN language varchar(2) NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN pages integer NULL;
+ALTER TABLE books ADD COLUMN description text NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn);
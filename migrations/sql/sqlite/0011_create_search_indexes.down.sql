@@ -0,0 +1,17 @@
+DROP TRIGGER publishers_fts_ai;
+DROP TRIGGER publishers_fts_au;
+DROP TRIGGER publishers_fts_bd;
+DROP TRIGGER publishers_fts_bu;
+DROP TABLE publishers_fts;
+
+DROP TRIGGER authors_fts_ai;
+DROP TRIGGER authors_fts_au;
+DROP TRIGGER authors_fts_bd;
+DROP TRIGGER authors_fts_bu;
+DROP TABLE authors_fts;
+
+DROP TRIGGER books_fts_ai;
+DROP TRIGGER books_fts_au;
+DROP TRIGGER books_fts_bd;
+DROP TRIGGER books_fts_bu;
+DROP TABLE books_fts;
@@ -0,0 +1,23 @@
+-- FTS5 needs a build tag of the driver, FTS4 does not; the tables index
+-- the rows of their content table and are kept in step by the triggers,
+-- each of which stays on one line for the statement splitter
+CREATE VIRTUAL TABLE books_fts USING fts4(content="books", title, subtitle, tokenize=unicode61 "remove_diacritics=0");
+CREATE TRIGGER books_fts_bu BEFORE UPDATE ON books BEGIN DELETE FROM books_fts WHERE docid = old.id; END;
+CREATE TRIGGER books_fts_bd BEFORE DELETE ON books BEGIN DELETE FROM books_fts WHERE docid = old.id; END;
+CREATE TRIGGER books_fts_au AFTER UPDATE ON books BEGIN INSERT INTO books_fts (docid, title, subtitle) VALUES (new.id, new.title, new.subtitle); END;
+CREATE TRIGGER books_fts_ai AFTER INSERT ON books BEGIN INSERT INTO books_fts (docid, title, subtitle) VALUES (new.id, new.title, new.subtitle); END;
+INSERT INTO books_fts (books_fts) VALUES ('rebuild');
+
+CREATE VIRTUAL TABLE authors_fts USING fts4(content="authors", fullname, tokenize=unicode61 "remove_diacritics=0");
+CREATE TRIGGER authors_fts_bu BEFORE UPDATE ON authors BEGIN DELETE FROM authors_fts WHERE docid = old.id; END;
+CREATE TRIGGER authors_fts_bd BEFORE DELETE ON authors BEGIN DELETE FROM authors_fts WHERE docid = old.id; END;
+CREATE TRIGGER authors_fts_au AFTER UPDATE ON authors BEGIN INSERT INTO authors_fts (docid, fullname) VALUES (new.id, new.fullname); END;
+CREATE TRIGGER authors_fts_ai AFTER INSERT ON authors BEGIN INSERT INTO authors_fts (docid, fullname) VALUES (new.id, new.fullname); END;
+INSERT INTO authors_fts (authors_fts) VALUES ('rebuild');
+
+CREATE VIRTUAL TABLE publishers_fts USING fts4(content="publishers", name, tokenize=unicode61 "remove_diacritics=0");
+CREATE TRIGGER publishers_fts_bu BEFORE UPDATE ON publishers BEGIN DELETE FROM publishers_fts WHERE docid = old.id; END;
+CREATE TRIGGER publishers_fts_bd BEFORE DELETE ON publishers BEGIN DELETE FROM publishers_fts WHERE docid = old.id; END;
+CREATE TRIGGER publishers_fts_au AFTER UPDATE ON publishers BEGIN INSERT INTO publishers_fts (docid, name) VALUES (new.id, new.name); END;
+CREATE TRIGGER publishers_fts_ai AFTER INSERT ON publishers BEGIN INSERT INTO publishers_fts (docid, name) VALUES (new.id, new.name); END;
+INSERT INTO publishers_fts (publishers_fts) VALUES ('rebuild');
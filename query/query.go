@@ -0,0 +1,348 @@
+// Package query turns the sort and filter parameters of a list request into
+// GORM clauses. A list declares a Spec of the fields it can be sorted and
+// filtered by; anything else in the request is rejected.
+//
+// Filters take the form field=value or field[op]=value, with in taking a
+// comma-separated list. Sorting takes sort=field,-field, a leading minus
+// sorting that field in descending order.
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ParamSort is the parameter holding the sort order.
+const ParamSort = "sort"
+
+// maxInValues bounds the number of values of an in filter.
+const maxInValues = 100
+
+type Op string
+
+const (
+	OpEq  Op = "eq"
+	OpNe  Op = "ne"
+	OpGt  Op = "gt"
+	OpGte Op = "gte"
+	OpLt  Op = "lt"
+	OpLte Op = "lte"
+	OpIn  Op = "in"
+)
+
+var sqlOps = map[Op]string{
+	OpEq:  "=",
+	OpNe:  "<>",
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+	OpIn:  "IN",
+}
+
+type Type int
+
+const (
+	TypeInt Type = iota
+	TypeString
+	TypeBool
+	// TypeDate takes 2006-01-02, which covers the whole day in UTC as the
+	// dates are stored, or RFC 3339.
+	TypeDate
+	// TypePresence is a boolean telling whether Column is set, i.e. not
+	// NULL. It only takes eq.
+	TypePresence
+)
+
+// Field is a field a list can be sorted or filtered by.
+type Field struct {
+	Column string // qualified with its table
+	Type   Type
+	Ops    []Op // the filter operators allowed, none if it cannot be filtered on
+	Sort   bool
+	// Through is set for a field held by a related table: the filter then
+	// applies to the rows of that table and keeps those whose key is in
+	// Column.
+	Through *Through
+}
+
+// Through is a table relating the rows of a list to the value filtered on,
+// such as the contributors relating books to authors.
+type Through struct {
+	Table  string
+	Key    string // the column matching Field.Column
+	Column string // the column filtered on
+}
+
+// Spec is the fields of one list, keyed by their parameter name.
+type Spec map[string]Field
+
+// Condition is a filter of a request.
+type Condition struct {
+	Field Field
+	Op    Op
+	Value interface{}
+}
+
+// Order is a sort key of a request.
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// Params is the parsed sort and filters of a request. The zero value
+// filters nothing and keeps the default order.
+type Params struct {
+	Conditions []Condition
+	Orders     []Order
+}
+
+// FieldError is a parameter that was rejected.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Errors is every parameter a request was rejected for.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+
+	return "query: " + strings.Join(msgs, "; ")
+}
+
+// Parse reads the sort and filters of values. Parameters named in bound are
+// handled elsewhere and skipped; any other parameter that is not a field of
+// the spec is an error.
+func (s Spec) Parse(values url.Values, bound ...string) (Params, error) {
+	var params Params
+	var errs Errors
+
+	skip := map[string]bool{ParamSort: true}
+	for _, name := range bound {
+		skip[name] = true
+	}
+
+	if sortParam := values.Get(ParamSort); sortParam != "" {
+		for _, key := range strings.Split(sortParam, ",") {
+			name := strings.TrimSpace(key)
+			desc := strings.HasPrefix(name, "-")
+			name = strings.TrimPrefix(name, "-")
+
+			field, ok := s[name]
+			if !ok || !field.Sort {
+				errs = append(errs, FieldError{ParamSort, fmt.Sprintf("tidak dapat diurutkan berdasarkan %q", name)})
+				continue
+			}
+			params.Orders = append(params.Orders, Order{Column: field.Column, Desc: desc})
+		}
+	}
+
+	// sorted so the errors come out in a stable order
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if skip[key] {
+			continue
+		}
+
+		name, op := key, OpEq
+		if i := strings.Index(key, "["); i > 0 && strings.HasSuffix(key, "]") {
+			name, op = key[:i], Op(key[i+1:len(key)-1])
+		}
+
+		field, ok := s[name]
+		if !ok || len(field.Ops) == 0 {
+			errs = append(errs, FieldError{key, "parameter tidak dikenal"})
+			continue
+		}
+		if !field.allows(op) {
+			errs = append(errs, FieldError{key, fmt.Sprintf("operator %q tidak didukung", op)})
+			continue
+		}
+
+		for _, raw := range values[key] {
+			conds, err := field.conditions(op, raw)
+			if err != nil {
+				errs = append(errs, FieldError{key, err.Error()})
+				continue
+			}
+			params.Conditions = append(params.Conditions, conds...)
+		}
+	}
+
+	if len(errs) > 0 {
+		return Params{}, errs
+	}
+
+	return params, nil
+}
+
+func (f Field) allows(op Op) bool {
+	for _, allowed := range f.Ops {
+		if allowed == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// conditions parses raw for op. A date without a time stands for the whole
+// day, so it may become two conditions.
+func (f Field) conditions(op Op, raw string) ([]Condition, error) {
+	if op == OpIn {
+		parts := strings.Split(raw, ",")
+		if len(parts) > maxInValues {
+			return nil, fmt.Errorf("paling banyak %d nilai", maxInValues)
+		}
+
+		values := make([]interface{}, len(parts))
+		for i, part := range parts {
+			value, _, err := f.parse(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+
+		return []Condition{{Field: f, Op: OpIn, Value: values}}, nil
+	}
+
+	value, wholeDay, err := f.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !wholeDay {
+		return []Condition{{Field: f, Op: op, Value: value}}, nil
+	}
+
+	start := value.(time.Time)
+	end := start.AddDate(0, 0, 1)
+	switch op {
+	case OpEq:
+		return []Condition{{Field: f, Op: OpGte, Value: start}, {Field: f, Op: OpLt, Value: end}}, nil
+	case OpGt:
+		return []Condition{{Field: f, Op: OpGte, Value: end}}, nil
+	case OpLte:
+		return []Condition{{Field: f, Op: OpLt, Value: end}}, nil
+	default:
+		return []Condition{{Field: f, Op: op, Value: start}}, nil
+	}
+}
+
+// parse converts raw to the type of the field. wholeDay is set for a date
+// given without a time.
+func (f Field) parse(raw string) (value interface{}, wholeDay bool, err error) {
+	switch f.Type {
+	case TypeInt:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("harus berupa bilangan bulat")
+		}
+		return n, false, nil
+	case TypeBool, TypePresence:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("harus berupa true atau false")
+		}
+		return b, false, nil
+	case TypeDate:
+		if t, err := time.Parse("2006-01-02", raw); err == nil {
+			return t, true, nil
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("harus berupa tanggal dengan format YYYY-MM-DD atau RFC 3339")
+		}
+		return t, false, nil
+	default:
+		return raw, false, nil
+	}
+}
+
+// Apply adds the filters to tx.
+func (p Params) Apply(tx *gorm.DB) *gorm.DB {
+	for _, cond := range p.Conditions {
+		tx = cond.apply(tx)
+	}
+
+	return tx
+}
+
+func (c Condition) apply(tx *gorm.DB) *gorm.DB {
+	f := c.Field
+	if f.Type == TypePresence {
+		if c.Value.(bool) {
+			return tx.Where(f.Column + " IS NOT NULL")
+		}
+		return tx.Where(f.Column + " IS NULL")
+	}
+
+	// Column and the operator come from the spec, never from the request
+	expr := fmt.Sprintf("%s %s ?", f.Column, sqlOps[c.Op])
+	if f.Through == nil {
+		return tx.Where(expr, c.Value)
+	}
+
+	related := tx.Session(&gorm.Session{NewDB: true}).
+		Table(f.Through.Table).
+		Select(f.Through.Key).
+		Where(fmt.Sprintf("%s %s ?", f.Through.Column, sqlOps[c.Op]), c.Value)
+
+	return tx.Where(f.Column+" IN (?)", related)
+}
+
+// Order adds the requested sort keys to tx followed by fallback, which keeps
+// the order stable where the requested keys tie.
+func (p Params) Order(tx *gorm.DB, fallback string) *gorm.DB {
+	for _, order := range p.Orders {
+		if order.Desc {
+			tx = tx.Order(order.Column + " DESC")
+		} else {
+			tx = tx.Order(order.Column + " ASC")
+		}
+	}
+
+	return tx.Order(fallback)
+}
+
+// FormKeys returns the form parameters the fields of v, a struct or a
+// pointer to one, are bound from, including those of embedded structs.
+func FormKeys(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			keys = append(keys, FormKeys(reflect.New(field.Type).Interface())...)
+			continue
+		}
+
+		name := strings.SplitN(field.Tag.Get("form"), ",", 2)[0]
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+
+	return keys
+}
@@ -1,128 +1,136 @@
-	package repository
-
-	import (
-		"base-gin/domain/dao"
-		"base-gin/exception"
-		"base-gin/storage"
-		"errors"
-
-
-
-
-		"gorm.io/gorm"
-	)
-
-	type AccountRepository struct {
-		db *gorm.DB
-	}
-
-	func NewAccountRepository(db *gorm.DB) *AccountRepository {
-		return &AccountRepository{db: db}
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type AccountRepository interface {
+	Create(ctx context.Context, newItem *dao.Account) error
+	GetByUsername(ctx context.Context, uname string) (dao.Account, error)
+	GetByEmail(ctx context.Context, email string) (dao.Account, error)
+	UpdatePassword(ctx context.Context, id uint, passwordHash string) error
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*dao.Account, error)
+	UpdateRole(ctx context.Context, id uint, role domain.TypeRole) error
+	GetList(ctx context.Context) ([]dao.Account, error)
+	Update(ctx context.Context, newItem *dao.Account) error
+}
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepository{db: db}
+}
+
+func (r *accountRepository) Create(ctx context.Context, newItem *dao.Account) error {
+	tx := r.db.WithContext(ctx).Create(&newItem)
+	if tx.Error != nil {
+		return tx.Error
 	}
 
-	func (r *AccountRepository) Create(newItem *dao.Account) error {
-		ctx, cancelFunc := storage.NewDBContext()
-		defer cancelFunc()
+	return nil
+}
 
-		tx := r.db.WithContext(ctx).Create(&newItem)
-		if tx.Error != nil {
-			return tx.Error
+func (r *accountRepository) GetByUsername(ctx context.Context, uname string) (dao.Account, error) {
+	var item dao.Account
+	tx := r.db.WithContext(ctx).Where(dao.Account{Username: uname}).
+		First(&item)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return item, exception.ErrUserNotFound
 		}
 
-		return nil
+		return item, tx.Error
 	}
 
-	func (r *AccountRepository) GetByUsername(uname string) (dao.Account, error) {
-		ctx, cancelFunc := storage.NewDBContext()
-		defer cancelFunc()
+	return item, nil
+}
 
-		var item dao.Account
-		tx := r.db.WithContext(ctx).Where(dao.Account{Username: uname}).
-			First(&item)
-		if tx.Error != nil {
-			if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-				return item, exception.ErrUserNotFound
-			}
-
-			return item, tx.Error
+func (r *accountRepository) GetByEmail(ctx context.Context, email string) (dao.Account, error) {
+	var item dao.Account
+	tx := r.db.WithContext(ctx).Where("email = ?", email).
+		First(&item)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return item, exception.ErrUserNotFound
 		}
 
-		return item, nil
-	}
-
-	func (r *AccountRepository) Delete(id uint) error {
-		// Check if there are any records in the persons table that reference the account
-		var persons []dao.Person
-		err := r.db.Where("account_id = ?", id).Find(&persons).Error
-		if err != nil {
-			return err
-		}
-		if len(persons) > 0 {
-			// Delete the records in the persons table
-			err := r.db.Where("account_id = ?", id).Delete(&dao.Person{}).Error
-			if err != nil {
-				return err
-			}
-		}
-		// Delete the account
-		err = r.db.Where("id = ?", id).Delete(&dao.Account{}).Error
-		return err
+		return item, tx.Error
 	}
 
-	func (r *AccountRepository) GetByID(id uint) (*dao.Account, error) {
-		ctx, cancelFunc := storage.NewDBContext()
-		defer cancelFunc()
+	return item, nil
+}
 
-		var account dao.Account
-		tx := r.db.WithContext(ctx).First(&account, id)
-
-		if tx.Error != nil && tx.Error == gorm.ErrRecordNotFound {
-			return nil, nil
-		}
-
-		return &account, tx.Error
+func (r *accountRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	tx := r.db.WithContext(ctx).Model(&dao.Account{}).
+		Where("id = ?", id).
+		Update("password", passwordHash)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrUserNotFound
 	}
 
-	func (r *AccountRepository) GetByToken(token string) (*dao.Account, error) {
-		ctx, cancelFunc := storage.NewDBContext()
-		defer cancelFunc()
+	return nil
+}
 
-		var item dao.Account
-		tx := r.db.WithContext(ctx).Where(dao.Account{Token: token}).
-			First(&item)
-		if tx.Error != nil {
-			if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-				return nil, exception.ErrUserNotFound
-			}
+// Delete deletes the account row only. Rows referencing the account must
+// be deleted first; AccountService.Delete does so in one transaction.
+func (r *accountRepository) Delete(ctx context.Context, id uint) error {
+	tx := r.db.WithContext(ctx).Where("id = ?", id).Delete(&dao.Account{})
 
-			return nil, tx.Error
-		}
+	return tx.Error
+}
 
-		return &item, nil
-	}
+func (r *accountRepository) GetByID(ctx context.Context, id uint) (*dao.Account, error) {
+	var account dao.Account
+	tx := r.db.WithContext(ctx).First(&account, id)
 
-	func (r *AccountRepository) GetList() ([]dao.Account, error) {
-		ctx, cancelFunc := storage.NewDBContext()
-		defer cancelFunc()
+	if tx.Error != nil && tx.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
 
-		var accounts []dao.Account
-		tx := r.db.WithContext(ctx).Find(&accounts)
+	return &account, tx.Error
+}
 
-		return accounts, tx.Error
+func (r *accountRepository) UpdateRole(ctx context.Context, id uint, role domain.TypeRole) error {
+	tx := r.db.WithContext(ctx).Model(&dao.Account{}).
+		Where("id = ?", id).
+		Update("role", role)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrUserNotFound
 	}
 
-	func (r *AccountRepository) Update(newItem *dao.Account) error {
-		ctx, cancelFunc := storage.NewDBContext()
-		defer cancelFunc()
+	return nil
+}
 
-		tx := r.db.WithContext(ctx).Model(&dao.Account{}).
-			Where("id = ?", newItem.ID).
-			Updates(map[string]interface{}{
-				"username": newItem.Username,
-				"password": newItem.Password,
-			})
+func (r *accountRepository) GetList(ctx context.Context) ([]dao.Account, error) {
+	var accounts []dao.Account
+	tx := r.db.WithContext(ctx).Find(&accounts)
 
-		return tx.Error
-	}
+	return accounts, tx.Error
+}
+
+func (r *accountRepository) Update(ctx context.Context, newItem *dao.Account) error {
+	tx := r.db.WithContext(ctx).Model(&dao.Account{}).
+		Where("id = ?", newItem.ID).
+		Updates(map[string]interface{}{
+			"username": newItem.Username,
+			"password": newItem.Password,
+			"email":    newItem.Email,
+		})
 
-		
\ No newline at end of file
+	return tx.Error
+}
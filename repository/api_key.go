@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, newItem *dao.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (dao.APIKey, error)
+	GetList(ctx context.Context, accountID uint, now time.Time) ([]dao.APIKey, error)
+	Touch(ctx context.Context, id uint, ip string, now time.Time) error
+	Revoke(ctx context.Context, accountID, id uint, now time.Time) error
+	DeleteByAccount(ctx context.Context, accountID uint) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, newItem *dao.APIKey) error {
+	return r.db.WithContext(ctx).Create(newItem).Error
+}
+
+// GetByHash returns the key with the given digest together with its owner.
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (dao.APIKey, error) {
+	var item dao.APIKey
+	tx := r.db.WithContext(ctx).Preload("Account").
+		Where("key_hash = ?", keyHash).
+		First(&item)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return item, exception.ErrAPIKeyInvalid
+		}
+
+		return item, tx.Error
+	}
+
+	return item, nil
+}
+
+// GetList returns the account's keys which are neither revoked nor expired.
+func (r *apiKeyRepository) GetList(ctx context.Context, accountID uint, now time.Time) ([]dao.APIKey, error) {
+	var items []dao.APIKey
+	tx := r.db.WithContext(ctx).
+		Where("account_id = ? AND revoked_at IS NULL", accountID).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("created_at DESC").
+		Find(&items)
+
+	return items, tx.Error
+}
+
+// Touch records when and from where the key was last used.
+func (r *apiKeyRepository) Touch(ctx context.Context, id uint, ip string, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&dao.APIKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		}).Error
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, accountID, id uint, now time.Time) error {
+	tx := r.db.WithContext(ctx).Model(&dao.APIKey{}).
+		Where("id = ? AND account_id = ? AND revoked_at IS NULL", id, accountID).
+		Update("revoked_at", now)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// DeleteByAccount deletes every key of the account, revoked or not.
+func (r *apiKeyRepository) DeleteByAccount(ctx context.Context, accountID uint) error {
+	tx := r.db.WithContext(ctx).Where("account_id = ?", accountID).Delete(&dao.APIKey{})
+
+	return tx.Error
+}
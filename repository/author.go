@@ -4,25 +4,30 @@ import (
 	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
-	"base-gin/storage"
+	"context"
 	"errors"
 	"fmt"
 
 	"gorm.io/gorm"
 )
 
-type AuthorRepository struct {
-	db *gorm.DB
+type AuthorRepository interface {
+	Create(ctx context.Context, newItem *dao.Author) error
+	GetByID(ctx context.Context, id uint) (*dao.Author, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dao.Author, error)
+	Update(ctx context.Context, params *dto.AuthorUpdateReq) error
+	Delete(ctx context.Context, id uint) error
 }
 
-func NewAuthorRepository(db *gorm.DB) *AuthorRepository {
-	return &AuthorRepository{db: db}
+type authorRepository struct {
+	db *gorm.DB
 }
 
-func (r *AuthorRepository) Create(newItem *dao.Author) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+func NewAuthorRepository(db *gorm.DB) AuthorRepository {
+	return &authorRepository{db: db}
+}
 
+func (r *authorRepository) Create(ctx context.Context, newItem *dao.Author) error {
 	tx := r.db.WithContext(ctx).Create(&newItem)
 	if tx.Error != nil {
 		return tx.Error
@@ -31,10 +36,7 @@ func (r *AuthorRepository) Create(newItem *dao.Author) error {
 	return nil
 }
 
-func (r *AuthorRepository) GetByID(id uint) (*dao.Author, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *authorRepository) GetByID(ctx context.Context, id uint) (*dao.Author, error) {
 	var item dao.Author
 	tx := r.db.WithContext(ctx).First(&item, id)
 	if tx.Error != nil {
@@ -48,10 +50,7 @@ func (r *AuthorRepository) GetByID(id uint) (*dao.Author, error) {
 	return &item, nil
 }
 
-func (r *AuthorRepository) GetList(params *dto.Filter) ([]dao.Author, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *authorRepository) GetList(ctx context.Context, params *dto.Filter) ([]dao.Author, error) {
 	var items []dao.Author
 	tx := r.db.WithContext(ctx)
 
@@ -59,6 +58,7 @@ func (r *AuthorRepository) GetList(params *dto.Filter) ([]dao.Author, error) {
 		q := fmt.Sprintf("%%%s%%", params.Keyword)
 		tx = tx.Where("fullname LIKE ?", q)
 	}
+	tx = params.Query.Apply(tx)
 	if params.Start >= 0 {
 		tx = tx.Offset(params.Start)
 	}
@@ -66,7 +66,7 @@ func (r *AuthorRepository) GetList(params *dto.Filter) ([]dao.Author, error) {
 		tx = tx.Limit(params.Limit)
 	}
 
-	tx = tx.Order("fullname ASC").Find(&items)
+	tx = params.Query.Order(tx, "fullname ASC, id ASC").Find(&items)
 	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 		return nil, tx.Error
 	}
@@ -74,25 +74,19 @@ func (r *AuthorRepository) GetList(params *dto.Filter) ([]dao.Author, error) {
 	return items, nil
 }
 
-func (r *AuthorRepository) Update(params *dto.AuthorUpdateReq) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *authorRepository) Update(ctx context.Context, params *dto.AuthorUpdateReq) error {
 	tx := r.db.WithContext(ctx).Model(&dao.Author{}).
 		Where("id = ?", params.ID).
 		Updates(map[string]interface{}{
-			"fullname": params.Fullname,
-			"gender": params.Gender,
+			"fullname":   params.Fullname,
+			"gender":     params.Gender,
 			"birth_date": params.BirthDate,
 		})
 
 	return tx.Error
 }
 
-func (r *AuthorRepository) Delete(id uint) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *authorRepository) Delete(ctx context.Context, id uint) error {
 	tx := r.db.WithContext(ctx).Delete(&dao.Author{}, id)
 
 	return tx.Error
@@ -4,40 +4,132 @@ import (
 	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
-	"base-gin/storage"
+	"context"
 	"errors"
 	"fmt"
 
 	"gorm.io/gorm"
 )
 
-type BookRepository struct {
+type BookRepository interface {
+	Create(ctx context.Context, newItem *dao.Book) error
+	GetByID(ctx context.Context, id uint) (*dao.Book, error)
+	GetList(ctx context.Context, params *dto.BookFilter) ([]dao.Book, error)
+	GetListByAuthor(ctx context.Context, authorID uint, params *dto.Filter) ([]dao.Book, error)
+	Update(ctx context.Context, params *dto.BookUpdateReq) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type bookRepository struct {
 	db *gorm.DB
 }
 
-func NewBookRepository(db *gorm.DB) *BookRepository {
-	return &BookRepository{db: db}
+func NewBookRepository(db *gorm.DB) BookRepository {
+	return &bookRepository{db: db}
 }
 
-func (r *BookRepository) Create(newItem *dao.Book) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+// Create stores the book together with its contributors and categories.
+func (r *bookRepository) Create(ctx context.Context, newItem *dao.Book) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkAuthors(tx, newItem.Contributors); err != nil {
+			return err
+		}
 
-	tx := r.db.WithContext(ctx).Create(&newItem)
-	if tx.Error != nil {
-		return tx.Error
+		categoryIDs := make([]uint, len(newItem.Categories))
+		for i, category := range newItem.Categories {
+			categoryIDs[i] = category.ID
+		}
+
+		// categories are assigned, never created, through a book
+		if err := tx.Omit("Categories").Create(newItem).Error; err != nil {
+			return err
+		}
+
+		return setCategories(tx, newItem.ID, categoryIDs)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return exception.ErrISBNConflict
+	}
+
+	return err
+}
+
+// checkAuthors returns ErrAuthorNotFound when a contributor refers to an
+// author that does not exist.
+func checkAuthors(tx *gorm.DB, contributors []dao.BookContributor) error {
+	ids := map[uint]bool{}
+	for _, contributor := range contributors {
+		ids[contributor.AuthorID] = true
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	authorIDs := make([]uint, 0, len(ids))
+	for id := range ids {
+		authorIDs = append(authorIDs, id)
 	}
+
+	var count int64
+	err := tx.Model(&dao.Author{}).Where("id IN ?", authorIDs).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count != int64(len(authorIDs)) {
+		return exception.ErrAuthorNotFound
+	}
+
 	return nil
 }
 
-func (r *BookRepository) GetByID(id uint) (*dao.Book, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+// setCategories replaces the categories the book is assigned to. It returns
+// ErrCategoryNotFound when one of them does not exist.
+func setCategories(tx *gorm.DB, bookID uint, categoryIDs []uint) error {
+	ids := map[uint]bool{}
+	items := []dao.BookCategory{}
+	for _, id := range categoryIDs {
+		if !ids[id] {
+			ids[id] = true
+			items = append(items, dao.BookCategory{BookID: bookID, CategoryID: id})
+		}
+	}
+
+	if len(items) > 0 {
+		var count int64
+		err := tx.Model(&dao.Category{}).Where("id IN ?", categoryIDs).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count != int64(len(items)) {
+			return exception.ErrCategoryNotFound
+		}
+	}
+
+	err := tx.Where("book_id = ?", bookID).Delete(&dao.BookCategory{}).Error
+	if err != nil || len(items) == 0 {
+		return err
+	}
+
+	return tx.Create(&items).Error
+}
+
+// withContributors loads the contributors of the books being queried, in
+// credit order, and the categories they are assigned to.
+func withContributors(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Preload("Contributors", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Contributors.Author").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("code ASC")
+		})
+}
 
+func (r *bookRepository) GetByID(ctx context.Context, id uint) (*dao.Book, error) {
 	var item dao.Book
-	tx := r.db.WithContext(ctx).
+	tx := withContributors(r.db.WithContext(ctx)).
 		Joins("BookPublisher").
-		Joins("BookAuthor").
 		First(&item, id)
 	if tx.Error != nil {
 		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
@@ -48,19 +140,46 @@ func (r *BookRepository) GetByID(id uint) (*dao.Book, error) {
 	return &item, nil
 }
 
-func (r *BookRepository) GetList(params *dto.Filter) ([]dao.Book, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+func (r *bookRepository) GetList(ctx context.Context, params *dto.BookFilter) ([]dao.Book, error) {
+	tx := withContributors(r.db.WithContext(ctx)).
+		Joins("BookPublisher")
+
+	if params.ISBN != "" {
+		tx = tx.Where("books.isbn = ?", params.ISBN)
+	}
+	if len(params.CategoryIDs) > 0 {
+		assigned := r.db.WithContext(ctx).Model(&dao.BookCategory{}).
+			Select("book_id").
+			Where("category_id IN ?", params.CategoryIDs)
+		tx = tx.Where("books.id IN (?)", assigned)
+	}
+
+	return r.find(tx, &params.Filter)
+}
+
+// GetListByAuthor returns the books the author contributed to in any role.
+func (r *bookRepository) GetListByAuthor(ctx context.Context, authorID uint, params *dto.Filter) ([]dao.Book, error) {
+	contributed := r.db.WithContext(ctx).Model(&dao.BookContributor{}).
+		Select("book_id").
+		Where("author_id = ?", authorID)
 
+	tx := withContributors(r.db.WithContext(ctx)).
+		Joins("BookPublisher").
+		Where("books.id IN (?)", contributed)
+
+	return r.find(tx, params)
+}
+
+// find applies the keyword, filters, sort and paging of params to tx and
+// runs it.
+func (r *bookRepository) find(tx *gorm.DB, params *dto.Filter) ([]dao.Book, error) {
 	var items []dao.Book
-	tx := r.db.WithContext(ctx).
-	Joins("BookPublisher").
-	Joins("BookAuthor")
 
 	if params.Keyword != "" {
 		q := fmt.Sprintf("%%%s%%", params.Keyword)
 		tx = tx.Where("title LIKE ?", q)
 	}
+	tx = params.Query.Apply(tx)
 	if params.Start >= 0 {
 		tx = tx.Offset(params.Start)
 	}
@@ -68,7 +187,7 @@ func (r *BookRepository) GetList(params *dto.Filter) ([]dao.Book, error) {
 		tx = tx.Limit(params.Limit)
 	}
 
-	tx = tx.Order("title ASC").Find(&items)
+	tx = params.Query.Order(tx, "books.title ASC, books.id ASC").Find(&items)
 	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 		return nil, tx.Error
 	}
@@ -76,25 +195,69 @@ func (r *BookRepository) GetList(params *dto.Filter) ([]dao.Book, error) {
 	return items, nil
 }
 
-func (r *BookRepository) Update(params *dto.BookUpdateReq) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+// Update changes the book's details and replaces its contributors and
+// categories.
+func (r *bookRepository) Update(ctx context.Context, params *dto.BookUpdateReq) error {
+	contributors := params.ToContributors()
 
-    tx := r.db.WithContext(ctx).Model(&dao.Book{}).Where("id = ?", params.ID).Updates(map[string]interface{}{
-        "title":       	params.Title,
-        "subtitle":    	params.Subtitle,
-        "author_id":    params.AuthorID,
-        "publisher_id": params.PublisherID,
-    })
+	var isbn *string
+	if params.ISBN != "" {
+		isbn = &params.ISBN
+	}
 
-	return tx.Error
-}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// a missing or deleted book must not have its credits rewritten
+		err := tx.Select("id").First(&dao.Book{}, params.ID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := checkAuthors(tx, contributors); err != nil {
+			return err
+		}
+
+		err = tx.Model(&dao.Book{}).Where("id = ?", params.ID).Updates(map[string]interface{}{
+			"title":            params.Title,
+			"subtitle":         params.Subtitle,
+			"publisher_id":     params.PublisherID,
+			"isbn":             isbn,
+			"edition":          params.Edition,
+			"publication_year": params.PublicationYear,
+			"language":         params.Language,
+			"pages":            params.Pages,
+			"description":      params.Description,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("book_id = ?", params.ID).Delete(&dao.BookContributor{}).Error
+		if err != nil {
+			return err
+		}
 
-func (r *BookRepository) Delete(id uint) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+		for i := range contributors {
+			contributors[i].BookID = params.ID
+		}
 
+		if err := tx.Create(&contributors).Error; err != nil {
+			return err
+		}
+
+		return setCategories(tx, params.ID, params.CategoryIDs)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return exception.ErrISBNConflict
+	}
+
+	return err
+}
+
+func (r *bookRepository) Delete(ctx context.Context, id uint) error {
 	tx := r.db.WithContext(ctx).Delete(&dao.Book{}, id)
 
 	return tx.Error
-}
\ No newline at end of file
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// CopyCount holds the number of copies a book owns and how many of them can
+// currently be lent out.
+type CopyCount struct {
+	BookID    uint
+	Total     int64
+	Available int64
+}
+
+type BookCopyRepository interface {
+	Create(ctx context.Context, newItem *dao.BookCopy) error
+	GetByID(ctx context.Context, bookID, id uint) (*dao.BookCopy, error)
+	GetList(ctx context.Context, bookID uint, params *dto.Filter) ([]dao.BookCopy, error)
+	GetOnLoanIDs(ctx context.Context, copyIDs []uint) (map[uint]bool, error)
+	CountByBookIDs(ctx context.Context, bookIDs []uint) (map[uint]CopyCount, error)
+	Update(ctx context.Context, params *dto.BookCopyUpdateReq) error
+	Delete(ctx context.Context, bookID, id uint) error
+}
+
+type bookCopyRepository struct {
+	db *gorm.DB
+}
+
+func NewBookCopyRepository(db *gorm.DB) BookCopyRepository {
+	return &bookCopyRepository{db: db}
+}
+
+func (r *bookCopyRepository) Create(ctx context.Context, newItem *dao.BookCopy) error {
+	tx := r.db.WithContext(ctx).Create(&newItem)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	return nil
+}
+
+func (r *bookCopyRepository) GetByID(ctx context.Context, bookID, id uint) (*dao.BookCopy, error) {
+	var item dao.BookCopy
+	tx := r.db.WithContext(ctx).
+		Where("book_id = ?", bookID).
+		First(&item, id)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, exception.ErrUserNotFound
+		}
+
+		return nil, tx.Error
+	}
+
+	return &item, nil
+}
+
+func (r *bookCopyRepository) GetList(ctx context.Context, bookID uint, params *dto.Filter) ([]dao.BookCopy, error) {
+	var items []dao.BookCopy
+	tx := r.db.WithContext(ctx).Where("book_id = ?", bookID)
+
+	if params.Keyword != "" {
+		tx = tx.Where("barcode = ?", params.Keyword)
+	}
+	tx = params.Query.Apply(tx)
+	if params.Start >= 0 {
+		tx = tx.Offset(params.Start)
+	}
+	if params.Limit > 0 {
+		tx = tx.Limit(params.Limit)
+	}
+
+	tx = params.Query.Order(tx, "barcode ASC").Find(&items)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, tx.Error
+	}
+
+	return items, nil
+}
+
+// GetOnLoanIDs returns the subset of copyIDs that currently have an open
+// borrowing.
+func (r *bookCopyRepository) GetOnLoanIDs(ctx context.Context, copyIDs []uint) (map[uint]bool, error) {
+	onLoan := make(map[uint]bool, len(copyIDs))
+	if len(copyIDs) == 0 {
+		return onLoan, nil
+	}
+
+	var ids []uint
+	tx := r.db.WithContext(ctx).Model(&dao.Borrowing{}).
+		Where("book_copy_id IN ? AND return_date IS NULL", copyIDs).
+		Pluck("book_copy_id", &ids)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	for _, id := range ids {
+		onLoan[id] = true
+	}
+
+	return onLoan, nil
+}
+
+// CountByBookIDs returns total and available copy counts keyed by book ID.
+// A copy is available when it is circulating, has no open borrowing and is
+// not set aside for a hold.
+func (r *bookCopyRepository) CountByBookIDs(ctx context.Context, bookIDs []uint) (map[uint]CopyCount, error) {
+	counts := make(map[uint]CopyCount, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return counts, nil
+	}
+
+	var totals []CopyCount
+	tx := r.db.WithContext(ctx).Model(&dao.BookCopy{}).
+		Select("book_id, COUNT(*) AS total").
+		Where("book_id IN ?", bookIDs).
+		Group("book_id").
+		Scan(&totals)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var availables []CopyCount
+	tx = r.db.WithContext(ctx).Model(&dao.BookCopy{}).
+		Select("book_id, COUNT(*) AS available").
+		Where("book_id IN ?", bookIDs).
+		Scopes(availableCopies).
+		Group("book_id").
+		Scan(&availables)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	for _, row := range totals {
+		counts[row.BookID] = row
+	}
+	for _, row := range availables {
+		count := counts[row.BookID]
+		count.Available = row.Available
+		counts[row.BookID] = count
+	}
+
+	return counts, nil
+}
+
+func (r *bookCopyRepository) Update(ctx context.Context, params *dto.BookCopyUpdateReq) error {
+	tx := r.db.WithContext(ctx).Model(&dao.BookCopy{}).
+		Where("id = ? AND book_id = ?", params.ID, params.BookID).
+		Updates(map[string]interface{}{
+			"barcode":          params.Barcode,
+			"shelf_location":   params.ShelfLocation,
+			"condition":        params.Condition,
+			"acquisition_date": params.AcquisitionDate,
+			"status":           params.Status,
+		})
+
+	return tx.Error
+}
+
+func (r *bookCopyRepository) Delete(ctx context.Context, bookID, id uint) error {
+	tx := r.db.WithContext(ctx).
+		Where("book_id = ?", bookID).
+		Delete(&dao.BookCopy{}, id)
+
+	return tx.Error
+}
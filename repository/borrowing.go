@@ -1,27 +1,39 @@
 package repository
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
-	"base-gin/storage"
+	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type BorrowingRepository struct {
-	db *gorm.DB
+type BorrowingRepository interface {
+	Create(ctx context.Context, newItem *dao.Borrowing) error
+	CreateIfAvailable(ctx context.Context, newItem *dao.Borrowing, policy dao.CirculationPolicy) error
+	GetByID(ctx context.Context, id uint) (*dao.Borrowing, error)
+	GetList(ctx context.Context, params *dto.BorrowingFilter) ([]dao.Borrowing, error)
+	GetOverdueList(ctx context.Context, now time.Time, params *dto.Filter) ([]dao.Borrowing, error)
+	Renew(ctx context.Context, id uint, renewalCount int, dueDate time.Time) error
+	Return(ctx context.Context, id, returnedByID uint, returnDate time.Time, policy dao.CirculationPolicy) (*dao.Borrowing, error)
+	ReturnByBarcode(ctx context.Context, barcode string, returnedByID uint, returnDate time.Time, policy dao.CirculationPolicy) (*dao.Borrowing, error)
+	Delete(ctx context.Context, id uint) error
 }
 
-func NewBorrowingRepository(db *gorm.DB) *BorrowingRepository {
-	return &BorrowingRepository{db: db}
+type borrowingRepository struct {
+	db *gorm.DB
 }
 
-func (r *BorrowingRepository) Create(newItem *dao.Borrowing) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+func NewBorrowingRepository(db *gorm.DB) BorrowingRepository {
+	return &borrowingRepository{db: db}
+}
 
+func (r *borrowingRepository) Create(ctx context.Context, newItem *dao.Borrowing) error {
 	tx := r.db.WithContext(ctx).Create(&newItem)
 	if tx.Error != nil {
 		return tx.Error
@@ -29,13 +41,91 @@ func (r *BorrowingRepository) Create(newItem *dao.Borrowing) error {
 	return nil
 }
 
-func (r *BorrowingRepository) GetByID(id uint) (*dao.Borrowing, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+// CreateIfAvailable inserts newItem only when its book copy is circulating,
+// has no open borrowing and is not set aside for another patron's hold. The
+// copy row is locked for the duration of the transaction so concurrent
+// checkouts of the same copy are serialised. The borrowing's BookID is taken
+// from the copy, and the borrower's own hold on the book is fulfilled; if it
+// had another copy set aside, that copy passes to the next patron. Holds
+// that lapsed before the borrow date are expired first.
+func (r *borrowingRepository) CreateIfAvailable(ctx context.Context,
+	newItem *dao.Borrowing,
+	policy dao.CirculationPolicy,
+) error {
+	now := *newItem.BorrowDate
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := expireReadyHolds(tx, now, policy.HoldPickup); err != nil {
+			return err
+		}
+
+		var bookCopy dao.BookCopy
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&bookCopy, newItem.BookCopyID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrDataNotFound
+			}
+			return err
+		}
+		if bookCopy.Status != domain.CopyStatusCirculating {
+			return exception.ErrBookUnavailable
+		}
+
+		var openCount int64
+		err = tx.Model(&dao.Borrowing{}).
+			Where("book_copy_id = ? AND return_date IS NULL", bookCopy.ID).
+			Count(&openCount).Error
+		if err != nil {
+			return err
+		}
+		if openCount > 0 {
+			return exception.ErrBookUnavailable
+		}
+
+		var readyHold dao.Hold
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("book_copy_id = ? AND status = ?", bookCopy.ID, domain.HoldStatusReady).
+			Limit(1).
+			Find(&readyHold).Error
+		if err != nil {
+			return err
+		}
+		if readyHold.ID != 0 && readyHold.PersonID != newItem.PersonID {
+			return exception.ErrBookOnHold
+		}
+
+		var ownHolds []dao.Hold
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("book_id = ? AND person_id = ? AND status IN ?",
+				bookCopy.BookID, newItem.PersonID, activeHoldStatuses).
+			Find(&ownHolds).Error
+		if err != nil {
+			return err
+		}
+		for i := range ownHolds {
+			hold := &ownHolds[i]
+			if hold.BookCopyID != nil && *hold.BookCopyID == bookCopy.ID {
+				err = tx.Model(hold).Update("status", domain.HoldStatusFulfilled).Error
+			} else {
+				// a copy set aside for the borrower other than the one lent
+				// goes to the next patron in the queue
+				err = releaseHold(tx, hold, domain.HoldStatusFulfilled, now, policy.HoldPickup)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		newItem.BookID = bookCopy.BookID
+		return tx.Create(newItem).Error
+	})
+}
 
+func (r *borrowingRepository) GetByID(ctx context.Context, id uint) (*dao.Borrowing, error) {
 	var item dao.Borrowing
 	tx := r.db.WithContext(ctx).
 		Joins("BorrowedBook").
+		Joins("BorrowedCopy").
 		Joins("BorrowerPerson").
 		First(&item, id)
 	if tx.Error != nil {
@@ -47,14 +137,43 @@ func (r *BorrowingRepository) GetByID(id uint) (*dao.Borrowing, error) {
 	return &item, nil
 }
 
-func (r *BorrowingRepository) GetList(params *dto.Filter) ([]dao.Borrowing, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+func (r *borrowingRepository) GetList(ctx context.Context, params *dto.BorrowingFilter) ([]dao.Borrowing, error) {
+	var items []dao.Borrowing
+	tx := r.db.WithContext(ctx).
+		Joins("BorrowedBook").
+		Joins("BorrowedCopy").
+		Joins("BorrowerPerson")
+
+	if params.PersonID > 0 {
+		tx = tx.Where("borrowings.person_id = ?", params.PersonID)
+	}
+	tx = params.Query.Apply(tx)
+
+	if params.Start >= 0 {
+		tx = tx.Offset(params.Start)
+	}
+	if params.Limit > 0 {
+		tx = tx.Limit(params.Limit)
+	}
 
+	tx = params.Query.Order(tx, "borrowings.id ASC").Find(&items)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, tx.Error
+	}
+
+	return items, nil
+}
+
+// GetOverdueList returns open borrowings whose due date is before now,
+// oldest due date first unless params sorts them otherwise.
+func (r *borrowingRepository) GetOverdueList(ctx context.Context, now time.Time, params *dto.Filter) ([]dao.Borrowing, error) {
 	var items []dao.Borrowing
 	tx := r.db.WithContext(ctx).
-    Joins("BorrowedBook").
-    Joins("BorrowerPerson")
+		Joins("BorrowedBook").
+		Joins("BorrowedCopy").
+		Joins("BorrowerPerson").
+		Where("borrowings.return_date IS NULL AND borrowings.due_date < ?", now)
+	tx = params.Query.Apply(tx)
 
 	if params.Start >= 0 {
 		tx = tx.Offset(params.Start)
@@ -63,7 +182,7 @@ func (r *BorrowingRepository) GetList(params *dto.Filter) ([]dao.Borrowing, erro
 		tx = tx.Limit(params.Limit)
 	}
 
-	tx = tx.Find(&items)
+	tx = params.Query.Order(tx, "borrowings.due_date ASC, borrowings.id ASC").Find(&items)
 	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 		return nil, tx.Error
 	}
@@ -71,25 +190,148 @@ func (r *BorrowingRepository) GetList(params *dto.Filter) ([]dao.Borrowing, erro
 	return items, nil
 }
 
-func (r *BorrowingRepository) Update(params *dto.BorrowingUpdateReq) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+// Renew moves the due date of an open borrowing and increments its renewal
+// counter. The update only applies when the counter still equals
+// renewalCount, so two concurrent renewals cannot both succeed.
+func (r *borrowingRepository) Renew(ctx context.Context, id uint, renewalCount int, dueDate time.Time) error {
+	tx := r.db.WithContext(ctx).Model(&dao.Borrowing{}).
+		Where("id = ? AND renewal_count = ? AND return_date IS NULL", id, renewalCount).
+		Updates(map[string]interface{}{
+			"due_date":      dueDate,
+			"renewal_count": gorm.Expr("renewal_count + 1"),
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrRenewalLimit
+	}
+
+	return nil
+}
 
-    tx := r.db.WithContext(ctx).Joins("BorrowedBook").Joins("BorrowerPerson").Model(&dao.Borrowing{}).Where("id = ?", params.ID).Updates(map[string]interface{}{
-        "borrow_date":	params.BorrowDate,
-        "return_date": 	params.ReturnDate,
-        "book_id":    params.BookID,
-        "person_id": params.PersonID,
-    })
+// Return closes the open borrowing with the given ID, stamping returnDate and
+// the account that processed it. Under policy a fine is accrued when the book
+// is late and the copy is set aside for the next hold in the queue.
+// Borrowings that are already closed are refused with
+// exception.ErrBorrowingReturned.
+func (r *borrowingRepository) Return(ctx context.Context,
+	id, returnedByID uint,
+	returnDate time.Time,
+	policy dao.CirculationPolicy,
+) (*dao.Borrowing, error) {
+	var item dao.Borrowing
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := expireReadyHolds(tx, returnDate, policy.HoldPickup); err != nil {
+			return err
+		}
 
-	return tx.Error
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&item, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrUserNotFound
+			}
+			return err
+		}
+
+		return closeBorrowing(tx, &item, returnedByID, returnDate, policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// ReturnByBarcode closes the open borrowing of the book copy with the given
+// barcode. It is meant for the circulation desk where only the item in hand
+// is known.
+func (r *borrowingRepository) ReturnByBarcode(ctx context.Context,
+	barcode string,
+	returnedByID uint,
+	returnDate time.Time,
+	policy dao.CirculationPolicy,
+) (*dao.Borrowing, error) {
+	var item dao.Borrowing
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := expireReadyHolds(tx, returnDate, policy.HoldPickup); err != nil {
+			return err
+		}
+
+		bookCopy := tx.Model(&dao.BookCopy{}).
+			Select("id").
+			Where("barcode = ?", barcode)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("book_copy_id IN (?) AND return_date IS NULL", bookCopy).
+			First(&item).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrDataNotFound
+			}
+			return err
+		}
+
+		return closeBorrowing(tx, &item, returnedByID, returnDate, policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
 }
 
-func (r *BorrowingRepository) Delete(id uint) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+func closeBorrowing(
+	tx *gorm.DB,
+	item *dao.Borrowing,
+	returnedByID uint,
+	returnDate time.Time,
+	policy dao.CirculationPolicy,
+) error {
+	if item.ReturnDate != nil {
+		return exception.ErrBorrowingReturned
+	}
+
+	err := tx.Model(item).Updates(map[string]interface{}{
+		"return_date":    returnDate,
+		"returned_by_id": returnedByID,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	item.ReturnDate = &returnDate
+	item.ReturnedByID = &returnedByID
 
+	amount := policy.Fine.Assess(item, returnDate)
+	if amount > 0 {
+		err := tx.Create(&dao.Fine{
+			PersonID:     item.PersonID,
+			BorrowingID:  &item.ID,
+			Kind:         domain.FineKindFine,
+			Amount:       amount,
+			RecordedByID: &returnedByID,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	var bookCopy dao.BookCopy
+	err = tx.First(&bookCopy, item.BookCopyID).Error
+	if err != nil {
+		return err
+	}
+	if bookCopy.Status != domain.CopyStatusCirculating {
+		return nil
+	}
+
+	_, err = promoteNextHold(tx, item.BookID, item.BookCopyID, returnDate, policy.HoldPickup)
+	return err
+}
+
+func (r *borrowingRepository) Delete(ctx context.Context, id uint) error {
 	tx := r.db.WithContext(ctx).Delete(&dao.Borrowing{}, id)
 
 	return tx.Error
-}
\ No newline at end of file
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, newItem *dao.Category) error
+	GetByID(ctx context.Context, id uint) (*dao.Category, error)
+	GetAll(ctx context.Context) ([]dao.Category, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dao.Category, error)
+	Update(ctx context.Context, params *dto.CategoryUpdateReq) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, newItem *dao.Category) error {
+	tx := r.db.WithContext(ctx).Create(newItem)
+	if errors.Is(tx.Error, gorm.ErrDuplicatedKey) {
+		return exception.ErrCategoryConflict
+	}
+
+	return tx.Error
+}
+
+func (r *categoryRepository) GetByID(ctx context.Context, id uint) (*dao.Category, error) {
+	var item dao.Category
+	tx := r.db.WithContext(ctx).First(&item, id)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, exception.ErrCategoryNotFound
+		}
+
+		return nil, tx.Error
+	}
+
+	return &item, nil
+}
+
+// GetAll returns the whole tree. Services walk it in memory to resolve
+// paths and descendants.
+func (r *categoryRepository) GetAll(ctx context.Context) ([]dao.Category, error) {
+	var items []dao.Category
+	tx := r.db.WithContext(ctx).Order("code ASC").Find(&items)
+
+	return items, tx.Error
+}
+
+func (r *categoryRepository) GetList(ctx context.Context, params *dto.Filter) ([]dao.Category, error) {
+	var items []dao.Category
+	tx := r.db.WithContext(ctx)
+
+	if params.Keyword != "" {
+		q := fmt.Sprintf("%%%s%%", params.Keyword)
+		tx = tx.Where("name LIKE ? OR code LIKE ?", q, q)
+	}
+	tx = params.Query.Apply(tx)
+	if params.Start >= 0 {
+		tx = tx.Offset(params.Start)
+	}
+	if params.Limit > 0 {
+		tx = tx.Limit(params.Limit)
+	}
+
+	tx = params.Query.Order(tx, "code ASC").Find(&items)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, tx.Error
+	}
+
+	return items, nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, params *dto.CategoryUpdateReq) error {
+	tx := r.db.WithContext(ctx).Model(&dao.Category{}).
+		Where("id = ?", params.ID).
+		Updates(map[string]interface{}{
+			"parent_id": params.ParentID,
+			"code":      params.Code,
+			"name":      params.Name,
+		})
+	if errors.Is(tx.Error, gorm.ErrDuplicatedKey) {
+		return exception.ErrCategoryConflict
+	}
+
+	return tx.Error
+}
+
+// Delete removes a category that has no subcategories, unassigning it from
+// its books.
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var children int64
+		err := tx.Model(&dao.Category{}).Where("parent_id = ?", id).Count(&children).Error
+		if err != nil {
+			return err
+		}
+		if children > 0 {
+			return exception.ErrCategoryHasChildren
+		}
+
+		err = tx.Where("category_id = ?", id).Delete(&dao.BookCategory{}).Error
+		if err != nil {
+			return err
+		}
+
+		tx = tx.Delete(&dao.Category{}, id)
+		if tx.Error != nil {
+			return tx.Error
+		}
+		if tx.RowsAffected == 0 {
+			return exception.ErrCategoryNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FineRepository interface {
+	Create(ctx context.Context, newItem *dao.Fine) error
+	GetListByPerson(ctx context.Context, personID uint, params *dto.Filter) ([]dao.Fine, error)
+	GetBalance(ctx context.Context, personID uint) (int64, error)
+	Pay(ctx context.Context, payment *dao.Fine) error
+	Waive(ctx context.Context, personID, fineID uint, waiver *dao.Fine) error
+}
+
+type fineRepository struct {
+	db *gorm.DB
+}
+
+func NewFineRepository(db *gorm.DB) FineRepository {
+	return &fineRepository{db: db}
+}
+
+func (r *fineRepository) Create(ctx context.Context, newItem *dao.Fine) error {
+	tx := r.db.WithContext(ctx).Create(&newItem)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	return nil
+}
+
+func (r *fineRepository) GetListByPerson(ctx context.Context, personID uint, params *dto.Filter) ([]dao.Fine, error) {
+	var items []dao.Fine
+	tx := r.db.WithContext(ctx).Where("person_id = ?", personID)
+	tx = params.Query.Apply(tx)
+
+	if params.Start >= 0 {
+		tx = tx.Offset(params.Start)
+	}
+	if params.Limit > 0 {
+		tx = tx.Limit(params.Limit)
+	}
+
+	tx = params.Query.Order(tx, "created_at DESC, id DESC").Find(&items)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, tx.Error
+	}
+
+	return items, nil
+}
+
+// GetBalance returns the person's outstanding balance, i.e. the sum of all
+// ledger entries.
+func (r *fineRepository) GetBalance(ctx context.Context, personID uint) (int64, error) {
+	return balanceOf(r.db.WithContext(ctx), personID)
+}
+
+// Pay records a payment for the person. Payments larger than the outstanding
+// balance are refused.
+func (r *fineRepository) Pay(ctx context.Context, payment *dao.Fine) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&dao.Person{}, payment.PersonID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrUserNotFound
+			}
+			return err
+		}
+
+		balance, err := balanceOf(tx, payment.PersonID)
+		if err != nil {
+			return err
+		}
+		if -payment.Amount > balance {
+			return exception.ErrPaymentTooLarge
+		}
+
+		return tx.Create(payment).Error
+	})
+}
+
+// Waive cancels a fine by recording an offsetting waiver entry. The waiver
+// never exceeds the person's outstanding balance, and a fine can only be
+// waived once.
+func (r *fineRepository) Waive(ctx context.Context, personID, fineID uint, waiver *dao.Fine) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&dao.Person{}, personID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrUserNotFound
+			}
+			return err
+		}
+
+		var fine dao.Fine
+		err = tx.Where("person_id = ?", personID).
+			First(&fine, fineID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrUserNotFound
+			}
+			return err
+		}
+		if fine.Kind != domain.FineKindFine {
+			return exception.ErrFineNotWaivable
+		}
+
+		var waived int64
+		err = tx.Model(&dao.Fine{}).
+			Where("waived_fine_id = ?", fine.ID).
+			Count(&waived).Error
+		if err != nil {
+			return err
+		}
+		if waived > 0 {
+			return exception.ErrFineNotWaivable
+		}
+
+		balance, err := balanceOf(tx, personID)
+		if err != nil {
+			return err
+		}
+		if balance <= 0 {
+			return exception.ErrFineNotWaivable
+		}
+
+		amount := fine.Amount
+		if amount > balance {
+			amount = balance
+		}
+
+		waiver.PersonID = fine.PersonID
+		waiver.BorrowingID = fine.BorrowingID
+		waiver.WaivedFineID = &fine.ID
+		waiver.Kind = domain.FineKindWaiver
+		waiver.Amount = -amount
+
+		return tx.Create(waiver).Error
+	})
+}
+
+func balanceOf(tx *gorm.DB, personID uint) (int64, error) {
+	var balance int64
+	err := tx.Model(&dao.Fine{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("person_id = ?", personID).
+		Scan(&balance).Error
+
+	return balance, err
+}
@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HoldRepository interface {
+	Create(ctx context.Context, newItem *dao.Hold, now time.Time, pickup time.Duration) error
+	GetByID(ctx context.Context, bookID, id uint) (*dao.Hold, error)
+	GetQueue(ctx context.Context, bookID uint) ([]dao.Hold, error)
+	CountWaitingByOthers(ctx context.Context, bookID, personID uint) (int64, error)
+	Cancel(ctx context.Context, bookID, id uint, now time.Time, pickup time.Duration) error
+}
+
+type holdRepository struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) HoldRepository {
+	return &holdRepository{db: db}
+}
+
+// Create places newItem at the end of its book's queue. A person can only
+// hold a book once, and only while no copy of it is available. Holds that
+// lapsed before now are expired first, as their copies may be free again.
+func (r *holdRepository) Create(ctx context.Context, newItem *dao.Hold, now time.Time, pickup time.Duration) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := expireReadyHolds(tx, now, pickup); err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&dao.Book{}, newItem.BookID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrUserNotFound
+			}
+			return err
+		}
+
+		err = tx.First(&dao.Person{}, newItem.PersonID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrUserNotFound
+			}
+			return err
+		}
+
+		var existing int64
+		err = tx.Model(&dao.Hold{}).
+			Where("book_id = ? AND person_id = ? AND status IN ?",
+				newItem.BookID, newItem.PersonID, activeHoldStatuses).
+			Count(&existing).Error
+		if err != nil {
+			return err
+		}
+		if existing > 0 {
+			return exception.ErrHoldExists
+		}
+
+		var available int64
+		err = tx.Model(&dao.BookCopy{}).
+			Where("book_id = ?", newItem.BookID).
+			Scopes(availableCopies).
+			Count(&available).Error
+		if err != nil {
+			return err
+		}
+		if available > 0 {
+			return exception.ErrHoldNotRequired
+		}
+
+		newItem.Status = domain.HoldStatusWaiting
+		return tx.Create(newItem).Error
+	})
+}
+
+func (r *holdRepository) GetByID(ctx context.Context, bookID, id uint) (*dao.Hold, error) {
+	var item dao.Hold
+	tx := r.db.WithContext(ctx).
+		Joins("Person").
+		Where("holds.book_id = ?", bookID).
+		First(&item, id)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return nil, exception.ErrUserNotFound
+		}
+
+		return nil, tx.Error
+	}
+
+	return &item, nil
+}
+
+// GetQueue returns the active holds of a book in queue order: ready holds
+// first, then waiting holds oldest first.
+func (r *holdRepository) GetQueue(ctx context.Context, bookID uint) ([]dao.Hold, error) {
+	var items []dao.Hold
+	tx := r.db.WithContext(ctx).
+		Joins("Person").
+		Where("holds.book_id = ? AND holds.status IN ?", bookID, activeHoldStatuses).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "CASE WHEN holds.status = ? THEN 0 ELSE 1 END, holds.created_at, holds.id",
+			Vars: []interface{}{domain.HoldStatusReady},
+		}}).
+		Find(&items)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, tx.Error
+	}
+
+	return items, nil
+}
+
+// CountWaitingByOthers returns how many patrons other than personID are
+// queueing for the book.
+func (r *holdRepository) CountWaitingByOthers(ctx context.Context, bookID, personID uint) (int64, error) {
+	var count int64
+	tx := r.db.WithContext(ctx).Model(&dao.Hold{}).
+		Where("book_id = ? AND person_id <> ? AND status IN ?",
+			bookID, personID, activeHoldStatuses).
+		Count(&count)
+
+	return count, tx.Error
+}
+
+// Cancel withdraws an active hold. A copy that was set aside for it is passed
+// on to the next patron in the queue.
+func (r *holdRepository) Cancel(ctx context.Context, bookID, id uint, now time.Time, pickup time.Duration) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item dao.Hold
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("book_id = ?", bookID).
+			First(&item, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrUserNotFound
+			}
+			return err
+		}
+		if !item.IsActive() {
+			return exception.ErrHoldNotActive
+		}
+
+		return releaseHold(tx, &item, domain.HoldStatusCancelled, now, pickup)
+	})
+}
+
+var activeHoldStatuses = []domain.TypeHoldStatus{
+	domain.HoldStatusWaiting,
+	domain.HoldStatusReady,
+}
+
+// availableCopies limits a book_copies query to copies that can be lent out
+// right now: circulating, not on loan and not set aside for a hold.
+func availableCopies(tx *gorm.DB) *gorm.DB {
+	openLoan := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&dao.Borrowing{}).
+		Select("1").
+		Where("borrowings.book_copy_id = book_copies.id AND borrowings.return_date IS NULL")
+	readyHold := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&dao.Hold{}).
+		Select("1").
+		Where("holds.book_copy_id = book_copies.id AND holds.status = ?", domain.HoldStatusReady)
+
+	return tx.Where("book_copies.status = ?", domain.CopyStatusCirculating).
+		Where("NOT EXISTS (?)", openLoan).
+		Where("NOT EXISTS (?)", readyHold)
+}
+
+// expireReadyHolds lapses every ready hold whose pickup window has passed and
+// hands its copy to the next patron in the queue. The transactions that place
+// holds, lend and return books run it first, so reads never have to write.
+func expireReadyHolds(tx *gorm.DB, now time.Time, pickup time.Duration) error {
+	var items []dao.Hold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("status = ? AND expires_at < ?", domain.HoldStatusReady, now).
+		Find(&items).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		err := releaseHold(tx, &items[i], domain.HoldStatusExpired, now, pickup)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseHold closes item with status and, when a copy was set aside for it,
+// offers that copy to the next waiting patron.
+func releaseHold(
+	tx *gorm.DB,
+	item *dao.Hold,
+	status domain.TypeHoldStatus,
+	now time.Time,
+	pickup time.Duration,
+) error {
+	// Updates writes the new status into item, so look before it does
+	setAside := item.Status == domain.HoldStatusReady && item.BookCopyID != nil
+
+	err := tx.Model(item).Updates(map[string]interface{}{
+		"status": status,
+	}).Error
+	if err != nil {
+		return err
+	}
+	item.Status = status
+
+	if !setAside {
+		return nil
+	}
+
+	_, err = promoteNextHold(tx, item.BookID, *item.BookCopyID, now, pickup)
+	return err
+}
+
+// promoteNextHold makes the oldest waiting hold on bookID ready for pickup and
+// sets bookCopyID aside for it. It reports whether a hold was promoted.
+func promoteNextHold(
+	tx *gorm.DB,
+	bookID, bookCopyID uint,
+	now time.Time,
+	pickup time.Duration,
+) (bool, error) {
+	var next dao.Hold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("book_id = ? AND status = ?", bookID, domain.HoldStatusWaiting).
+		Order("created_at, id").
+		First(&next).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	expiresAt := now.Add(pickup)
+	err = tx.Model(&next).Updates(map[string]interface{}{
+		"status":       domain.HoldStatusReady,
+		"book_copy_id": bookCopyID,
+		"ready_at":     now,
+		"expires_at":   expiresAt,
+	}).Error
+
+	return err == nil, err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, newItem *dao.PasswordReset) error
+	Consume(ctx context.Context, tokenHash, passwordHash string, now time.Time) (uint, error)
+	DeleteByAccount(ctx context.Context, accountID uint) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create stores a new reset token for the account. Tokens issued earlier
+// and not yet used are invalidated so only the latest email works.
+func (r *passwordResetRepository) Create(ctx context.Context, newItem *dao.PasswordReset) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&dao.PasswordReset{}).
+			Where("account_id = ? AND used_at IS NULL", newItem.AccountID).
+			Update("used_at", newItem.CreatedAt).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(newItem).Error
+	})
+}
+
+// Consume uses up the reset token with the given hash and sets the account's
+// password to passwordHash. It returns the account's ID.
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash, passwordHash string, now time.Time) (uint, error) {
+	var accountID uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item dao.PasswordReset
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", tokenHash).
+			First(&item).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrResetTokenInvalid
+			}
+			return err
+		}
+		if item.UsedAt != nil || !item.ExpiresAt.After(now) {
+			return exception.ErrResetTokenInvalid
+		}
+
+		err = tx.Model(&item).Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		accountID = item.AccountID
+
+		return tx.Model(&dao.Account{}).
+			Where("id = ?", item.AccountID).
+			Update("password", passwordHash).Error
+	})
+
+	return accountID, err
+}
+
+// DeleteByAccount deletes the account's reset tokens, used or not.
+func (r *passwordResetRepository) DeleteByAccount(ctx context.Context, accountID uint) error {
+	tx := r.db.WithContext(ctx).Where("account_id = ?", accountID).Delete(&dao.PasswordReset{})
+
+	return tx.Error
+}
@@ -4,25 +4,35 @@ import (
 	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
-	"base-gin/storage"
+	"context"
 	"errors"
-
+	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type PersonRepository struct {
-	db *gorm.DB
+type PersonRepository interface {
+	Create(ctx context.Context, newItem *dao.Person) error
+	GetByAccountID(ctx context.Context, accountID uint) (dao.Person, error)
+	GetByID(ctx context.Context, id uint) (*dao.Person, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dao.Person, error)
+	Update(ctx context.Context, params *dto.PersonUpdateReq) error
+	Delete(ctx context.Context, id uint) error
+	DeleteByAccount(ctx context.Context, accountID uint) error
+	AttachAccount(ctx context.Context, id, accountID uint) error
+	DetachAccount(ctx context.Context, id uint) error
 }
 
-func NewPersonRepository(db *gorm.DB) *PersonRepository {
-	return &PersonRepository{db: db}
+type personRepository struct {
+	db *gorm.DB
 }
 
-func (r *PersonRepository) Create(newItem *dao.Person) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+func NewPersonRepository(db *gorm.DB) PersonRepository {
+	return &personRepository{db: db}
+}
 
+func (r *personRepository) Create(ctx context.Context, newItem *dao.Person) error {
 	tx := r.db.WithContext(ctx).Create(&newItem)
 	if tx.Error != nil {
 		return tx.Error
@@ -31,10 +41,7 @@ func (r *PersonRepository) Create(newItem *dao.Person) error {
 	return nil
 }
 
-func (r *PersonRepository) GetByAccountID(accountID uint) (dao.Person, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *personRepository) GetByAccountID(ctx context.Context, accountID uint) (dao.Person, error) {
 	var item dao.Person
 	tx := r.db.WithContext(ctx).Where(dao.Person{AccountID: &accountID}).
 		First(&item)
@@ -49,10 +56,7 @@ func (r *PersonRepository) GetByAccountID(accountID uint) (dao.Person, error) {
 	return item, nil
 }
 
-func (r *PersonRepository) GetByID(id uint) (*dao.Person, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *personRepository) GetByID(ctx context.Context, id uint) (*dao.Person, error) {
 	var item dao.Person
 	tx := r.db.WithContext(ctx).First(&item, id)
 	if tx.Error != nil {
@@ -66,26 +70,36 @@ func (r *PersonRepository) GetByID(id uint) (*dao.Person, error) {
 	return &item, nil
 }
 
-func (r *PersonRepository) GetList(filter interface{}) ([]dao.Person, error) {
-    // Cek jika filter nil
-    if filter == nil {
-        filter = &dao.Person{} // Default filter kosong
-    }
+// GetList returns every person when params is nil.
+func (r *personRepository) GetList(ctx context.Context, params *dto.Filter) ([]dao.Person, error) {
+	if params == nil {
+		params = &dto.Filter{}
+	}
 
-    var persons []dao.Person
-    result := r.db.Where(filter).Find(&persons)
-    if result.Error != nil {
-        return nil, result.Error
-    }
+	var items []dao.Person
+	tx := r.db.WithContext(ctx)
 
-    return persons, nil
-}
+	if params.Keyword != "" {
+		q := fmt.Sprintf("%%%s%%", params.Keyword)
+		tx = tx.Where("fullname LIKE ?", q)
+	}
+	tx = params.Query.Apply(tx)
+	if params.Start >= 0 {
+		tx = tx.Offset(params.Start)
+	}
+	if params.Limit > 0 {
+		tx = tx.Limit(params.Limit)
+	}
 
+	tx = params.Query.Order(tx, "id ASC").Find(&items)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, tx.Error
+	}
 
-func (r *PersonRepository) Update(params *dto.PersonUpdateReq) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+	return items, nil
+}
 
+func (r *personRepository) Update(ctx context.Context, params *dto.PersonUpdateReq) error {
 	tx := r.db.WithContext(ctx).Model(&dao.Person{}).
 		Where("id = ?", params.ID).
 		Updates(map[string]interface{}{
@@ -97,11 +111,93 @@ func (r *PersonRepository) Update(params *dto.PersonUpdateReq) error {
 	return tx.Error
 }
 
-func (r *PersonRepository) Delete(id uint) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *personRepository) Delete(ctx context.Context, id uint) error {
 	tx := r.db.WithContext(ctx).Delete(&dao.Person{}, id)
 
 	return tx.Error
 }
+
+// DeleteByAccount soft-deletes the person linked to the account, if any,
+// and unlinks it so the account row can go. The person's loans stay.
+func (r *personRepository) DeleteByAccount(ctx context.Context, accountID uint) error {
+	tx := r.db.WithContext(ctx).Model(&dao.Person{}).
+		Where("account_id = ?", accountID).
+		Updates(map[string]interface{}{
+			"account_id": nil,
+			"deleted_at": r.db.NowFunc(),
+		})
+
+	return tx.Error
+}
+
+// AttachAccount links the person to the account. A person has at most one
+// account and an account at most one person.
+func (r *personRepository) AttachAccount(ctx context.Context, id, accountID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item dao.Person
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrDataNotFound
+			}
+			return err
+		}
+		if item.AccountID != nil {
+			if *item.AccountID == accountID {
+				return nil
+			}
+			return exception.ErrPersonLinked
+		}
+
+		var count int64
+		if err := tx.Model(&dao.Account{}).Where("id = ?", accountID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return exception.ErrUserNotFound
+		}
+
+		err = tx.Model(&dao.Person{}).Where("account_id = ?", accountID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return exception.ErrAccountLinked
+		}
+
+		return tx.Model(&item).Update("account_id", accountID).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// Another request linked the account in the meantime.
+		return exception.ErrAccountLinked
+	}
+
+	return err
+}
+
+// DetachAccount unlinks the person from its account. Both records are kept.
+func (r *personRepository) DetachAccount(ctx context.Context, id uint) error {
+	var item dao.Person
+	tx := r.db.WithContext(ctx).First(&item, id)
+	if tx.Error != nil {
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return exception.ErrDataNotFound
+		}
+		return tx.Error
+	}
+	if item.AccountID == nil {
+		return exception.ErrPersonNotLinked
+	}
+
+	tx = r.db.WithContext(ctx).Model(&dao.Person{}).
+		Where("id = ? AND account_id IS NOT NULL", id).
+		Update("account_id", nil)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrPersonNotLinked
+	}
+
+	return nil
+}
@@ -4,25 +4,30 @@ import (
 	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
-	"base-gin/storage"
+	"context"
 	"errors"
 	"fmt"
 
 	"gorm.io/gorm"
 )
 
-type PublisherRepository struct {
-	db *gorm.DB
+type PublisherRepository interface {
+	Create(ctx context.Context, newItem *dao.Publisher) error
+	GetByID(ctx context.Context, id uint) (*dao.Publisher, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dao.Publisher, error)
+	Update(ctx context.Context, params *dto.PublisherUpdateReq) error
+	Delete(ctx context.Context, id uint) error
 }
 
-func NewPublisherRepository(db *gorm.DB) *PublisherRepository {
-	return &PublisherRepository{db: db}
+type publisherRepository struct {
+	db *gorm.DB
 }
 
-func (r *PublisherRepository) Create(newItem *dao.Publisher) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
+func NewPublisherRepository(db *gorm.DB) PublisherRepository {
+	return &publisherRepository{db: db}
+}
 
+func (r *publisherRepository) Create(ctx context.Context, newItem *dao.Publisher) error {
 	tx := r.db.WithContext(ctx).Create(&newItem)
 	if tx.Error != nil {
 		return tx.Error
@@ -31,10 +36,7 @@ func (r *PublisherRepository) Create(newItem *dao.Publisher) error {
 	return nil
 }
 
-func (r *PublisherRepository) GetByID(id uint) (*dao.Publisher, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *publisherRepository) GetByID(ctx context.Context, id uint) (*dao.Publisher, error) {
 	var item dao.Publisher
 	tx := r.db.WithContext(ctx).First(&item, id)
 	if tx.Error != nil {
@@ -48,10 +50,7 @@ func (r *PublisherRepository) GetByID(id uint) (*dao.Publisher, error) {
 	return &item, nil
 }
 
-func (r *PublisherRepository) GetList(params *dto.Filter) ([]dao.Publisher, error) {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *publisherRepository) GetList(ctx context.Context, params *dto.Filter) ([]dao.Publisher, error) {
 	var items []dao.Publisher
 	tx := r.db.WithContext(ctx)
 
@@ -59,6 +58,7 @@ func (r *PublisherRepository) GetList(params *dto.Filter) ([]dao.Publisher, erro
 		q := fmt.Sprintf("%%%s%%", params.Keyword)
 		tx = tx.Where("name LIKE ?", q)
 	}
+	tx = params.Query.Apply(tx)
 	if params.Start >= 0 {
 		tx = tx.Offset(params.Start)
 	}
@@ -66,7 +66,7 @@ func (r *PublisherRepository) GetList(params *dto.Filter) ([]dao.Publisher, erro
 		tx = tx.Limit(params.Limit)
 	}
 
-	tx = tx.Order("name ASC").Find(&items)
+	tx = params.Query.Order(tx, "name ASC").Find(&items)
 	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
 		return nil, tx.Error
 	}
@@ -74,10 +74,7 @@ func (r *PublisherRepository) GetList(params *dto.Filter) ([]dao.Publisher, erro
 	return items, nil
 }
 
-func (r *PublisherRepository) Update(params *dto.PublisherUpdateReq) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *publisherRepository) Update(ctx context.Context, params *dto.PublisherUpdateReq) error {
 	tx := r.db.WithContext(ctx).Model(&dao.Publisher{}).
 		Where("id = ?", params.ID).
 		Updates(map[string]interface{}{
@@ -88,10 +85,7 @@ func (r *PublisherRepository) Update(params *dto.PublisherUpdateReq) error {
 	return tx.Error
 }
 
-func (r *PublisherRepository) Delete(id uint) error {
-	ctx, cancelFunc := storage.NewDBContext()
-	defer cancelFunc()
-
+func (r *publisherRepository) Delete(ctx context.Context, id uint) error {
 	tx := r.db.WithContext(ctx).Delete(&dao.Publisher{}, id)
 
 	return tx.Error
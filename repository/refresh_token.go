@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, newItem *dao.RefreshToken) error
+	Rotate(ctx context.Context, tokenID string, next *dao.RefreshToken, now time.Time) error
+	DeleteByAccount(ctx context.Context, accountID uint) error
+}
+
+type refreshTokenRepository struct {
+	db       *gorm.DB
+	sessions SessionRepository
+}
+
+func NewRefreshTokenRepository(db *gorm.DB, sessions SessionRepository) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db, sessions: sessions}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, newItem *dao.RefreshToken) error {
+	tx := r.db.WithContext(ctx).Create(&newItem)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	return nil
+}
+
+// Rotate marks the token identified by tokenID as used and stores next as
+// its successor in the same session, extending the session to next's
+// expiry. Presenting a token that was already used means it has leaked, so
+// its session is revoked and ErrRefreshTokenReused is returned.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, tokenID string, next *dao.RefreshToken, now time.Time) error {
+	var sessionID string
+	reused := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item dao.RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Joins("Session").
+			Where("token_id = ?", tokenID).
+			First(&item).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.ErrRefreshTokenInvalid
+			}
+			return err
+		}
+		if item.AccountID != next.AccountID ||
+			!item.ExpiresAt.After(now) ||
+			item.Session == nil || !item.Session.IsActive(now) {
+			return exception.ErrRefreshTokenInvalid
+		}
+
+		sessionID = item.SessionID
+		if item.UsedAt != nil {
+			reused = true
+			return tx.Model(&dao.Session{}).
+				Where("id = ?", item.SessionID).
+				Update("revoked_at", now).Error
+		}
+
+		err = tx.Model(&item).Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&dao.Session{}).
+			Where("id = ?", item.SessionID).
+			Update("expires_at", next.ExpiresAt).Error
+		if err != nil {
+			return err
+		}
+
+		next.SessionID = item.SessionID
+
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		return err
+	}
+	if reused {
+		if cache, ok := r.sessions.(revocationCache); ok {
+			cache.markRevoked(sessionID, now)
+		}
+		return exception.ErrRefreshTokenReused
+	}
+
+	return nil
+}
+
+// DeleteByAccount deletes the refresh tokens of every session of the
+// account.
+func (r *refreshTokenRepository) DeleteByAccount(ctx context.Context, accountID uint) error {
+	tx := r.db.WithContext(ctx).Where("account_id = ?", accountID).Delete(&dao.RefreshToken{})
+
+	return tx.Error
+}
@@ -1,46 +1,74 @@
 package repository
 
-import "base-gin/storage"
-
-var (
-	accountRepo   *AccountRepository
-	personRepo    *PersonRepository
-	publisherRepo *PublisherRepository
-	authorRepo 	  *AuthorRepository
-	bookRepo 	  *BookRepository
-	borrowingRepo *BorrowingRepository
+import (
+	"base-gin/storage"
+	"context"
+
+	"gorm.io/gorm"
 )
 
-func SetupRepositories() {
-	db := storage.GetDB()
-	accountRepo = NewAccountRepository(db)
-	personRepo = NewPersonRepository(db)
-	publisherRepo = NewPublisherRepository(db)
-	authorRepo = NewAuthorRepository(db)
-	bookRepo = NewBookRepository(db)
-	borrowingRepo = NewBorrowingRepository(db)
-}
+// Repositories holds one instance of every repository, all bound to the
+// same database handle.
+type Repositories struct {
+	sessionCache *sessionCache
 
-func GetAccountRepo() *AccountRepository {
-	return accountRepo
+	Account       AccountRepository
+	Person        PersonRepository
+	Publisher     PublisherRepository
+	Author        AuthorRepository
+	Category      CategoryRepository
+	Book          BookRepository
+	BookCopy      BookCopyRepository
+	Borrowing     BorrowingRepository
+	Fine          FineRepository
+	Hold          HoldRepository
+	RefreshToken  RefreshTokenRepository
+	Session       SessionRepository
+	PasswordReset PasswordResetRepository
+	TwoFactor     TwoFactorRepository
+	APIKey        APIKeyRepository
+	Search        SearchRepository
 }
 
-func GetPersonRepo() *PersonRepository {
-	return personRepo
+func NewRepositories(db *gorm.DB) *Repositories {
+	return newRepositories(db, newSessionCache())
 }
 
-func GetPublisherRepo() *PublisherRepository {
-	return publisherRepo
-}
+func newRepositories(db *gorm.DB, cache *sessionCache) *Repositories {
+	sessionRepo := newSessionRepository(db, cache)
 
-func GetAuthorRepo() *AuthorRepository {
-	return authorRepo
+	return &Repositories{
+		sessionCache:  cache,
+		Account:       NewAccountRepository(db),
+		Person:        NewPersonRepository(db),
+		Publisher:     NewPublisherRepository(db),
+		Author:        NewAuthorRepository(db),
+		Category:      NewCategoryRepository(db),
+		Book:          NewBookRepository(db),
+		BookCopy:      NewBookCopyRepository(db),
+		Borrowing:     NewBorrowingRepository(db),
+		Fine:          NewFineRepository(db),
+		Hold:          NewHoldRepository(db),
+		RefreshToken:  NewRefreshTokenRepository(db, sessionRepo),
+		Session:       sessionRepo,
+		PasswordReset: NewPasswordResetRepository(db),
+		TwoFactor:     NewTwoFactorRepository(db),
+		APIKey:        NewAPIKeyRepository(db),
+		Search:        NewSearchRepository(db),
+	}
 }
 
-func GetBookRepo() *BookRepository {
-	return bookRepo
+// UnitOfWork runs work that spans several repositories in one transaction.
+// The repositories handed to fn are bound to that transaction; repositories
+// held elsewhere are not, so fn should only use the ones it is given.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos *Repositories) error) error
 }
 
-func GetBorrowingRepo() *BorrowingRepository {
-	return borrowingRepo
+// NewUnitOfWork returns a UnitOfWork whose repositories share the in-memory
+// state of repos, such as which sessions are revoked.
+func NewUnitOfWork(db *gorm.DB, repos *Repositories) UnitOfWork {
+	return storage.NewUnitOfWork(db, func(tx *gorm.DB) *Repositories {
+		return newRepositories(tx, repos.sessionCache)
+	})
 }
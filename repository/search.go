@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/search"
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SearchRepository reads books, authors and publishers as search documents.
+type SearchRepository interface {
+	search.Source
+}
+
+type searchRepository struct {
+	db    *gorm.DB
+	match fullTextMatch
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db, match: newFullTextMatch(db.Dialector.Name())}
+}
+
+// fullTextMatch returns a condition that holds when a word of table.column
+// starts with term. table and column come from the code, never from the
+// request, and term is letters and digits only.
+type fullTextMatch func(table, column, term string) clause.Expr
+
+// newFullTextMatch looks words up in the full-text indexes of migration
+// 0011 for the dialect.
+func newFullTextMatch(dialect string) fullTextMatch {
+	switch dialect {
+	case "mysql":
+		return func(table, column, term string) clause.Expr {
+			return clause.Expr{
+				SQL:  fmt.Sprintf("MATCH (%s.%s) AGAINST (? IN BOOLEAN MODE)", table, column),
+				Vars: []interface{}{term + "*"},
+			}
+		}
+	case "postgres":
+		// the expression is the one the GIN index is built on
+		return func(table, column, term string) clause.Expr {
+			return clause.Expr{
+				SQL:  fmt.Sprintf("to_tsvector('simple', %s.%s) @@ to_tsquery('simple', ?)", table, column),
+				Vars: []interface{}{term + ":*"},
+			}
+		}
+	default:
+		return func(table, column, term string) clause.Expr {
+			return clause.Expr{
+				SQL:  fmt.Sprintf("%s.id IN (SELECT docid FROM %s_fts WHERE %s_fts MATCH ?)", table, table, table),
+				Vars: []interface{}{column + ":" + term + "*"},
+			}
+		}
+	}
+}
+
+// fieldMatch is a condition on a row with the search field it stands for.
+type fieldMatch struct {
+	field string
+	cond  clause.Expr
+}
+
+// byScore orders rows by the summed weights of the fields that matched,
+// heaviest first, and ties by the columns in then, so the database keeps the
+// best candidates when it cuts them off at the limit.
+func byScore(matches []fieldMatch, then string) clause.OrderBy {
+	terms := make([]string, 0, len(matches))
+	vars := make([]interface{}, 0, len(matches))
+	for _, m := range matches {
+		terms = append(terms, fmt.Sprintf("CASE WHEN ? THEN %g ELSE 0 END", search.FieldWeight(m.field)))
+		vars = append(vars, m.cond)
+	}
+
+	sql := then
+	if len(terms) > 0 {
+		sql = "(" + strings.Join(terms, " + ") + ") DESC, " + then
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: vars, WithoutParentheses: true}}
+}
+
+// Match looks every term up as a word prefix in the full-text indexes and
+// orders the rows of each kind by the fields that matched before limiting.
+// Authors and publishers match on their only field, so the shortest names,
+// which are the nearest to the query, come first.
+func (r *searchRepository) Match(ctx context.Context, q search.Query, limit int) ([]search.Document, error) {
+	var docs []search.Document
+	db := r.db.WithContext(ctx)
+
+	if q.Kind == "" || q.Kind == search.KindBook {
+		tx := withSearchFields(db)
+		if q.ISBN != "" {
+			tx = tx.Where("books.isbn = ?", q.ISBN)
+		}
+
+		var matches []fieldMatch
+		for _, term := range q.Terms {
+			credited := db.Model(&dao.BookContributor{}).
+				Select("book_contributors.book_id").
+				Joins("JOIN authors ON authors.id = book_contributors.author_id").
+				Where(r.match("authors", "fullname", term))
+			published := db.Model(&dao.Publisher{}).
+				Select("publishers.id").
+				Where(r.match("publishers", "name", term))
+
+			title := fieldMatch{search.FieldTitle, r.match("books", "title", term)}
+			subtitle := fieldMatch{search.FieldSubtitle, r.match("books", "subtitle", term)}
+			contributors := fieldMatch{search.FieldContributors, clause.Expr{SQL: "books.id IN (?)", Vars: []interface{}{credited}}}
+			publisher := fieldMatch{search.FieldPublisher, clause.Expr{SQL: "books.publisher_id IN (?)", Vars: []interface{}{published}}}
+
+			tx = tx.Where(clause.Or(title.cond, subtitle.cond, contributors.cond, publisher.cond))
+			matches = append(matches, title, subtitle, contributors, publisher)
+		}
+
+		var books []dao.Book
+		if err := tx.Clauses(byScore(matches, "books.title ASC, books.id ASC")).Limit(limit).Find(&books).Error; err != nil {
+			return nil, err
+		}
+		for _, book := range books {
+			docs = append(docs, bookDocument(&book))
+		}
+	}
+
+	if q.ISBN != "" {
+		return docs, nil
+	}
+
+	if q.Kind == "" || q.Kind == search.KindAuthor {
+		tx := db
+		for _, term := range q.Terms {
+			tx = tx.Where(r.match("authors", "fullname", term))
+		}
+
+		var authors []dao.Author
+		order := "LENGTH(authors.fullname) ASC, authors.fullname ASC, authors.id ASC"
+		if err := tx.Order(order).Limit(limit).Find(&authors).Error; err != nil {
+			return nil, err
+		}
+		for _, author := range authors {
+			docs = append(docs, authorDocument(&author))
+		}
+	}
+
+	if q.Kind == "" || q.Kind == search.KindPublisher {
+		tx := db
+		for _, term := range q.Terms {
+			tx = tx.Where(r.match("publishers", "name", term))
+		}
+
+		var publishers []dao.Publisher
+		order := "LENGTH(publishers.name) ASC, publishers.name ASC, publishers.id ASC"
+		if err := tx.Order(order).Limit(limit).Find(&publishers).Error; err != nil {
+			return nil, err
+		}
+		for _, publisher := range publishers {
+			docs = append(docs, publisherDocument(&publisher))
+		}
+	}
+
+	return docs, nil
+}
+
+func (r *searchRepository) All(ctx context.Context) ([]search.Document, error) {
+	var docs []search.Document
+	db := r.db.WithContext(ctx)
+
+	var books []dao.Book
+	if err := withSearchFields(db).Find(&books).Error; err != nil {
+		return nil, err
+	}
+	for _, book := range books {
+		docs = append(docs, bookDocument(&book))
+	}
+
+	var authors []dao.Author
+	if err := db.Find(&authors).Error; err != nil {
+		return nil, err
+	}
+	for _, author := range authors {
+		docs = append(docs, authorDocument(&author))
+	}
+
+	var publishers []dao.Publisher
+	if err := db.Find(&publishers).Error; err != nil {
+		return nil, err
+	}
+	for _, publisher := range publishers {
+		docs = append(docs, publisherDocument(&publisher))
+	}
+
+	return docs, nil
+}
+
+// withSearchFields loads what a book document is made of besides the book.
+func withSearchFields(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Preload("Contributors", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Preload("Contributors.Author").
+		Preload("BookPublisher")
+}
+
+func bookDocument(book *dao.Book) search.Document {
+	var names []string
+	for _, contributor := range book.Contributors {
+		if contributor.Author != nil {
+			names = append(names, contributor.Author.Fullname)
+		}
+	}
+
+	fields := map[string]string{
+		search.FieldTitle:        book.Title,
+		search.FieldSubtitle:     book.Subtitle,
+		search.FieldContributors: strings.Join(names, ", "),
+	}
+	if book.BookPublisher != nil {
+		fields[search.FieldPublisher] = book.BookPublisher.Name
+	}
+	if book.ISBN != nil {
+		fields[search.FieldISBN] = *book.ISBN
+	}
+
+	return search.Document{Kind: search.KindBook, ID: book.ID, Label: book.Title, Fields: fields}
+}
+
+func authorDocument(author *dao.Author) search.Document {
+	return search.Document{
+		Kind:   search.KindAuthor,
+		ID:     author.ID,
+		Label:  author.Fullname,
+		Fields: map[string]string{search.FieldName: author.Fullname},
+	}
+}
+
+func publisherDocument(publisher *dao.Publisher) search.Document {
+	return search.Document{
+		Kind:   search.KindPublisher,
+		ID:     publisher.ID,
+		Label:  publisher.Name,
+		Fields: map[string]string{search.FieldName: publisher.Name},
+	}
+}
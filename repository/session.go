@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// sessionCacheTTL bounds how long a session seen as active is trusted
+// without asking the database, i.e. how late a revocation made by another
+// instance is noticed.
+const sessionCacheTTL = 30 * time.Second
+
+// sessionCacheSize is the number of cached sessions above which stale
+// entries are dropped.
+const sessionCacheSize = 10000
+
+type sessionCacheEntry struct {
+	revoked   bool
+	expiresAt time.Time
+	checkedAt time.Time
+}
+
+// sessionCache remembers what the session repositories learned about
+// sessions. One cache is shared by all session repositories of an
+// application, those bound to a unit of work included, so a revocation made
+// by any of them is seen by the others at once.
+type sessionCache struct {
+	mu      sync.RWMutex
+	entries map[string]sessionCacheEntry
+}
+
+func newSessionCache() *sessionCache {
+	return &sessionCache{entries: make(map[string]sessionCacheEntry)}
+}
+
+func (c *sessionCache) get(id string) (sessionCacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[id]
+	return entry, ok
+}
+
+func (c *sessionCache) remember(id string, entry sessionCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= sessionCacheSize {
+		for k, v := range c.entries {
+			if entry.checkedAt.Sub(v.checkedAt) >= sessionCacheTTL {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	c.entries[id] = entry
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, newItem *dao.Session) error
+	GetActiveList(ctx context.Context, accountID uint, now time.Time) ([]dao.Session, error)
+	IsRevoked(ctx context.Context, id string) (bool, error)
+	Revoke(ctx context.Context, accountID uint, id string, now time.Time) error
+	RevokeByAccount(ctx context.Context, accountID uint, now time.Time) (int, error)
+	DeleteByAccount(ctx context.Context, accountID uint) error
+}
+
+type sessionRepository struct {
+	db    *gorm.DB
+	cache *sessionCache
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return newSessionRepository(db, newSessionCache())
+}
+
+func newSessionRepository(db *gorm.DB, cache *sessionCache) *sessionRepository {
+	return &sessionRepository{db: db, cache: cache}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, newItem *dao.Session) error {
+	tx := r.db.WithContext(ctx).Create(&newItem)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	return nil
+}
+
+// GetActiveList returns the account's sessions that are neither revoked nor
+// expired, most recently used first.
+func (r *sessionRepository) GetActiveList(ctx context.Context, accountID uint, now time.Time) ([]dao.Session, error) {
+	var items []dao.Session
+	tx := r.db.WithContext(ctx).
+		Where("account_id = ? AND revoked_at IS NULL AND expires_at > ?", accountID, now).
+		Order("updated_at DESC").
+		Find(&items)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return nil, tx.Error
+	}
+
+	return items, nil
+}
+
+// IsRevoked reports whether the session can no longer authenticate
+// requests, i.e. it is revoked, expired or unknown. Answers are cached in
+// memory; revocations are permanent so only active sessions are re-checked,
+// and once the cached expiry has passed, as a refresh may have extended it.
+func (r *sessionRepository) IsRevoked(ctx context.Context, id string) (bool, error) {
+	now := time.Now()
+
+	entry, ok := r.cache.get(id)
+	if ok && (entry.revoked || (now.Sub(entry.checkedAt) < sessionCacheTTL && now.Before(entry.expiresAt))) {
+		return entry.revoked, nil
+	}
+
+	var item dao.Session
+	tx := r.db.WithContext(ctx).Where("id = ?", id).First(&item)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return false, tx.Error
+	}
+
+	revoked := tx.Error != nil || item.RevokedAt != nil
+	r.cache.remember(id, sessionCacheEntry{revoked: revoked, expiresAt: item.ExpiresAt, checkedAt: now})
+
+	return revoked || !item.IsActive(now), nil
+}
+
+// Revoke revokes one of the account's sessions.
+func (r *sessionRepository) Revoke(ctx context.Context, accountID uint, id string, now time.Time) error {
+	tx := r.db.WithContext(ctx).Model(&dao.Session{}).
+		Where("id = ? AND account_id = ? AND revoked_at IS NULL", id, accountID).
+		Update("revoked_at", now)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return exception.ErrDataNotFound
+	}
+
+	r.markRevoked(id, now)
+
+	return nil
+}
+
+// RevokeByAccount revokes every session of the account and returns how many
+// were still active.
+func (r *sessionRepository) RevokeByAccount(ctx context.Context, accountID uint, now time.Time) (int, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&dao.Session{}).
+			Where("account_id = ? AND revoked_at IS NULL", accountID).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		return tx.Model(&dao.Session{}).
+			Where("id IN ?", ids).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		r.markRevoked(id, now)
+	}
+
+	return len(ids), nil
+}
+
+// DeleteByAccount deletes the account's sessions. Their refresh tokens must
+// be deleted first. Deleted sessions count as revoked from then on.
+func (r *sessionRepository) DeleteByAccount(ctx context.Context, accountID uint) error {
+	var ids []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&dao.Session{}).
+			Where("account_id = ?", accountID).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		return tx.Where("id IN ?", ids).Delete(&dao.Session{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		r.markRevoked(id, now)
+	}
+
+	return nil
+}
+
+// revocationCache is implemented by session repositories that keep
+// revocation state in memory and need to hear about revocations made
+// elsewhere.
+type revocationCache interface {
+	markRevoked(id string, now time.Time)
+}
+
+func (r *sessionRepository) markRevoked(id string, now time.Time) {
+	r.cache.remember(id, sessionCacheEntry{revoked: true, checkedAt: now})
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TwoFactorRepository interface {
+	SetSecret(ctx context.Context, accountID uint, encryptedSecret string) error
+	Enable(ctx context.Context, accountID uint, now time.Time, step int64, codeHashes []string) error
+	Disable(ctx context.Context, accountID uint) error
+	DeleteByAccount(ctx context.Context, accountID uint) error
+	ClaimStep(ctx context.Context, accountID uint, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, accountID uint, codeHash string, now time.Time) (bool, error)
+	GetRolePolicy(ctx context.Context, role domain.TypeRole) (dao.RolePolicy, error)
+	SetRequired(ctx context.Context, role domain.TypeRole, required bool) error
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// SetSecret stores a new, not yet enabled, TOTP secret for the account.
+func (r *twoFactorRepository) SetSecret(ctx context.Context, accountID uint, encryptedSecret string) error {
+	return r.db.WithContext(ctx).Model(&dao.Account{}).
+		Where("id = ?", accountID).
+		Updates(map[string]interface{}{
+			"totp_secret":     encryptedSecret,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error
+}
+
+// Enable turns two-factor authentication on and replaces the account's
+// recovery codes.
+func (r *twoFactorRepository) Enable(ctx context.Context, accountID uint, now time.Time, step int64, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&dao.Account{}).
+			Where("id = ?", accountID).
+			Updates(map[string]interface{}{
+				"totp_enabled_at": now,
+				"totp_last_step":  step,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(tx, accountID, codeHashes)
+	})
+}
+
+// Disable turns two-factor authentication off and drops the secret and the
+// recovery codes.
+func (r *twoFactorRepository) Disable(ctx context.Context, accountID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&dao.Account{}).
+			Where("id = ?", accountID).
+			Updates(map[string]interface{}{
+				"totp_secret":     "",
+				"totp_enabled_at": nil,
+				"totp_last_step":  0,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("account_id = ?", accountID).Delete(&dao.RecoveryCode{}).Error
+	})
+}
+
+// DeleteByAccount deletes the account's recovery codes. The secret lives on
+// the account row and goes with it.
+func (r *twoFactorRepository) DeleteByAccount(ctx context.Context, accountID uint) error {
+	tx := r.db.WithContext(ctx).Where("account_id = ?", accountID).Delete(&dao.RecoveryCode{})
+
+	return tx.Error
+}
+
+// ClaimStep records step as the last TOTP step used by the account. It
+// reports false when a code from that step or a later one was already used.
+func (r *twoFactorRepository) ClaimStep(ctx context.Context, accountID uint, step int64) (bool, error) {
+	tx := r.db.WithContext(ctx).Model(&dao.Account{}).
+		Where("id = ? AND totp_last_step < ?", accountID, step).
+		Update("totp_last_step", step)
+
+	return tx.RowsAffected > 0, tx.Error
+}
+
+// UseRecoveryCode marks the account's unused recovery code with the given
+// hash as used. It reports false when there is no such code.
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, accountID uint, codeHash string, now time.Time) (bool, error) {
+	tx := r.db.WithContext(ctx).Model(&dao.RecoveryCode{}).
+		Where("account_id = ? AND code_hash = ? AND used_at IS NULL", accountID, codeHash).
+		Update("used_at", now)
+
+	return tx.RowsAffected > 0, tx.Error
+}
+
+func (r *twoFactorRepository) GetRolePolicy(ctx context.Context, role domain.TypeRole) (dao.RolePolicy, error) {
+	item := dao.RolePolicy{Role: role}
+	tx := r.db.WithContext(ctx).Where("role = ?", role).First(&item)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return item, tx.Error
+	}
+
+	return item, nil
+}
+
+func (r *twoFactorRepository) SetRequired(ctx context.Context, role domain.TypeRole, required bool) error {
+	item := dao.RolePolicy{Role: role, RequireTwoFactor: required}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"require_two_factor", "updated_at"}),
+	}).Create(&item).Error
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, accountID uint, codeHashes []string) error {
+	err := tx.Where("account_id = ?", accountID).Delete(&dao.RecoveryCode{}).Error
+	if err != nil {
+		return err
+	}
+
+	items := make([]dao.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		items[i] = dao.RecoveryCode{AccountID: accountID, CodeHash: hash}
+	}
+
+	return tx.Create(&items).Error
+}
@@ -1,11 +1,13 @@
 package rest
 
 import (
-	"base-gin/domain/dto"
+	"base-gin/domain"
 	"base-gin/domain/dao"
+	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
 	"base-gin/service"
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -16,14 +18,14 @@ import (
 
 type AccountHandler struct {
 	hr            *server.Handler
-	service       *service.AccountService
-	personService *service.PersonService
+	service       service.AccountService
+	personService service.PersonService
 }
 
 func NewAccountHandler(
 	hr *server.Handler,
-	accountService *service.AccountService,
-	personService *service.PersonService,
+	accountService service.AccountService,
+	personService service.PersonService,
 ) *AccountHandler {
 	return &AccountHandler{
 		hr:            hr,
@@ -35,23 +37,38 @@ func NewAccountHandler(
 func (h *AccountHandler) Route(app *gin.Engine) {
 	grp := app.Group(server.RootAccount)
 	grp.POST(server.PathLogin, h.login)
+	grp.POST(server.PathLoginTwoFactor, h.loginTwoFactor)
+	grp.POST(server.PathLoginTwoFactorSetup, h.loginTwoFactorSetup)
+	grp.POST(server.PathRefresh, h.hr.AuthRefresh(), h.refresh)
 	grp.GET("", h.hr.AuthAccess(), h.getProfile)
 	grp.POST("", h.create)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
-	grp.GET("/:id", h.getProfile)
-	grp.GET("/profile", h.hr.AuthAccess(), h.getByID)
+	grp.POST(server.PathRegister, h.register)
+	grp.DELETE("/:id", h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin), h.delete)
+	grp.GET("/:id", h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin), h.getByID)
+	grp.GET("/profile", h.hr.AuthAccess(), h.getProfile)
+	grp.PUT(server.PathRole, h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin), h.updateRole)
+	grp.PUT(server.PathPassword, h.hr.AuthAccess(), h.changePassword)
+	grp.POST(server.PathPasswordForgot, h.forgotPassword)
+	grp.POST(server.PathPasswordReset, h.resetPassword)
+	grp.GET(server.PathSessions, h.hr.AuthAccess(), h.getSessionList)
+	grp.POST(server.PathLogout, h.hr.AuthAccess(), h.logout)
+	grp.POST(server.PathLogoutAll, h.hr.AuthAccess(), h.logoutAll)
+	grp.DELETE(server.PathAccountSessions, h.hr.AuthAccess(), h.hr.RequireRole(domain.RoleAdmin), h.revokeSessions)
 }
 
 // login godoc
 //
 //	@Summary Account login
 //	@Description Account login using username & password combination.
+//	@Description Accounts using 2FA, or whose role requires it, get a challenge_token instead of tokens
+//	@Description and finish at /accounts/login/2fa.
 //	@Accept json
 //	@Produce json
 //	@Param cred body dto.AccountLoginReq true "Credential"
 //	@Success 200 {object} dto.SuccessResponse[dto.AccountLoginResp]
 //	@Failure 400 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 429 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /accounts/login [post]
 func (h *AccountHandler) login(c *gin.Context) {
@@ -61,18 +78,160 @@ func (h *AccountHandler) login(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.Login(req)
+	client := h.hr.ClientInfo(c)
+	throttle := h.hr.LoginThrottle()
+
+	retryAfter, err := throttle.Allow(req.Username, client.IPAddress)
+	if err != nil {
+		switch {
+		case errors.Is(err, server.ErrRequestThrottled):
+			h.hr.ErrorThrottled(c, retryAfter)
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	data, err := h.service.Login(c.Request.Context(), req, client)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound),
 			errors.Is(err, exception.ErrUserLoginFailed):
 			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(exception.ErrUserLoginFailed.Error()))
 		default:
+			h.releaseAttempt(throttle, req.Username, client.IPAddress)
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	// A challenge leaves the counters alone: loginTwoFactor counts its codes
+	// against the same username, which only tokens may clear.
+	if data.ChallengeToken == "" {
+		if err := throttle.Succeed(req.Username, client.IPAddress); err != nil {
+			exception.LogError(err, "AccountHandler.login")
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.AccountLoginResp]{
+		Success: true,
+		Message: "Login berhasil",
+		Data:    data,
+	})
+}
+
+// loginTwoFactorSetup godoc
+//
+//	@Summary Start 2FA enrollment during login
+//	@Description Generate a TOTP secret for an account whose role requires 2FA but has not enabled it.
+//	@Description Confirm it by sending a code to /accounts/login/2fa with the same challenge token.
+//	@Accept json
+//	@Produce json
+//	@Param cred body dto.AccountLoginChallengeReq true "Challenge token from login"
+//	@Success 200 {object} dto.SuccessResponse[dto.TwoFactorSetupResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/login/2fa/setup [post]
+func (h *AccountHandler) loginTwoFactorSetup(c *gin.Context) {
+	var req dto.AccountLoginChallengeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	username, err := h.service.VerifyChallenge(c.Request.Context(), req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, h.hr.ErrorResponse(err.Error()))
+		return
+	}
+
+	data, err := h.service.LoginTwoFactorSetup(c.Request.Context(), username)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusUnauthorized, h.hr.ErrorResponse(exception.ErrChallengeInvalid.Error()))
+		case errors.Is(err, exception.ErrTwoFactorEnabled):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.TwoFactorSetupResp]{
+		Success: true,
+		Message: "Pindai kode QR dengan aplikasi autentikator",
+		Data:    data,
+	})
+}
+
+// loginTwoFactor godoc
+//
+//	@Summary Complete a 2FA login
+//	@Description Exchange the challenge token and a TOTP or recovery code for an access/refresh pair.
+//	@Description During enrollment the code enables 2FA and the response also lists the recovery codes.
+//	@Accept json
+//	@Produce json
+//	@Param cred body dto.AccountLoginTwoFactorReq true "Challenge token and code"
+//	@Success 200 {object} dto.SuccessResponse[dto.AccountLoginResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 429 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/login/2fa [post]
+func (h *AccountHandler) loginTwoFactor(c *gin.Context) {
+	var req dto.AccountLoginTwoFactorReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	username, err := h.service.VerifyChallenge(c.Request.Context(), req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, h.hr.ErrorResponse(err.Error()))
+		return
+	}
+
+	client := h.hr.ClientInfo(c)
+	throttle := h.hr.LoginThrottle()
+
+	retryAfter, err := throttle.Allow(username, client.IPAddress)
+	if err != nil {
+		switch {
+		case errors.Is(err, server.ErrRequestThrottled):
+			h.hr.ErrorThrottled(c, retryAfter)
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	data, err := h.service.LoginTwoFactor(c.Request.Context(), username, req.Code, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrTwoFactorCodeInvalid):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrUserNotFound):
+			h.releaseAttempt(throttle, username, client.IPAddress)
+			c.JSON(http.StatusUnauthorized, h.hr.ErrorResponse(exception.ErrChallengeInvalid.Error()))
+		case errors.Is(err, exception.ErrTwoFactorNotSetup):
+			h.releaseAttempt(throttle, username, client.IPAddress)
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.releaseAttempt(throttle, username, client.IPAddress)
 			h.hr.ErrorInternalServer(c, err)
 		}
 		return
 	}
 
+	if err := throttle.Succeed(username, client.IPAddress); err != nil {
+		exception.LogError(err, "AccountHandler.loginTwoFactor")
+	}
+
 	c.JSON(http.StatusOK, dto.SuccessResponse[dto.AccountLoginResp]{
 		Success: true,
 		Message: "Login berhasil",
@@ -80,6 +239,274 @@ func (h *AccountHandler) login(c *gin.Context) {
 	})
 }
 
+// releaseAttempt stops a login attempt that ended before the credentials
+// were checked from counting as a failure.
+func (h *AccountHandler) releaseAttempt(throttle *server.LoginThrottle, username, ip string) {
+	if err := throttle.Release(username, ip); err != nil {
+		exception.LogError(err, "AccountHandler.releaseAttempt")
+	}
+}
+
+// refresh godoc
+//
+//	@Summary Refresh account tokens
+//	@Description Exchange a refresh token, sent as the bearer token, for a new access/refresh pair.
+//	@Description Each refresh token can be used once; reusing one revokes every token issued from the same login.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[dto.AccountLoginResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/refresh [post]
+func (h *AccountHandler) refresh(c *gin.Context) {
+	data, err := h.service.Refresh(c.Request.Context(),
+		c.GetString(server.ParamTokenUsername),
+		c.GetString(server.ParamTokenID),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound),
+			errors.Is(err, exception.ErrRefreshTokenInvalid),
+			errors.Is(err, exception.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.AccountLoginResp]{
+		Success: true,
+		Message: "Token berhasil diperbarui",
+		Data:    data,
+	})
+}
+
+// changePassword godoc
+//
+//	@Summary Change password
+//	@Description Change the logged-in account's password. The current password is required.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.AccountPasswordChangeReq true "Current and new password"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/password [put]
+func (h *AccountHandler) changePassword(c *gin.Context) {
+	var req dto.AccountPasswordChangeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	err := h.service.ChangePassword(c.Request.Context(), c.GetUint(server.ParamTokenUserID), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrPasswordMismatch):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Kata sandi berhasil diubah",
+	})
+}
+
+// forgotPassword godoc
+//
+//	@Summary Request a password reset
+//	@Description Email a single-use reset token to the account registered with the address.
+//	@Description The response is the same whether or not such an account exists.
+//	@Accept json
+//	@Produce json
+//	@Param detail body dto.AccountPasswordForgotReq true "Account's email"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/password/forgot [post]
+func (h *AccountHandler) forgotPassword(c *gin.Context) {
+	var req dto.AccountPasswordForgotReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	if err := h.service.ForgotPassword(c.Request.Context(), &req); err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Jika email terdaftar, petunjuk atur ulang kata sandi telah dikirim",
+	})
+}
+
+// resetPassword godoc
+//
+//	@Summary Reset password
+//	@Description Set a new password using a reset token. Every session of the account is logged out.
+//	@Accept json
+//	@Produce json
+//	@Param detail body dto.AccountPasswordResetReq true "Reset token and new password"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/password/reset [post]
+func (h *AccountHandler) resetPassword(c *gin.Context) {
+	var req dto.AccountPasswordResetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	err := h.service.ResetPassword(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrResetTokenInvalid):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Kata sandi berhasil diatur ulang",
+	})
+}
+
+// getSessionList godoc
+//
+//	@Summary Get account's sessions
+//	@Description Get the devices the logged-in account is signed in on.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[[]dto.SessionResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/sessions [get]
+func (h *AccountHandler) getSessionList(c *gin.Context) {
+	data, err := h.service.GetSessionList(c.Request.Context(),
+		c.GetUint(server.ParamTokenUserID),
+		c.GetString(server.ParamTokenSession),
+	)
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.SessionResp]{
+		Success: true,
+		Message: "Daftar sesi",
+		Data:    data,
+	})
+}
+
+// logout godoc
+//
+//	@Summary Log out
+//	@Description Revoke the session the request was made with.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/logout [post]
+func (h *AccountHandler) logout(c *gin.Context) {
+	err := h.service.Logout(c.Request.Context(),
+		c.GetUint(server.ParamTokenUserID),
+		c.GetString(server.ParamTokenSession),
+	)
+	if err != nil && !errors.Is(err, exception.ErrDataNotFound) {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Logout berhasil",
+	})
+}
+
+// logoutAll godoc
+//
+//	@Summary Log out everywhere
+//	@Description Revoke every session of the logged-in account, including the current one.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[dto.SessionRevokeResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/logout-all [post]
+func (h *AccountHandler) logoutAll(c *gin.Context) {
+	revoked, err := h.service.LogoutAll(c.Request.Context(), c.GetUint(server.ParamTokenUserID))
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.SessionRevokeResp]{
+		Success: true,
+		Message: "Logout dari semua sesi berhasil",
+		Data:    dto.SessionRevokeResp{Revoked: revoked},
+	})
+}
+
+// revokeSessions godoc
+//
+//	@Summary Revoke an account's sessions
+//	@Description Log an account out of every device.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param id path int true "Account ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.SessionRevokeResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/{id}/sessions [delete]
+func (h *AccountHandler) revokeSessions(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	revoked, err := h.service.LogoutAll(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.SessionRevokeResp]{
+		Success: true,
+		Message: "Sesi akun berhasil dicabut",
+		Data:    dto.SessionRevokeResp{Revoked: revoked},
+	})
+}
+
 // getProfile godoc
 //
 //	@Summary Get account's profile
@@ -95,7 +522,7 @@ func (h *AccountHandler) login(c *gin.Context) {
 func (h *AccountHandler) getProfile(c *gin.Context) {
 	accountID, _ := c.Get(server.ParamTokenUserID)
 
-	data, err := h.personService.GetAccountProfile((accountID).(uint))
+	data, err := h.personService.GetAccountProfile(c.Request.Context(), (accountID).(uint))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
@@ -114,6 +541,44 @@ func (h *AccountHandler) getProfile(c *gin.Context) {
 	})
 }
 
+// register godoc
+//
+//	@Summary Register a member
+//	@Description Create a member account together with its person profile, in one transaction.
+//	@Accept json
+//	@Produce json
+//	@Param detail body dto.AccountRegisterReq true "Credential and profile"
+//	@Success 201 {object} dto.SuccessResponse[dto.AccountRegisterResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/register [post]
+func (h *AccountHandler) register(c *gin.Context) {
+	var req dto.AccountRegisterReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Register(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.AccountRegisterResp]{
+		Success: true,
+		Message: "Pendaftaran berhasil",
+		Data:    data,
+	})
+}
+
 // create godoc
 //
 //	@Summary Create a new account
@@ -121,7 +586,7 @@ func (h *AccountHandler) getProfile(c *gin.Context) {
 //	@Accept json
 //	@Produce json
 //	@Param cred body dto.AccountCreateReq true "Account creation request"
-//	@Success 201 {object} dto.SuccessResponse[dto.AccountCreateResp]
+//	@Success 201 {object} dto.SuccessResponse[dto.AccountResp]
 //	@Failure 400 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
@@ -134,16 +599,21 @@ func (h *AccountHandler) create(c *gin.Context) {
 		return
 	}
 
-	account, err := h.service.Create(req)
+	account, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.AccountResp]{
 		Success: true,
 		Message: "Data berhasil disimpan",
-		Data:    account,
+		Data: dto.AccountResp{
+			ID:       account.ID,
+			Username: account.Username,
+			Email:    account.Email,
+			Role:     account.Role,
+		},
 	})
 }
 
@@ -159,8 +629,8 @@ func (h *AccountHandler) create(c *gin.Context) {
 //	@Failure 404 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /accounts/{id} [delete]
-// 	@Security BearerAuth
-func (h *AccountHandler) delete(c *gin.Context){
+//	@Security BearerAuth
+func (h *AccountHandler) delete(c *gin.Context) {
 	idStr := c.Param("id")
 
 	id, err := strconv.ParseUint(idStr, 10, 64)
@@ -169,7 +639,7 @@ func (h *AccountHandler) delete(c *gin.Context){
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -181,42 +651,56 @@ func (h *AccountHandler) delete(c *gin.Context){
 	})
 }
 
-// get godoc
+// updateRole godoc
 //
-//	@Summary Get logged-in account profile
-//	@Description Get the profile information of the logged-in account
+//	@Summary Change an account's role
+//	@Description Grant an account the admin, librarian or member role.
 //	@Accept json
 //	@Produce json
-//  @Security BearerAuth
-//	@Success 200 {object} dto.SuccessResponse[dto.AccountResp]
+//	@Security BearerAuth
+//	@Param id path int true "Account ID"
+//	@Param role body dto.AccountRoleUpdateReq true "New role"
+//	@Success 200 {object} dto.SuccessResponse[any]
 //	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
-//	@Router /accounts/profile [get]
-func (h *AccountHandler) GetProfile(c *gin.Context) {
-	account, err := h.service.GetAccountByToken(c)
+//	@Router /accounts/{id}/role [put]
+func (h *AccountHandler) updateRole(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, h.hr.ErrorResponse("Account not found"))
-		} else {
-			h.hr.ErrorInternalServer(c, err)
-		}
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
 		return
 	}
 
-	dataResp := dto.AccountResp{
-		ID:       account.ID,
-		Username: account.Username,
+	var req dto.AccountRoleUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
 	}
+	req.ID = uint(id)
 
-	c.JSON(http.StatusOK, dto.SuccessResponse[dto.AccountResp]{
+	err = h.service.UpdateRole(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
 		Success: true,
-		Message: "Account profile found",
-		Data:    dataResp,
+		Message: "Data berhasil disimpan",
 	})
 }
 
-
 // @Summary Get account by ID
 // @Description Retrieves an account by its ID
 // @ID get-account-by-id
@@ -237,16 +721,17 @@ func (h *AccountHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse("Data tidak ditemukan"))
 		} else {
 			h.hr.ErrorInternalServer(c, err)
 		}
+		return
 	}
-	
-	personData, err := h.personService.GetAccountProfile(data.ID)
+
+	personData, err := h.personService.GetAccountProfile(c.Request.Context(), data.ID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse("Data tidak ditemukan"))
@@ -255,15 +740,17 @@ func (h *AccountHandler) getByID(c *gin.Context) {
 		}
 		return
 	}
-	
+
 	accountResp := dto.AccountResp{
 		ID:       data.ID,
 		Username: data.Username,
+		Email:    data.Email,
+		Role:     data.Role,
 		Fullname: personData.Fullname,
 		Gender:   personData.Gender,
 		Age:      personData.Age,
 	}
-	
+
 	c.JSON(http.StatusOK, dto.SuccessResponse[dto.AccountResp]{
 		Success: true,
 		Message: "Account found",
@@ -293,7 +780,7 @@ func (h *AccountHandler) GetList(c *gin.Context) {
 		return
 	}
 
-	accounts, err := h.getAccountList(params)
+	accounts, err := h.getAccountList(c.Request.Context(), params)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
@@ -305,9 +792,8 @@ func (h *AccountHandler) GetList(c *gin.Context) {
 	c.JSON(http.StatusOK, accounts)
 }
 
-
-func (h *AccountHandler) getAccountList(params *dto.Filter) ([]dao.Account, error) {
-	accounts, err := h.service.GetList(params)
+func (h *AccountHandler) getAccountList(ctx context.Context, params *dto.Filter) ([]dao.Account, error) {
+	accounts, err := h.service.GetList(ctx, params)
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
@@ -319,8 +805,6 @@ func (h *AccountHandler) getAccountList(params *dto.Filter) ([]dao.Account, erro
 	return accounts, nil
 }
 
-
-
 // @Summary Update an account
 // @Description Updates an account based on the provided ID and request body
 // @ID update-account
@@ -351,7 +835,7 @@ func (h *AccountHandler) Update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	account, err := h.service.Update(&req)
+	account, err := h.service.Update(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
@@ -363,11 +847,11 @@ func (h *AccountHandler) Update(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse[dto.AccountResp]{
-			Success: true,
-			Message: "Data berhasil disimpan",
-			Data: dto.AccountResp{
-				ID:       account.ID,
-				Username: account.Username,
-			},
-		})
+		Success: true,
+		Message: "Data berhasil disimpan",
+		Data: dto.AccountResp{
+			ID:       account.ID,
+			Username: account.Username,
+		},
+	})
 }
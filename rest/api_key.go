@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	hr      *server.Handler
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(hr *server.Handler, apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{hr: hr, service: apiKeyService}
+}
+
+// Route registers the key management endpoints. They take an access token
+// only, so a leaked key cannot be used to mint more keys.
+func (h *APIKeyHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootAccount)
+	grp.POST(server.PathAPIKeys, h.hr.AuthAccess(), h.create)
+	grp.GET(server.PathAPIKeys, h.hr.AuthAccess(), h.getList)
+	grp.DELETE(server.PathAPIKey, h.hr.AuthAccess(), h.revoke)
+}
+
+// create godoc
+//
+//	@Summary Create an API key
+//	@Description Issue a key acting as the logged-in account, limited to the given scopes.
+//	@Description Send it in the X-API-Key header. The key is shown only in this response.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.APIKeyCreateReq true "Key's name, scopes and optional expiry"
+//	@Success 201 {object} dto.SuccessResponse[dto.APIKeyCreateResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/api-keys [post]
+func (h *APIKeyHandler) create(c *gin.Context) {
+	var req dto.APIKeyCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Create(c.Request.Context(), c.GetUint(server.ParamTokenUserID), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrAPIKeyExpiry):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[dto.APIKeyCreateResp]{
+		Success: true,
+		Message: "Kunci API berhasil dibuat",
+		Data:    data,
+	})
+}
+
+// getList godoc
+//
+//	@Summary List API keys
+//	@Description List the logged-in account's keys that are neither revoked nor expired.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[[]dto.APIKeyResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/api-keys [get]
+func (h *APIKeyHandler) getList(c *gin.Context) {
+	data, err := h.service.GetList(c.Request.Context(), c.GetUint(server.ParamTokenUserID))
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.APIKeyResp]{
+		Success: true,
+		Message: "Daftar kunci API",
+		Data:    data,
+	})
+}
+
+// revoke godoc
+//
+//	@Summary Revoke an API key
+//	@Description Revoke one of the logged-in account's keys. It is refused from then on.
+//	@Produce json
+//	@Security BearerAuth
+//	@Param keyID path int true "API key's ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/api-keys/{keyID} [delete]
+func (h *APIKeyHandler) revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("keyID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	err = h.service.Revoke(c.Request.Context(), c.GetUint(server.ParamTokenUserID), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Kunci API berhasil dicabut",
+	})
+}
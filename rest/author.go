@@ -1,35 +1,35 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
 	"base-gin/service"
 	"errors"
+	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
-	"github.com/gin-gonic/gin"
 )
 
 type AuthorHandler struct {
-	hr 		*server.Handler
-	service *service.AuthorService
+	hr      *server.Handler
+	service service.AuthorService
 }
 
-func NewAuthorHandler(handler *server.Handler, authorService *service.AuthorService) *AuthorHandler {
+func NewAuthorHandler(handler *server.Handler, authorService service.AuthorService) *AuthorHandler {
 	return &AuthorHandler{hr: handler, service: authorService}
 }
 
 func (h *AuthorHandler) Route(app *gin.Engine) {
 	grp := app.Group(server.RootAuthor)
-	grp.POST("", h.hr.AuthAccess(), h.create)
+	grp.POST("", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.create)
 	grp.GET("", h.getList)
 	grp.GET("/:id", h.getByID)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.PUT("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.update)
+	grp.DELETE("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.delete)
 }
 
-
 // create godoc
 //
 //	@Summary Create a author
@@ -37,6 +37,7 @@ func (h *AuthorHandler) Route(app *gin.Engine) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param detail body dto.AuthorUpdateReq true "Author's detail"
 //	@Success 201 {object} dto.SuccessResponse[any]
 //	@Failure 401 {object} dto.ErrorResponse
@@ -51,7 +52,7 @@ func (h *AuthorHandler) create(c *gin.Context) {
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -71,6 +72,9 @@ func (h *AuthorHandler) create(c *gin.Context) {
 //	@Param q query string false "Author's name"
 //	@Param s query int false "Data offset"
 //	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by fullname or birth_date, comma-separated, - for descending"
+//	@Param gender query string false "Gender, eq or in"
+//	@Param birth_date query string false "Birth date, also birth_date[gte], [gt], [lte] and [lt]"
 //	@Success 200 {object} dto.SuccessResponse[[]dto.AuthorResp]
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
@@ -79,12 +83,12 @@ func (h *AuthorHandler) create(c *gin.Context) {
 //	@Router /authors [get]
 func (h *AuthorHandler) getList(c *gin.Context) {
 	var req dto.Filter
-	if err := c.ShouldBindQuery(&req); err != nil {
+	if err := bindListQuery(c, &req, dto.AuthorQuery); err != nil {
 		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	data, err := h.service.GetList(&req)
+	data, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -123,7 +127,7 @@ func (h *AuthorHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -149,6 +153,7 @@ func (h *AuthorHandler) getByID(c *gin.Context) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Author's ID"
 //	@Param detail body dto.AuthorUpdateReq true "Author's detail"
 //	@Success 200 {object} dto.SuccessResponse[any]
@@ -175,7 +180,7 @@ func (h *AuthorHandler) update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	err = h.service.Update(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -199,6 +204,7 @@ func (h *AuthorHandler) update(c *gin.Context) {
 //	@Description Delete a author.
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Author's ID"
 //	@Success 200 {object} dto.SuccessResponse[any]
 //	@Failure 400 {object} dto.ErrorResponse
@@ -216,7 +222,7 @@ func (h *AuthorHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
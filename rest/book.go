@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
@@ -13,13 +14,13 @@ import (
 )
 
 type BookHandler struct {
-	hr *server.Handler
-	service *service.BookService
+	hr      *server.Handler
+	service service.BookService
 }
 
-func NewBookHandler (
+func NewBookHandler(
 	hr *server.Handler,
-	bookService *service.BookService,
+	bookService service.BookService,
 ) *BookHandler {
 	return &BookHandler{hr: hr, service: bookService}
 }
@@ -28,9 +29,11 @@ func (h *BookHandler) Route(app *gin.Engine) {
 	grp := app.Group(server.RootBook)
 	grp.GET("", h.getList)
 	grp.GET("/:id", h.getByID)
-	grp.POST("", h.hr.AuthAccess(), h.create)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.POST("", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.create)
+	grp.PUT("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.update)
+	grp.DELETE("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.delete)
+
+	app.GET(server.RootAuthorBook, h.getListByAuthor)
 }
 
 // create godoc
@@ -40,23 +43,35 @@ func (h *BookHandler) Route(app *gin.Engine) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
-//	@Param detail body dto.BookUpdateReq true "Book's detail"
+//	@Security APIKeyAuth
+//	@Param detail body dto.BookCreateReq true "Book's detail"
 //	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
 //	@Failure 401 {object} dto.ErrorResponse
 //	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /books [post]
 func (h *BookHandler) create(c *gin.Context) {
 	var req dto.BookCreateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.hr.BindingError(err)
+		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
-		h.hr.ErrorInternalServer(c, err)
+		switch {
+		case errors.Is(err, exception.ErrAuthorNotFound),
+			errors.Is(err, exception.ErrCategoryNotFound),
+			errors.Is(err, exception.ErrContributorDuplicate):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrISBNConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
 		return
 	}
 
@@ -72,8 +87,16 @@ func (h *BookHandler) create(c *gin.Context) {
 //	@Description Get a list of books.
 //	@Produce json
 //	@Param q query string false "Book's name"
+//	@Param isbn query string false "Book's ISBN-10 or ISBN-13"
+//	@Param category_id query int false "Category's ID, subcategories included"
 //	@Param s query int false "Data offset"
 //	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by title, created_at, publication_year or language, comma-separated, - for descending"
+//	@Param author_id query int false "Contributor's author ID, eq or in"
+//	@Param publisher_id query int false "Publisher's ID, eq or in"
+//	@Param language query string false "Language, eq or in"
+//	@Param publication_year query int false "Publication year, also publication_year[gte], [gt], [lte] and [lt]"
+//	@Param created_at query string false "Creation date, also created_at[gte], [gt], [lte] and [lt]"
 //	@Success 200 {object} dto.SuccessResponse[[]dto.BookResp]
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
@@ -81,16 +104,18 @@ func (h *BookHandler) create(c *gin.Context) {
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /books [get]
 func (h *BookHandler) getList(c *gin.Context) {
-	var req dto.Filter
-	if err := c.ShouldBindQuery(&req); err != nil {
+	var req dto.BookFilter
+	if err := bindListQuery(c, &req, dto.BookQuery); err != nil {
 		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	data, err := h.service.GetList(&req)
+	data, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
-		case errors.Is(err, exception.ErrUserNotFound):
+		case errors.Is(err, exception.ErrUserNotFound),
+			errors.Is(err, exception.ErrDataNotFound),
+			errors.Is(err, exception.ErrCategoryNotFound):
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
 		default:
 			h.hr.ErrorInternalServer(c, err)
@@ -106,6 +131,57 @@ func (h *BookHandler) getList(c *gin.Context) {
 	})
 }
 
+// getListByAuthor godoc
+//
+//	@Summary Get a list of an author's books
+//	@Description Get the books an author contributed to in any role.
+//	@Produce json
+//	@Param id path int true "Author's ID"
+//	@Param q query string false "Book's name"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by title, created_at, publication_year or language, comma-separated, - for descending"
+//	@Param publisher_id query int false "Publisher's ID, eq or in"
+//	@Param language query string false "Language, eq or in"
+//	@Param publication_year query int false "Publication year, also publication_year[gte], [gt], [lte] and [lt]"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.BookResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /authors/{id}/books [get]
+func (h *BookHandler) getListByAuthor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.Filter
+	if err := bindListQuery(c, &req, dto.BookQuery); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.GetListByAuthor(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BookResp]{
+		Success: true,
+		Message: "Daftar buku penulis",
+		Data:    data,
+	})
+}
+
 // getByID godoc
 //
 //	@Summary Get a book's detail
@@ -126,7 +202,7 @@ func (h *BookHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
@@ -151,6 +227,7 @@ func (h *BookHandler) getByID(c *gin.Context) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Book's ID"
 //	@Param detail body dto.BookUpdateReq true "Book's detail"
 //	@Success 200 {object} dto.SuccessResponse[any]
@@ -158,6 +235,7 @@ func (h *BookHandler) getByID(c *gin.Context) {
 //	@Failure 401 {object} dto.ErrorResponse
 //	@Failure 403 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /books/{id} [put]
@@ -177,11 +255,16 @@ func (h *BookHandler) update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	err = h.service.Update(c.Request.Context(), &req)
 	if err != nil {
 		switch {
-		case errors.Is(err, exception.ErrDateParsing):
+		case errors.Is(err, exception.ErrDateParsing),
+			errors.Is(err, exception.ErrAuthorNotFound),
+			errors.Is(err, exception.ErrCategoryNotFound),
+			errors.Is(err, exception.ErrContributorDuplicate):
 			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrISBNConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
 		case errors.Is(err, exception.ErrUserNotFound):
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
 		default:
@@ -203,6 +286,7 @@ func (h *BookHandler) update(c *gin.Context) {
 //	@Description Delete a book.
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Book's ID"
 //	@Success 200 {object} dto.SuccessResponse[any]
 //	@Failure 400 {object} dto.ErrorResponse
@@ -220,7 +304,7 @@ func (h *BookHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -0,0 +1,280 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookCopyHandler struct {
+	hr      *server.Handler
+	service service.BookCopyService
+}
+
+func NewBookCopyHandler(
+	hr *server.Handler,
+	bookCopyService service.BookCopyService,
+) *BookCopyHandler {
+	return &BookCopyHandler{hr: hr, service: bookCopyService}
+}
+
+func (h *BookCopyHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootBookCopy)
+	grp.GET("", h.getList)
+	grp.GET("/:copyID", h.getByID)
+	grp.POST("", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.create)
+	grp.PUT("/:copyID", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.update)
+	grp.DELETE("/:copyID", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.delete)
+}
+
+// parseIDs reads the book ID and, when present, the copy ID from the path.
+func (h *BookCopyHandler) parseIDs(c *gin.Context) (uint, uint, bool) {
+	bookID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return 0, 0, false
+	}
+
+	copyIDStr := c.Param("copyID")
+	if copyIDStr == "" {
+		return uint(bookID), 0, true
+	}
+
+	copyID, err := strconv.ParseUint(copyIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return 0, 0, false
+	}
+
+	return uint(bookID), uint(copyID), true
+}
+
+// create godoc
+//
+//	@Summary Add a copy of a book
+//	@Description Add a physical copy to a book.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Book's ID"
+//	@Param detail body dto.BookCopyCreateReq true "Copy's detail"
+//	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies [post]
+func (h *BookCopyHandler) create(c *gin.Context) {
+	bookID, _, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BookCopyCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.BookID = bookID
+
+	err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil disimpan",
+	})
+}
+
+// getList godoc
+//
+//	@Summary Get a list of a book's copies
+//	@Description Get a list of a book's copies.
+//	@Produce json
+//	@Param id path int true "Book's ID"
+//	@Param q query string false "Copy's barcode"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by barcode, shelf_location, status or acquisition_date, comma-separated, - for descending"
+//	@Param status query string false "Status, eq or in"
+//	@Param condition query string false "Condition, eq or in"
+//	@Param shelf_location query string false "Shelf location, eq or in"
+//	@Param acquisition_date query string false "Acquisition date, also acquisition_date[gte], [gt], [lte] and [lt]"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.BookCopyResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies [get]
+func (h *BookCopyHandler) getList(c *gin.Context) {
+	bookID, _, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.Filter
+	if err := bindListQuery(c, &req, dto.BookCopyQuery); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.GetList(c.Request.Context(), bookID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BookCopyResp]{
+		Success: true,
+		Message: "Daftar eksemplar",
+		Data:    data,
+	})
+}
+
+// getByID godoc
+//
+//	@Summary Get a copy's detail
+//	@Description Get a copy's detail.
+//	@Produce json
+//	@Param id path int true "Book's ID"
+//	@Param copyID path int true "Copy's ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.BookCopyResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies/{copyID} [get]
+func (h *BookCopyHandler) getByID(c *gin.Context) {
+	bookID, copyID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetByID(c.Request.Context(), bookID, copyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BookCopyResp]{
+		Success: true,
+		Message: "Detail eksemplar",
+		Data:    data,
+	})
+}
+
+// update godoc
+//
+//	@Summary Update a copy's detail
+//	@Description Update a copy's detail.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Book's ID"
+//	@Param copyID path int true "Copy's ID"
+//	@Param detail body dto.BookCopyUpdateReq true "Copy's detail"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies/{copyID} [put]
+func (h *BookCopyHandler) update(c *gin.Context) {
+	bookID, copyID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BookCopyUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.ID = copyID
+	req.BookID = bookID
+
+	err := h.service.Update(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil disimpan",
+	})
+}
+
+// delete godoc
+//
+//	@Summary Delete a copy
+//	@Description Delete a copy. Copies that are lent out cannot be deleted.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Book's ID"
+//	@Param copyID path int true "Copy's ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/copies/{copyID} [delete]
+func (h *BookCopyHandler) delete(c *gin.Context) {
+	bookID, copyID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.Delete(c.Request.Context(), bookID, copyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrBookUnavailable):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil dihapus",
+	})
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
@@ -13,24 +14,33 @@ import (
 )
 
 type BorrowingHandler struct {
-	hr *server.Handler
-	service *service.BorrowingService
+	hr            *server.Handler
+	service       service.BorrowingService
+	personService service.PersonService
 }
 
-func NewBorrowingHandler (
+func NewBorrowingHandler(
 	hr *server.Handler,
-	borrowingService *service.BorrowingService,
+	borrowingService service.BorrowingService,
+	personService service.PersonService,
 ) *BorrowingHandler {
-	return &BorrowingHandler{hr: hr, service: borrowingService}
+	return &BorrowingHandler{
+		hr:            hr,
+		service:       borrowingService,
+		personService: personService,
+	}
 }
 
 func (h *BorrowingHandler) Route(app *gin.Engine) {
 	grp := app.Group(server.RootBorrowing)
-	grp.GET("", h.getList)
-	grp.GET("/:id", h.getByID)
-	grp.POST("", h.hr.AuthAccess(), h.create)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.GET("", h.hr.Authenticate(domain.ScopeBorrowingsRead), h.getList)
+	grp.GET(server.PathOverdue, h.hr.Authenticate(domain.ScopeBorrowingsRead), h.hr.RequireRole(staff...), h.getOverdueList)
+	grp.GET("/:id", h.hr.Authenticate(domain.ScopeBorrowingsRead), h.getByID)
+	grp.POST("", h.hr.Authenticate(domain.ScopeBorrowingsWrite), h.hr.RequireRole(staff...), h.create)
+	grp.POST(server.PathRenew, h.hr.Authenticate(domain.ScopeBorrowingsWrite), h.renew)
+	grp.POST(server.PathReturn, h.hr.Authenticate(domain.ScopeBorrowingsWrite), h.hr.RequireRole(staff...), h.returnBook)
+	grp.POST(server.PathReturnByBook, h.hr.Authenticate(domain.ScopeBorrowingsWrite), h.hr.RequireRole(staff...), h.returnByBook)
+	grp.DELETE("/:id", h.hr.Authenticate(domain.ScopeBorrowingsWrite), h.hr.RequireRole(domain.RoleAdmin), h.delete)
 }
 
 // create godoc
@@ -40,23 +50,36 @@ func (h *BorrowingHandler) Route(app *gin.Engine) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
-//	@Param detail body dto.BorrowingUpdateReq true "Borrowing's detail"
+//	@Security APIKeyAuth
+//	@Param detail body dto.BorrowingCreateReq true "Borrowing's detail"
 //	@Success 201 {object} dto.SuccessResponse[any]
 //	@Failure 401 {object} dto.ErrorResponse
 //	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /borrowings [post]
 func (h *BorrowingHandler) create(c *gin.Context) {
 	var req dto.BorrowingCreateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.hr.BindingError(err)
+		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
-		h.hr.ErrorInternalServer(c, err)
+		switch {
+		case errors.Is(err, exception.ErrBookUnavailable),
+			errors.Is(err, exception.ErrBookOnHold):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrFineBalanceTooHigh):
+			c.JSON(http.StatusForbidden, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
 		return
 	}
 
@@ -69,28 +92,54 @@ func (h *BorrowingHandler) create(c *gin.Context) {
 // getList godoc
 //
 //	@Summary Get a list of borrowings
-//	@Description Get a list of borrowings.
+//	@Description Get a list of borrowings. Members only see their own.
 //	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param q query string false "Borrowing's name"
+//	@Param person_id query int false "Borrower's person ID"
 //	@Param s query int false "Data offset"
 //	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by borrow_date, due_date or return_date, comma-separated, - for descending"
+//	@Param returned query bool false "Whether the book has been returned"
+//	@Param book_id query int false "Book's ID, eq or in"
+//	@Param book_copy_id query int false "Book copy's ID, eq or in"
+//	@Param borrow_date query string false "Borrow date, also borrow_date[gte], [gt], [lte] and [lt]"
+//	@Param due_date query string false "Due date, also due_date[gte], [gt], [lte] and [lt]"
+//	@Param return_date query string false "Return date, also return_date[gte], [gt], [lte] and [lt]"
 //	@Success 200 {object} dto.SuccessResponse[[]dto.BorrowingResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /borrowings [get]
 func (h *BorrowingHandler) getList(c *gin.Context) {
-	var req dto.Filter
-	if err := c.ShouldBindQuery(&req); err != nil {
+	var req dto.BorrowingFilter
+	if err := bindListQuery(c, &req, dto.BorrowingQuery); err != nil {
 		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	data, err := h.service.GetList(&req)
+	personID, restricted, err := ownPersonID(c, h.hr, h.personService)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusForbidden, h.hr.ErrorResponse(exception.ErrForbidden.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+	if restricted {
+		req.PersonID = personID
+	}
+
+	data, err := h.service.GetList(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound), errors.Is(err, exception.ErrUserNotFound):
 			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
 		default:
 			h.hr.ErrorInternalServer(c, err)
@@ -109,11 +158,15 @@ func (h *BorrowingHandler) getList(c *gin.Context) {
 // getByID godoc
 //
 //	@Summary Get a borrowing's detail
-//	@Description Get a borrowing's detail.
+//	@Description Get a borrowing's detail. Members only see their own.
 //	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Borrowing's ID"
 //	@Success 200 {object} dto.SuccessResponse[dto.BorrowingResp]
 //	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
 //	@Router /borrowings/{id} [get]
@@ -126,7 +179,7 @@ func (h *BorrowingHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
@@ -136,6 +189,9 @@ func (h *BorrowingHandler) getByID(c *gin.Context) {
 		}
 		return
 	}
+	if !canActFor(c, h.hr, h.personService, data.PersonID) {
+		return
+	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BorrowingResp]{
 		Success: true,
@@ -144,24 +200,69 @@ func (h *BorrowingHandler) getByID(c *gin.Context) {
 	})
 }
 
-// update godoc
+// getOverdueList godoc
 //
-//	@Summary Update a borrowing's detail
-//	@Description Update a borrowing's detail.
-//	@Accept json
+//	@Summary Get a list of overdue borrowings
+//	@Description Get open borrowings that are past their due date, oldest first.
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by borrow_date or due_date, comma-separated, - for descending"
+//	@Param book_id query int false "Book's ID, eq or in"
+//	@Param book_copy_id query int false "Book copy's ID, eq or in"
+//	@Param borrow_date query string false "Borrow date, also borrow_date[gte], [gt], [lte] and [lt]"
+//	@Param due_date query string false "Due date, also due_date[gte], [gt], [lte] and [lt]"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.BorrowingResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /borrowings/overdue [get]
+func (h *BorrowingHandler) getOverdueList(c *gin.Context) {
+	var req dto.Filter
+	if err := bindListQuery(c, &req, dto.OverdueQuery); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.GetOverdueList(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.BorrowingResp]{
+		Success: true,
+		Message: "Daftar peminjaman terlambat",
+		Data:    data,
+	})
+}
+
+// renew godoc
+//
+//	@Summary Renew a borrowing
+//	@Description Extend a borrowing's due date by one loan period. Members may only renew their own.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Borrowing's ID"
-//	@Param detail body dto.BorrowingUpdateReq true "Borrowing's detail"
-//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Success 200 {object} dto.SuccessResponse[dto.BorrowingResp]
 //	@Failure 400 {object} dto.ErrorResponse
 //	@Failure 401 {object} dto.ErrorResponse
 //	@Failure 403 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
-//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
 //	@Failure 500 {object} dto.ErrorResponse
-//	@Router /borrowings/{id} [put]
-func (h *BorrowingHandler) update(c *gin.Context) {
+//	@Router /borrowings/{id}/renew [post]
+func (h *BorrowingHandler) renew(c *gin.Context) {
 	idStr := c.Param("id")
 
 	id, err := strconv.ParseUint(idStr, 10, 64)
@@ -169,40 +270,118 @@ func (h *BorrowingHandler) update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
 		return
 	}
-
-	var req dto.BorrowingUpdateReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(h.hr.BindingError(err))
+	if !h.authorizeBorrowing(c, uint(id)) {
 		return
 	}
-	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	data, err := h.service.Renew(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
-		case errors.Is(err, exception.ErrDateParsing):
-			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
 		case errors.Is(err, exception.ErrUserNotFound):
-			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		case errors.Is(err, exception.ErrBorrowingReturned),
+			errors.Is(err, exception.ErrBorrowingOverdue),
+			errors.Is(err, exception.ErrRenewalLimit),
+			errors.Is(err, exception.ErrHoldPending):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
 		default:
 			h.hr.ErrorInternalServer(c, err)
 		}
+		return
+	}
 
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BorrowingResp]{
+		Success: true,
+		Message: "Peminjaman berhasil diperpanjang",
+		Data:    data,
+	})
+}
+
+// returnBook godoc
+//
+//	@Summary Return a borrowed book
+//	@Description Check a borrowing in. The return date is taken from the server clock.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Borrowing's ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.BorrowingResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /borrowings/{id}/return [post]
+func (h *BorrowingHandler) returnBook(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+	data, err := h.service.Return(c.Request.Context(), uint(id), c.GetUint(server.ParamTokenUserID))
+	if err != nil {
+		h.returnError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BorrowingResp]{
 		Success: true,
-		Message: "Data berhasil disimpan",
+		Message: "Buku berhasil dikembalikan",
+		Data:    data,
+	})
+}
+
+// returnByBook godoc
+//
+//	@Summary Return a borrowed book by its barcode
+//	@Description Check in the open borrowing of the book copy whose barcode is scanned at the circulation desk.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param barcode path string true "Book copy's barcode"
+//	@Success 200 {object} dto.SuccessResponse[dto.BorrowingResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /borrowings/return-by-book/{barcode} [post]
+func (h *BorrowingHandler) returnByBook(c *gin.Context) {
+	data, err := h.service.ReturnByBarcode(c.Request.Context(), c.Param("barcode"), c.GetUint(server.ParamTokenUserID))
+	if err != nil {
+		h.returnError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.BorrowingResp]{
+		Success: true,
+		Message: "Buku berhasil dikembalikan",
+		Data:    data,
 	})
 }
 
+func (h *BorrowingHandler) returnError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, exception.ErrUserNotFound),
+		errors.Is(err, exception.ErrDataNotFound):
+		c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+	case errors.Is(err, exception.ErrBorrowingReturned):
+		c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+	default:
+		h.hr.ErrorInternalServer(c, err)
+	}
+}
+
 // delete godoc
 //
 //	@Summary Delete a borrowing
 //	@Description Delete a borrowing.
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Borrowing's ID"
 //	@Success 200 {object} dto.SuccessResponse[any]
 //	@Failure 400 {object} dto.ErrorResponse
@@ -220,7 +399,7 @@ func (h *BorrowingHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -231,3 +410,25 @@ func (h *BorrowingHandler) delete(c *gin.Context) {
 		Message: "Data berhasil dihapus",
 	})
 }
+
+// authorizeBorrowing lets staff through for any borrowing and members only
+// for their own. The error response has already been written when it
+// returns false.
+func (h *BorrowingHandler) authorizeBorrowing(c *gin.Context, id uint) bool {
+	if h.hr.IsStaff(c) {
+		return true
+	}
+
+	data, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return false
+	}
+
+	return canActFor(c, h.hr, h.personService, data.PersonID)
+}
@@ -0,0 +1,259 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	hr      *server.Handler
+	service service.CategoryService
+}
+
+func NewCategoryHandler(handler *server.Handler, categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{hr: handler, service: categoryService}
+}
+
+func (h *CategoryHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootCategory)
+	grp.POST("", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.create)
+	grp.GET("", h.getList)
+	grp.GET("/:id", h.getByID)
+	grp.PUT("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.update)
+	grp.DELETE("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.delete)
+}
+
+// create godoc
+//
+//	@Summary Create a category
+//	@Description Create a category.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param detail body dto.CategoryCreateReq true "Category's detail"
+//	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories [post]
+func (h *CategoryHandler) create(c *gin.Context) {
+	var req dto.CategoryCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrCategoryParent):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrCategoryConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil disimpan",
+	})
+}
+
+// getList godoc
+//
+//	@Summary Get a list of categories
+//	@Description Get a list of categories.
+//	@Produce json
+//	@Param q query string false "Category's name or classification code"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by code or name, comma-separated, - for descending"
+//	@Param parent_id query int false "Parent category's ID, eq or in"
+//	@Param has_parent query bool false "false for the top-level categories only"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.CategoryResp]
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories [get]
+func (h *CategoryHandler) getList(c *gin.Context) {
+	var req dto.Filter
+	if err := bindListQuery(c, &req, dto.CategoryQuery); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.GetList(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.CategoryResp]{
+		Success: true,
+		Message: "Daftar kategori",
+		Data:    data,
+	})
+}
+
+// getByID godoc
+//
+//	@Summary Get a category's detail
+//	@Description Get a category's detail.
+//	@Produce json
+//	@Param id path int true "Category's ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.CategoryResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories/{id} [get]
+func (h *CategoryHandler) getByID(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.CategoryResp]{
+		Success: true,
+		Message: "Detail kategori",
+		Data:    data,
+	})
+}
+
+// update godoc
+//
+//	@Summary Update a category's detail
+//	@Description Update a category's detail. A category cannot be moved under itself or one of its subcategories.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Category's ID"
+//	@Param detail body dto.CategoryUpdateReq true "Category's detail"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories/{id} [put]
+func (h *CategoryHandler) update(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.CategoryUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.ID = uint(id)
+
+	err = h.service.Update(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrCategoryParent),
+			errors.Is(err, exception.ErrCategoryCycle):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrCategoryConflict):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil disimpan",
+	})
+}
+
+// delete godoc
+//
+//	@Summary Delete a category
+//	@Description Delete a category that has no subcategories. Books assigned to it lose the category.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Category's ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /categories/{id} [delete]
+func (h *CategoryHandler) delete(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	err = h.service.Delete(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrCategoryHasChildren):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data berhasil dihapus",
+	})
+}
@@ -0,0 +1,202 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FineHandler struct {
+	hr            *server.Handler
+	service       service.FineService
+	personService service.PersonService
+}
+
+func NewFineHandler(
+	hr *server.Handler,
+	fineService service.FineService,
+	personService service.PersonService,
+) *FineHandler {
+	return &FineHandler{hr: hr, service: fineService, personService: personService}
+}
+
+func (h *FineHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootPersonFine)
+	grp.GET("", h.hr.Authenticate(domain.ScopeFinesRead), h.getBalance)
+	grp.POST(server.PathPayment, h.hr.Authenticate(domain.ScopeFinesWrite), h.hr.RequireRole(staff...), h.pay)
+	grp.POST(server.PathWaive, h.hr.Authenticate(domain.ScopeFinesWrite), h.hr.RequireRole(staff...), h.waive)
+}
+
+// getBalance godoc
+//
+//	@Summary Get a person's fine balance
+//	@Description Get a person's outstanding balance and ledger entries, newest first unless sorted otherwise.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Person's ID"
+//	@Param s query int false "Data offset"
+//	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by amount or created_at, comma-separated, - for descending"
+//	@Param kind query string false "Entry kind, eq or in"
+//	@Param borrowing_id query int false "Borrowing's ID, eq or in"
+//	@Param amount query int false "Amount, also amount[gte], [gt], [lte] and [lt]"
+//	@Param created_at query string false "Entry date, also created_at[gte], [gt], [lte] and [lt]"
+//	@Success 200 {object} dto.SuccessResponse[dto.FineBalanceResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /persons/{id}/fines [get]
+func (h *FineHandler) getBalance(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+	if !canActFor(c, h.hr, h.personService, uint(id)) {
+		return
+	}
+
+	var req dto.Filter
+	if err := bindListQuery(c, &req, dto.FineQuery); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.GetBalance(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		h.hr.ErrorInternalServer(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.FineBalanceResp]{
+		Success: true,
+		Message: "Saldo denda",
+		Data:    data,
+	})
+}
+
+// pay godoc
+//
+//	@Summary Record a fine payment
+//	@Description Record a payment against a person's outstanding fines.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Person's ID"
+//	@Param detail body dto.FinePaymentReq true "Payment's detail"
+//	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /persons/{id}/fines/payments [post]
+func (h *FineHandler) pay(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.FinePaymentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.PersonID = uint(id)
+
+	err = h.service.Pay(c.Request.Context(), &req, c.GetUint(server.ParamTokenUserID))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		case errors.Is(err, exception.ErrPaymentTooLarge):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Pembayaran berhasil disimpan",
+	})
+}
+
+// waive godoc
+//
+//	@Summary Waive a fine
+//	@Description Cancel a fine by recording an offsetting waiver.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Person's ID"
+//	@Param fineID path int true "Fine's ID"
+//	@Param detail body dto.FineWaiveReq false "Waiver's detail"
+//	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /persons/{id}/fines/{fineID}/waive [post]
+func (h *FineHandler) waive(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	fineID, err := strconv.ParseUint(c.Param("fineID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.FineWaiveReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(h.hr.BindingError(err))
+			return
+		}
+	}
+
+	err = h.service.Waive(c.Request.Context(), uint(id), uint(fineID), &req, c.GetUint(server.ParamTokenUserID))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		case errors.Is(err, exception.ErrFineNotWaivable):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Denda berhasil dihapuskan",
+	})
+}
@@ -0,0 +1,250 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldHandler struct {
+	hr            *server.Handler
+	service       service.HoldService
+	personService service.PersonService
+}
+
+func NewHoldHandler(
+	hr *server.Handler,
+	holdService service.HoldService,
+	personService service.PersonService,
+) *HoldHandler {
+	return &HoldHandler{hr: hr, service: holdService, personService: personService}
+}
+
+func (h *HoldHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootBookHold)
+	grp.GET("", h.hr.Authenticate(domain.ScopeHoldsRead), h.hr.RequireRole(staff...), h.getQueue)
+	grp.GET("/:holdID", h.hr.Authenticate(domain.ScopeHoldsRead), h.getByID)
+	grp.POST("", h.hr.Authenticate(domain.ScopeHoldsWrite), h.create)
+	grp.DELETE("/:holdID", h.hr.Authenticate(domain.ScopeHoldsWrite), h.cancel)
+}
+
+// create godoc
+//
+//	@Summary Place a hold on a book
+//	@Description Join the queue for a book whose copies are all out. Members may only place holds for themselves.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Book's ID"
+//	@Param detail body dto.HoldCreateReq true "Hold's detail"
+//	@Success 201 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/holds [post]
+func (h *HoldHandler) create(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.HoldCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.BookID = uint(id)
+	if !canActFor(c, h.hr, h.personService, req.PersonID) {
+		return
+	}
+
+	err = h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		case errors.Is(err, exception.ErrHoldExists),
+			errors.Is(err, exception.ErrHoldNotRequired):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Pemesanan berhasil disimpan",
+	})
+}
+
+// getQueue godoc
+//
+//	@Summary Get a book's hold queue
+//	@Description Get the active holds of a book in queue order.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Book's ID"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.HoldResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/holds [get]
+func (h *HoldHandler) getQueue(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	data, err := h.service.GetQueue(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.HoldResp]{
+		Success: true,
+		Message: "Antrean pemesanan",
+		Data:    data,
+	})
+}
+
+// getByID godoc
+//
+//	@Summary Get a hold's detail
+//	@Description Get a hold's detail including its queue position.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Book's ID"
+//	@Param holdID path int true "Hold's ID"
+//	@Success 200 {object} dto.SuccessResponse[dto.HoldResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/holds/{holdID} [get]
+func (h *HoldHandler) getByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	holdID, err := strconv.ParseUint(c.Param("holdID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	data, err := h.service.GetByID(c.Request.Context(), uint(id), uint(holdID))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+	if !canActFor(c, h.hr, h.personService, uint(data.PersonID)) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.HoldResp]{
+		Success: true,
+		Message: "Detail pemesanan",
+		Data:    data,
+	})
+}
+
+// cancel godoc
+//
+//	@Summary Cancel a hold
+//	@Description Cancel a hold. A copy set aside for it passes to the next patron. Members may only cancel their own.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Book's ID"
+//	@Param holdID path int true "Hold's ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /books/{id}/holds/{holdID} [delete]
+func (h *HoldHandler) cancel(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	holdID, err := strconv.ParseUint(c.Param("holdID"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+	if !h.hr.IsStaff(c) {
+		data, err := h.service.GetByID(c.Request.Context(), uint(id), uint(holdID))
+		if err != nil {
+			switch {
+			case errors.Is(err, exception.ErrUserNotFound):
+				c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+			default:
+				h.hr.ErrorInternalServer(c, err)
+			}
+			return
+		}
+		if !canActFor(c, h.hr, h.personService, uint(data.PersonID)) {
+			return
+		}
+	}
+
+	err = h.service.Cancel(c.Request.Context(), uint(id), uint(holdID))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(exception.ErrDataNotFound.Error()))
+		case errors.Is(err, exception.ErrHoldNotActive):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Pemesanan berhasil dibatalkan",
+	})
+}
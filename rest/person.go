@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
@@ -14,23 +15,25 @@ import (
 
 type PersonHandler struct {
 	hr      *server.Handler
-	service *service.PersonService
+	service service.PersonService
 }
 
 func NewPersonHandler(
 	hr *server.Handler,
-	personService *service.PersonService,
+	personService service.PersonService,
 ) *PersonHandler {
 	return &PersonHandler{hr: hr, service: personService}
 }
 
 func (h *PersonHandler) Route(app *gin.Engine) {
 	grp := app.Group(server.RootPerson)
-	grp.GET("", h.getList)
-	grp.GET("/:id", h.getByID)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.POST("", h.create)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.GET("", h.hr.Authenticate(domain.ScopePersonsRead), h.hr.RequireRole(staff...), h.getList)
+	grp.GET("/:id", h.hr.Authenticate(domain.ScopePersonsRead), h.getByID)
+	grp.PUT("/:id", h.hr.Authenticate(domain.ScopePersonsWrite), h.update)
+	grp.POST("", h.hr.Authenticate(domain.ScopePersonsWrite), h.hr.RequireRole(staff...), h.create)
+	grp.DELETE("/:id", h.hr.Authenticate(domain.ScopePersonsWrite), h.hr.RequireRole(staff...), h.delete)
+	grp.PUT(server.PathPersonAccount, h.hr.Authenticate(domain.ScopePersonsWrite), h.hr.RequireRole(domain.RoleAdmin), h.attachAccount)
+	grp.DELETE(server.PathPersonAccount, h.hr.Authenticate(domain.ScopePersonsWrite), h.hr.RequireRole(domain.RoleAdmin), h.detachAccount)
 }
 
 // getList godoc
@@ -38,9 +41,16 @@ func (h *PersonHandler) Route(app *gin.Engine) {
 //	@Summary Get a list of person
 //	@Description Get a list of person.
 //	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param q query string false "Person's name"
 //	@Param s query int false "Data offset"
 //	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by fullname, birth_date or created_at, comma-separated, - for descending"
+//	@Param gender query string false "Gender, eq or in"
+//	@Param birth_date query string false "Birth date, also birth_date[gte], [gt], [lte] and [lt]"
+//	@Param created_at query string false "Creation date, also created_at[gte], [gt], [lte] and [lt]"
+//	@Param has_account query bool false "Whether the person has an account"
 //	@Success 200 {object} dto.SuccessResponse[[]dto.PersonDetailResp]
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
@@ -49,12 +59,12 @@ func (h *PersonHandler) Route(app *gin.Engine) {
 //	@Router /persons [get]
 func (h *PersonHandler) getList(c *gin.Context) {
 	var req dto.Filter
-	if err := c.ShouldBindQuery(&req); err != nil {
+	if err := bindListQuery(c, &req, dto.PersonQuery); err != nil {
 		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	data, err := h.service.GetList(&req)
+	data, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
@@ -76,8 +86,10 @@ func (h *PersonHandler) getList(c *gin.Context) {
 // getByID godoc
 //
 //	@Summary Get a person's detail
-//	@Description Get a person's detail.
+//	@Description Get a person's detail. Members only see their own.
 //	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Person's ID"
 //	@Success 200 {object} dto.SuccessResponse[dto.PersonDetailResp]
 //	@Failure 400 {object} dto.ErrorResponse
@@ -92,8 +104,11 @@ func (h *PersonHandler) getByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
 		return
 	}
+	if !canActFor(c, h.hr, h.service, uint(id)) {
+		return
+	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrUserNotFound):
@@ -119,6 +134,7 @@ func (h *PersonHandler) getByID(c *gin.Context) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Person's ID"
 //	@Param detail body dto.PersonUpdateReq true "Person's detail"
 //	@Success 200 {object} dto.SuccessResponse[any]
@@ -136,6 +152,9 @@ func (h *PersonHandler) update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
 		return
 	}
+	if !canActFor(c, h.hr, h.service, uint(id)) {
+		return
+	}
 
 	var req dto.PersonUpdateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -144,7 +163,7 @@ func (h *PersonHandler) update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	err = h.service.Update(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDateParsing):
@@ -164,18 +183,19 @@ func (h *PersonHandler) update(c *gin.Context) {
 	})
 }
 
-
-//	@Summary Create a person
-//	@Description Create a person.
-//	@Accept json
-//	@Produce json
-//	@Param detail body dto.PersonCreateReq true "Person's detail"
-//	@Success 201 {object} dto.SuccessResponse[any]
-//	@Failure 401 {object} dto.ErrorResponse
-//	@Failure 403 {object} dto.ErrorResponse
-//	@Failure 422 {object} dto.ErrorResponse
-//	@Failure 500 {object} dto.ErrorResponse
-//	@Router /persons [post]
+// @Summary Create a person
+// @Description Create a person.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param detail body dto.PersonCreateReq true "Person's detail"
+// @Success 201 {object} dto.SuccessResponse[any]
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /persons [post]
 func (h *PersonHandler) create(c *gin.Context) {
 	var req dto.PersonCreateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -183,7 +203,7 @@ func (h *PersonHandler) create(c *gin.Context) {
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -195,7 +215,6 @@ func (h *PersonHandler) create(c *gin.Context) {
 	})
 }
 
-
 // @Summary Delete a Person by ID
 // @Description Deletes a Person entity by ID
 // @ID person-repository-delete
@@ -215,7 +234,7 @@ func (h *PersonHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -225,4 +244,100 @@ func (h *PersonHandler) delete(c *gin.Context) {
 		Success: true,
 		Message: "Data berhasil dihapus",
 	})
-}
\ No newline at end of file
+}
+
+// attachAccount godoc
+//
+//	@Summary Link a person to an account
+//	@Description Link an unlinked person to an account which has no person yet.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Person's ID"
+//	@Param detail body dto.PersonAccountLinkReq true "Account to link"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /persons/{id}/account [put]
+func (h *PersonHandler) attachAccount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	var req dto.PersonAccountLinkReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.ID = uint(id)
+
+	err = h.service.AttachAccount(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound),
+			errors.Is(err, exception.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrPersonLinked),
+			errors.Is(err, exception.ErrAccountLinked):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data orang berhasil dihubungkan ke akun",
+	})
+}
+
+// detachAccount godoc
+//
+//	@Summary Unlink a person from its account
+//	@Description Remove the link between a person and its account. Both records are kept.
+//	@Produce json
+//	@Security BearerAuth
+//	@Security APIKeyAuth
+//	@Param id path int true "Person's ID"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /persons/{id}/account [delete]
+func (h *PersonHandler) detachAccount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, h.hr.ErrorResponse("ID tidak valid"))
+		return
+	}
+
+	err = h.service.DetachAccount(c.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrPersonNotLinked):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Data orang berhasil dilepas dari akun",
+	})
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/server"
@@ -14,20 +15,20 @@ import (
 
 type PublisherHandler struct {
 	hr      *server.Handler
-	service *service.PublisherService
+	service service.PublisherService
 }
 
-func NewPublisherHandler(handler *server.Handler, publisherService *service.PublisherService) *PublisherHandler {
+func NewPublisherHandler(handler *server.Handler, publisherService service.PublisherService) *PublisherHandler {
 	return &PublisherHandler{hr: handler, service: publisherService}
 }
 
 func (h *PublisherHandler) Route(app *gin.Engine) {
 	grp := app.Group(server.RootPublisher)
-	grp.POST("", h.hr.AuthAccess(), h.create)
+	grp.POST("", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.create)
 	grp.GET("", h.getList)
 	grp.GET("/:id", h.getByID)
-	grp.PUT("/:id", h.hr.AuthAccess(), h.update)
-	grp.DELETE("/:id", h.hr.AuthAccess(), h.delete)
+	grp.PUT("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.update)
+	grp.DELETE("/:id", h.hr.Authenticate(domain.ScopeCatalogWrite), h.hr.RequireRole(staff...), h.delete)
 }
 
 // create godoc
@@ -37,6 +38,7 @@ func (h *PublisherHandler) Route(app *gin.Engine) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param detail body dto.PublisherUpdateReq true "Publisher's detail"
 //	@Success 201 {object} dto.SuccessResponse[any]
 //	@Failure 401 {object} dto.ErrorResponse
@@ -51,7 +53,7 @@ func (h *PublisherHandler) create(c *gin.Context) {
 		return
 	}
 
-	err := h.service.Create(&req)
+	err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
@@ -71,6 +73,9 @@ func (h *PublisherHandler) create(c *gin.Context) {
 //	@Param q query string false "Publisher's name"
 //	@Param s query int false "Data offset"
 //	@Param l query int false "Data limit"
+//	@Param sort query string false "Sort by name, city or created_at, comma-separated, - for descending"
+//	@Param city query string false "City, eq or in"
+//	@Param created_at query string false "Creation date, also created_at[gte], [gt], [lte] and [lt]"
 //	@Success 200 {object} dto.SuccessResponse[[]dto.PublisherResp]
 //	@Failure 422 {object} dto.ErrorResponse
 //	@Failure 404 {object} dto.ErrorResponse
@@ -79,12 +84,12 @@ func (h *PublisherHandler) create(c *gin.Context) {
 //	@Router /publishers [get]
 func (h *PublisherHandler) getList(c *gin.Context) {
 	var req dto.Filter
-	if err := c.ShouldBindQuery(&req); err != nil {
+	if err := bindListQuery(c, &req, dto.PublisherQuery); err != nil {
 		c.JSON(h.hr.BindingError(err))
 		return
 	}
 
-	data, err := h.service.GetList(&req)
+	data, err := h.service.GetList(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -123,7 +128,7 @@ func (h *PublisherHandler) getByID(c *gin.Context) {
 		return
 	}
 
-	data, err := h.service.GetByID(uint(id))
+	data, err := h.service.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -149,6 +154,7 @@ func (h *PublisherHandler) getByID(c *gin.Context) {
 //	@Accept json
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Publisher's ID"
 //	@Param detail body dto.PublisherUpdateReq true "Publisher's detail"
 //	@Success 200 {object} dto.SuccessResponse[any]
@@ -175,7 +181,7 @@ func (h *PublisherHandler) update(c *gin.Context) {
 	}
 	req.ID = uint(id)
 
-	err = h.service.Update(&req)
+	err = h.service.Update(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, exception.ErrDataNotFound):
@@ -199,6 +205,7 @@ func (h *PublisherHandler) update(c *gin.Context) {
 //	@Description Delete a publisher.
 //	@Produce json
 //	@Security BearerAuth
+//	@Security APIKeyAuth
 //	@Param id path int true "Publisher's ID"
 //	@Success 200 {object} dto.SuccessResponse[any]
 //	@Failure 400 {object} dto.ErrorResponse
@@ -216,7 +223,7 @@ func (h *PublisherHandler) delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(uint(id))
+	err = h.service.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		h.hr.ErrorInternalServer(c, err)
 		return
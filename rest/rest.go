@@ -1,40 +1,94 @@
 package rest
 
 import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/query"
 	"base-gin/server"
 	"base-gin/service"
+	"errors"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-var (
-	accountHandler   *AccountHandler
-	personHandler    *PersonHandler
-	publisherHandler *PublisherHandler
-	authorHandler 	 *AuthorHandler
-	bookHandler 	 *BookHandler
-	borrowingHandler *BorrowingHandler
-)
+// staff are the roles allowed to manage the catalogue and circulation.
+var staff = []domain.TypeRole{domain.RoleAdmin, domain.RoleLibrarian}
+
+// SetupRestHandlers builds the handlers of every resource and registers
+// their routes on app.
+func SetupRestHandlers(app *gin.Engine, hr *server.Handler, services *service.Services) {
+	handlers := []interface{ Route(app *gin.Engine) }{
+		NewAccountHandler(hr, services.Account, services.Person),
+		NewPersonHandler(hr, services.Person),
+		NewPublisherHandler(hr, services.Publisher),
+		NewAuthorHandler(hr, services.Author),
+		NewCategoryHandler(hr, services.Category),
+		NewBookHandler(hr, services.Book),
+		NewBookCopyHandler(hr, services.BookCopy),
+		NewBorrowingHandler(hr, services.Borrowing, services.Person),
+		NewFineHandler(hr, services.Fine, services.Person),
+		NewHoldHandler(hr, services.Hold, services.Person),
+		NewTwoFactorHandler(hr, services.TwoFactor),
+		NewAPIKeyHandler(hr, services.APIKey),
+		NewSearchHandler(hr, services.Search),
+		NewWellKnownHandler(hr),
+	}
+	for _, h := range handlers {
+		h.Route(app)
+	}
+}
 
-func SetupRestHandlers(app *gin.Engine) {
-	handler := server.GetHandler()
+// bindListQuery binds the query string to req, then parses the sort and
+// field filters of spec from the parameters req does not bind itself.
+func bindListQuery(c *gin.Context, req dto.ListQuery, spec query.Spec) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		return err
+	}
 
-	accountHandler = NewAccountHandler(
-		handler, service.GetAccountService(), service.GetPersonService())
-	personHandler = NewPersonHandler(handler, service.GetPersonService())
-	publisherHandler = NewPublisherHandler(handler, service.GetPublisherService())
-	authorHandler = NewAuthorHandler(handler, service.GetAuthorService())
-	bookHandler = NewBookHandler(handler, service.GetBookService())
-	borrowingHandler = NewBorrowingHandler(handler, service.GetBorrowingService())
+	params, err := spec.Parse(c.Request.URL.Query(), query.FormKeys(req)...)
+	if err != nil {
+		return err
+	}
+	req.ListFilter().Query = params
 
-	setupRoutes(app)
+	return nil
 }
 
-func setupRoutes(app *gin.Engine) {
-	accountHandler.Route(app)
-	personHandler.Route(app)
-	publisherHandler.Route(app)
-	authorHandler.Route(app)
-	bookHandler.Route(app)
-	borrowingHandler.Route(app)
+// ownPersonID returns the person linked to the logged-in member. Staff are
+// not tied to a single person, for them restricted is false.
+func ownPersonID(
+	c *gin.Context,
+	hr *server.Handler,
+	personService service.PersonService,
+) (personID uint, restricted bool, err error) {
+	if hr.IsStaff(c) {
+		return 0, false, nil
+	}
+
+	personID, err = personService.GetIDByAccountID(c.Request.Context(), c.GetUint(server.ParamTokenUserID))
+	return personID, true, err
+}
+
+// canActFor reports whether the logged-in account may act on behalf of the
+// given person: staff may act for anyone, members only for themselves. The
+// error response has already been written when it returns false.
+func canActFor(
+	c *gin.Context,
+	hr *server.Handler,
+	personService service.PersonService,
+	personID uint,
+) bool {
+	ownID, restricted, err := ownPersonID(c, hr, personService)
+	if err != nil && !errors.Is(err, exception.ErrUserNotFound) {
+		hr.ErrorInternalServer(c, err)
+		return false
+	}
+	if restricted && (err != nil || ownID != personID) {
+		c.JSON(http.StatusForbidden, hr.ErrorResponse(exception.ErrForbidden.Error()))
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	hr      *server.Handler
+	service service.SearchService
+}
+
+func NewSearchHandler(hr *server.Handler, searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{hr: hr, service: searchService}
+}
+
+func (h *SearchHandler) Route(app *gin.Engine) {
+	app.GET(server.RootSearch, h.search)
+}
+
+// search godoc
+//
+//	@Summary Search the catalogue
+//	@Description Search book titles, subtitles, contributors, publishers and ISBNs, and author and publisher names. All words of the query must match; results of every type are ranked together, best first. A query that is an ISBN-10 or ISBN-13 looks up that ISBN only.
+//	@Produce json
+//	@Param q query string true "Words to search for, or an ISBN"
+//	@Param type query string false "Result type" Enums(book, author, publisher)
+//	@Param l query int false "Data limit, 20 by default"
+//	@Success 200 {object} dto.SuccessResponse[[]dto.SearchResp]
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /search [get]
+func (h *SearchHandler) search(c *gin.Context) {
+	var req dto.SearchReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Search(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[[]dto.SearchResp]{
+		Success: true,
+		Message: "Hasil pencarian",
+		Data:    data,
+	})
+}
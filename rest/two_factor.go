@@ -0,0 +1,194 @@
+package rest
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/server"
+	"base-gin/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	hr      *server.Handler
+	service service.TwoFactorService
+}
+
+func NewTwoFactorHandler(hr *server.Handler, twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{hr: hr, service: twoFactorService}
+}
+
+func (h *TwoFactorHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootAccount)
+	grp.POST(server.PathTwoFactorSetup, h.hr.AuthAccess(), h.setup)
+	grp.POST(server.PathTwoFactorEnable, h.hr.AuthAccess(), h.enable)
+	grp.POST(server.PathTwoFactorDisable, h.hr.AuthAccess(), h.disable)
+	grp.PUT(
+		server.PathRoleTwoFactor,
+		h.hr.AuthAccess(),
+		h.hr.RequireRole(domain.RoleAdmin),
+		h.setRoleRequirement,
+	)
+}
+
+// setup godoc
+//
+//	@Summary Start 2FA enrollment
+//	@Description Generate a TOTP secret for the logged-in account. 2FA stays off until /accounts/2fa/enable confirms a code.
+//	@Produce json
+//	@Security BearerAuth
+//	@Success 200 {object} dto.SuccessResponse[dto.TwoFactorSetupResp]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/2fa/setup [post]
+func (h *TwoFactorHandler) setup(c *gin.Context) {
+	data, err := h.service.Setup(c.Request.Context(), c.GetUint(server.ParamTokenUserID))
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrTwoFactorEnabled):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.TwoFactorSetupResp]{
+		Success: true,
+		Message: "Pindai kode QR dengan aplikasi autentikator",
+		Data:    data,
+	})
+}
+
+// enable godoc
+//
+//	@Summary Enable 2FA
+//	@Description Confirm the secret from /accounts/2fa/setup with a current code.
+//	@Description The response lists single-use recovery codes; they are not shown again.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.TwoFactorEnableReq true "Code from the authenticator app"
+//	@Success 200 {object} dto.SuccessResponse[dto.TwoFactorRecoveryResp]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/2fa/enable [post]
+func (h *TwoFactorHandler) enable(c *gin.Context) {
+	var req dto.TwoFactorEnableReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	data, err := h.service.Enable(c.Request.Context(), c.GetUint(server.ParamTokenUserID), req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrTwoFactorCodeInvalid):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrTwoFactorEnabled),
+			errors.Is(err, exception.ErrTwoFactorNotSetup):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[dto.TwoFactorRecoveryResp]{
+		Success: true,
+		Message: "Verifikasi dua langkah berhasil diaktifkan",
+		Data:    data,
+	})
+}
+
+// disable godoc
+//
+//	@Summary Disable 2FA
+//	@Description Turn 2FA off for the logged-in account. Not allowed when the account's role requires 2FA.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param detail body dto.TwoFactorDisableReq true "Current password"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 400 {object} dto.ErrorResponse
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 409 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/2fa/disable [post]
+func (h *TwoFactorHandler) disable(c *gin.Context) {
+	var req dto.TwoFactorDisableReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+
+	err := h.service.Disable(c.Request.Context(), c.GetUint(server.ParamTokenUserID), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, exception.ErrPasswordMismatch):
+			c.JSON(http.StatusBadRequest, h.hr.ErrorResponse(err.Error()))
+		case errors.Is(err, exception.ErrTwoFactorNotSetup),
+			errors.Is(err, exception.ErrTwoFactorRequired):
+			c.JSON(http.StatusConflict, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Verifikasi dua langkah berhasil dinonaktifkan",
+	})
+}
+
+// setRoleRequirement godoc
+//
+//	@Summary Require 2FA for a role
+//	@Description Set whether accounts of the role must use 2FA. Accounts without 2FA enroll at their next login.
+//	@Accept json
+//	@Produce json
+//	@Security BearerAuth
+//	@Param role path string true "Role" Enums(admin, librarian, member)
+//	@Param detail body dto.RoleTwoFactorReq true "Requirement"
+//	@Success 200 {object} dto.SuccessResponse[any]
+//	@Failure 401 {object} dto.ErrorResponse
+//	@Failure 403 {object} dto.ErrorResponse
+//	@Failure 404 {object} dto.ErrorResponse
+//	@Failure 422 {object} dto.ErrorResponse
+//	@Failure 500 {object} dto.ErrorResponse
+//	@Router /accounts/roles/{role}/2fa [put]
+func (h *TwoFactorHandler) setRoleRequirement(c *gin.Context) {
+	var req dto.RoleTwoFactorReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(h.hr.BindingError(err))
+		return
+	}
+	req.Role = domain.TypeRole(c.Param("role"))
+
+	if err := h.service.SetRequired(c.Request.Context(), &req); err != nil {
+		switch {
+		case errors.Is(err, exception.ErrDataNotFound):
+			c.JSON(http.StatusNotFound, h.hr.ErrorResponse(err.Error()))
+		default:
+			h.hr.ErrorInternalServer(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse[any]{
+		Success: true,
+		Message: "Kebijakan peran berhasil diperbarui",
+	})
+}
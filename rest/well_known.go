@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"base-gin/server"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownHandler struct {
+	hr *server.Handler
+}
+
+func NewWellKnownHandler(hr *server.Handler) *WellKnownHandler {
+	return &WellKnownHandler{hr: hr}
+}
+
+func (h *WellKnownHandler) Route(app *gin.Engine) {
+	grp := app.Group(server.RootWellKnown)
+	grp.GET(server.PathJWKS, h.getJWKS)
+}
+
+// getJWKS godoc
+//
+//	@Summary JSON Web Key Set
+//	@Description Public keys that verify the tokens this service issues, identified by the token's kid header.
+//	@Description Empty while tokens are signed with a shared HS256 secret.
+//	@Produce json
+//	@Success 200 {object} util.JWKS
+//	@Router /.well-known/jwks.json [get]
+func (h *WellKnownHandler) getJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.hr.KeySet().JWKS())
+}
@@ -0,0 +1,33 @@
+package search
+
+import "context"
+
+// candidatesPerKind is how many matching documents of each kind the
+// database returns for ranking. The database orders them by a coarser score
+// than rank, so it is well above the number of hits asked for.
+const candidatesPerKind = 200
+
+// DatabaseIndex searches the full-text indexes of the catalogue tables. It
+// always sees the committed data and needs no upkeep.
+type DatabaseIndex struct {
+	source Source
+}
+
+func NewDatabaseIndex(source Source) *DatabaseIndex {
+	return &DatabaseIndex{source: source}
+}
+
+func (i *DatabaseIndex) Search(ctx context.Context, q Query, limit int) ([]Hit, error) {
+	if q.IsEmpty() {
+		return nil, nil
+	}
+
+	docs, err := i.source.Match(ctx, q, candidatesPerKind)
+	if err != nil {
+		return nil, err
+	}
+
+	return rank(docs, q, limit), nil
+}
+
+func (i *DatabaseIndex) Invalidate() {}
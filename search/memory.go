@@ -0,0 +1,142 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MemoryIndex keeps an inverted index of every document in the process. It
+// is built from the source on the first search and rebuilt on the first
+// search after Invalidate, which suits SQLite and single-instance setups
+// where the catalogue fits in memory.
+type MemoryIndex struct {
+	source Source
+
+	mu       sync.Mutex // serialises builds
+	snapshot *memorySnapshot
+	version  atomic.Uint64 // bumped by Invalidate
+}
+
+type memorySnapshot struct {
+	version uint64
+	docs    []Document
+	words   map[string][]int // word to the documents containing it
+	vocab   []string         // the keys of words, sorted for prefix lookups
+	isbns   map[string]int
+}
+
+func NewMemoryIndex(source Source) *MemoryIndex {
+	return &MemoryIndex{source: source}
+}
+
+func (i *MemoryIndex) Search(ctx context.Context, q Query, limit int) ([]Hit, error) {
+	if q.IsEmpty() {
+		return nil, nil
+	}
+
+	s, err := i.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rank(s.match(q), q, limit), nil
+}
+
+func (i *MemoryIndex) Invalidate() {
+	i.version.Add(1)
+}
+
+// current returns an index of the data as of the latest Invalidate. A build
+// that overlaps an Invalidate keeps the older version, so the next search
+// builds again.
+func (i *MemoryIndex) current(ctx context.Context) (*memorySnapshot, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	version := i.version.Load()
+	if i.snapshot != nil && i.snapshot.version == version {
+		return i.snapshot, nil
+	}
+
+	docs, err := i.source.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i.snapshot = newMemorySnapshot(version, docs)
+
+	return i.snapshot, nil
+}
+
+func newMemorySnapshot(version uint64, docs []Document) *memorySnapshot {
+	s := &memorySnapshot{
+		version: version,
+		docs:    docs,
+		words:   map[string][]int{},
+		isbns:   map[string]int{},
+	}
+
+	for n, doc := range docs {
+		seen := map[string]bool{}
+		for name, value := range doc.Fields {
+			if name == FieldISBN {
+				if value != "" {
+					s.isbns[value] = n
+				}
+				continue
+			}
+			for _, word := range tokenize(value) {
+				if !seen[word] {
+					seen[word] = true
+					s.words[word] = append(s.words[word], n)
+				}
+			}
+		}
+	}
+
+	s.vocab = make([]string, 0, len(s.words))
+	for word := range s.words {
+		s.vocab = append(s.vocab, word)
+	}
+	sort.Strings(s.vocab)
+
+	return s
+}
+
+// match returns the documents in which every term of q starts a word, as the
+// full-text indexes of the database do. The words starting with a term sit
+// next to each other in the sorted vocabulary, from where the term would be
+// inserted.
+func (s *memorySnapshot) match(q Query) []Document {
+	if q.ISBN != "" {
+		if n, ok := s.isbns[q.ISBN]; ok {
+			return []Document{s.docs[n]}
+		}
+		return nil
+	}
+
+	var found map[int]bool
+	for _, term := range q.Terms {
+		docs := map[int]bool{}
+		for i := sort.SearchStrings(s.vocab, term); i < len(s.vocab) && strings.HasPrefix(s.vocab[i], term); i++ {
+			for _, n := range s.words[s.vocab[i]] {
+				if found == nil || found[n] {
+					docs[n] = true
+				}
+			}
+		}
+		found = docs
+		if len(found) == 0 {
+			return nil
+		}
+	}
+
+	docs := make([]Document, 0, len(found))
+	for n := range found {
+		docs = append(docs, s.docs[n])
+	}
+
+	return docs
+}
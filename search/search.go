@@ -0,0 +1,278 @@
+// Package search finds books, authors and publishers by free text. An Index
+// picks the candidate documents for a query; ranking and highlighting are
+// shared, so every index returns the same results in the same order.
+package search
+
+import (
+	"base-gin/config"
+	"base-gin/util"
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	DriverDatabase = "database"
+	DriverMemory   = "memory"
+)
+
+type Kind string
+
+const (
+	KindBook      Kind = "book"
+	KindAuthor    Kind = "author"
+	KindPublisher Kind = "publisher"
+)
+
+// maxTerms bounds the number of words of a query that are searched for.
+const maxTerms = 8
+
+// Field names of the documents. A match in a heavier field ranks higher.
+const (
+	FieldTitle        = "title"
+	FieldSubtitle     = "subtitle"
+	FieldContributors = "contributors"
+	FieldPublisher    = "publisher"
+	FieldISBN         = "isbn"
+	FieldName         = "name"
+)
+
+var fieldWeights = map[string]float64{
+	FieldTitle:        4,
+	FieldName:         4,
+	FieldISBN:         4,
+	FieldSubtitle:     2,
+	FieldContributors: 2,
+	FieldPublisher:    1,
+}
+
+// FieldWeight is how much a match in the field counts, for sources that
+// order their candidates the way they will be ranked.
+func FieldWeight(name string) float64 {
+	return fieldWeights[name]
+}
+
+// Document is what gets searched: one book, author or publisher with its
+// searchable text. Label is the text results are shown with.
+type Document struct {
+	Kind   Kind
+	ID     uint
+	Label  string
+	Fields map[string]string
+}
+
+// Hit is a document that matched, with the fields that matched rewritten to
+// HTML in which every match is wrapped in <em>.
+type Hit struct {
+	Kind       Kind
+	ID         uint
+	Label      string
+	Score      float64
+	Highlights map[string]string
+}
+
+// Query is a parsed search. A query that reads as an ISBN looks up that ISBN
+// only; anything else is split into words, each of which must start a word
+// of the document.
+type Query struct {
+	Terms []string
+	ISBN  string // ISBN-13
+	Kind  Kind   // empty for every kind
+}
+
+func ParseQuery(text string, kind Kind) Query {
+	q := Query{Kind: kind}
+	if isbn, err := util.NormalizeISBN(strings.TrimSpace(text)); err == nil {
+		q.ISBN = isbn
+		return q
+	}
+
+	seen := map[string]bool{}
+	for _, term := range tokenize(text) {
+		if !seen[term] && len(q.Terms) < maxTerms {
+			seen[term] = true
+			q.Terms = append(q.Terms, term)
+		}
+	}
+
+	return q
+}
+
+func (q Query) IsEmpty() bool {
+	return q.ISBN == "" && len(q.Terms) == 0
+}
+
+// Source supplies the documents an index searches.
+type Source interface {
+	// Match returns the documents in which every term of q starts a word of
+	// some field, or whose ISBN is q.ISBN: at most limit of each kind, those
+	// that would rank best first.
+	Match(ctx context.Context, q Query, limit int) ([]Document, error)
+	// All returns every document.
+	All(ctx context.Context) ([]Document, error)
+}
+
+type Index interface {
+	// Search returns at most limit hits, best first.
+	Search(ctx context.Context, q Query, limit int) ([]Hit, error)
+	// Invalidate tells the index the catalogue has changed. It is called
+	// after the change is committed.
+	Invalidate()
+}
+
+// New returns the index selected by SEARCH_DRIVER.
+func New(cfg *config.Config, source Source) (Index, error) {
+	switch cfg.Search.Driver {
+	case DriverDatabase:
+		return NewDatabaseIndex(source), nil
+	case DriverMemory:
+		return NewMemoryIndex(source), nil
+	default:
+		return nil, fmt.Errorf("search: unknown driver %q", cfg.Search.Driver)
+	}
+}
+
+// rank scores docs against q and returns the best limit of those that match.
+func rank(docs []Document, q Query, limit int) []Hit {
+	hits := []Hit{}
+	for _, doc := range docs {
+		if q.Kind != "" && doc.Kind != q.Kind {
+			continue
+		}
+		if hit, ok := score(doc, q); ok {
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Label != hits[j].Label {
+			return hits[i].Label < hits[j].Label
+		}
+		if hits[i].Kind != hits[j].Kind {
+			return hits[i].Kind < hits[j].Kind
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+// score rates how well doc matches q. A term counts more where it is a whole
+// word of a field than where it only starts one; the rating of each field is
+// multiplied by the field's weight.
+func score(doc Document, q Query) (Hit, bool) {
+	hit := Hit{Kind: doc.Kind, ID: doc.ID, Label: doc.Label, Highlights: map[string]string{}}
+
+	if q.ISBN != "" {
+		if doc.Fields[FieldISBN] != q.ISBN {
+			return hit, false
+		}
+		hit.Score = 2 * fieldWeights[FieldISBN]
+		hit.Highlights[FieldISBN] = highlight(q.ISBN, []string{q.ISBN})
+		return hit, true
+	}
+
+	matched := map[string][]string{}
+	for _, term := range q.Terms {
+		termScore := 0.0
+		for name, value := range doc.Fields {
+			if name == FieldISBN {
+				continue // only a whole ISBN is looked up
+			}
+			best := 0.0
+			for _, word := range tokenize(value) {
+				switch {
+				case word == term:
+					best = 2
+				case strings.HasPrefix(word, term) && best < 1.5:
+					best = 1.5
+				}
+			}
+			if best > 0 {
+				termScore += best * fieldWeights[name]
+				matched[name] = append(matched[name], term)
+			}
+		}
+		if termScore == 0 {
+			return hit, false
+		}
+		hit.Score += termScore
+	}
+
+	// the words of the query side by side weigh as much as one more word
+	if len(q.Terms) > 1 {
+		phrase := " " + strings.Join(q.Terms, " ")
+		for name, value := range doc.Fields {
+			if strings.Contains(" "+strings.Join(tokenize(value), " "), phrase) {
+				hit.Score += 2 * fieldWeights[name]
+			}
+		}
+	}
+
+	for name, terms := range matched {
+		hit.Highlights[name] = highlight(doc.Fields[name], terms)
+	}
+
+	return hit, true
+}
+
+// tokenize splits text into lower-case words of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// highlight escapes text for HTML and wraps every case-insensitive
+// occurrence of terms at the start of a word in <em>.
+func highlight(text string, terms []string) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		t := []rune(term)
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if i > 0 && isWordRune(lower[i-1]) {
+				continue
+			}
+			if string(lower[i:i+len(t)]) == term {
+				for j := i; j < i+len(t); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && marked[j] == marked[i] {
+			j++
+		}
+		part := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			part = "<em>" + part + "</em>"
+		}
+		b.WriteString(part)
+		i = j
+	}
+
+	return b.String()
+}
@@ -2,16 +2,22 @@ package server
 
 import (
 	"base-gin/config"
+	"base-gin/domain"
 	"base-gin/domain/dto"
 	"base-gin/exception"
+	"base-gin/query"
 	"base-gin/repository"
 	"base-gin/util"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -25,6 +31,10 @@ import (
 	"github.com/mssola/user_agent"
 )
 
+// StatusClientClosedRequest is answered when the client disconnected before
+// the response was ready. Nobody reads it; it only shows up in access logs.
+const StatusClientClosedRequest = 499
+
 var (
 	ErrRequestThrottled = errors.New("ratelimit")
 )
@@ -36,13 +46,25 @@ type BindingErrorMessage struct {
 
 type Handler struct {
 	cfg         config.Config
+	keys        *util.KeySet
 	idValidator ut.Translator
-	accountRepo *repository.AccountRepository
+	accountRepo repository.AccountRepository
+	sessionRepo repository.SessionRepository
+	apiKeyRepo  repository.APIKeyRepository
+
+	loginThrottle *LoginThrottle
 }
 
+// NewHandler builds the shared request helpers. throttleStore keeps the
+// login throttle's counters; deployments running more than one instance
+// should pass a shared one.
 func NewHandler(
 	cfg *config.Config,
-	accountRepo *repository.AccountRepository,
+	keys *util.KeySet,
+	accountRepo repository.AccountRepository,
+	sessionRepo repository.SessionRepository,
+	apiKeyRepo repository.APIKeyRepository,
+	throttleStore ThrottleStore,
 ) *Handler {
 	var idValidator ut.Translator
 
@@ -57,11 +79,36 @@ func NewHandler(
 	}
 	return &Handler{
 		cfg:         *cfg,
+		keys:        keys,
 		idValidator: idValidator,
 		accountRepo: accountRepo,
+		sessionRepo: sessionRepo,
+		apiKeyRepo:  apiKeyRepo,
+
+		loginThrottle: NewLoginThrottle(cfg, throttleStore),
 	}
 }
 
+// KeySet returns the keys tokens are signed and verified with.
+func (h *Handler) KeySet() *util.KeySet {
+	return h.keys
+}
+
+func (h *Handler) LoginThrottle() *LoginThrottle {
+	return h.loginThrottle
+}
+
+// ErrorThrottled responds with 429 and a Retry-After header in whole
+// seconds.
+func (h *Handler) ErrorThrottled(c *gin.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+		Success: false,
+		Message: fmt.Sprintf("terlalu banyak percobaan, coba lagi dalam %d detik", seconds),
+	})
+}
+
 func (h *Handler) BindingError(err error) (int, dto.ErrorResponse) {
 	var ve validator.ValidationErrors
 	if errors.As(err, &ve) {
@@ -79,6 +126,21 @@ func (h *Handler) BindingError(err error) (int, dto.ErrorResponse) {
 			Errors:  messageBag,
 		}
 	}
+	var qe query.Errors
+	if errors.As(err, &qe) {
+		messageBag := make([]BindingErrorMessage, len(qe))
+		for i, fe := range qe {
+			messageBag[i] = BindingErrorMessage{
+				Field:   fe.Field,
+				Message: fe.Message,
+			}
+		}
+		return http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Success: false,
+			Message: "Validasi error",
+			Errors:  messageBag,
+		}
+	}
 	log.Error().Err(err).Msg("Handler.BindingError")
 	return http.StatusBadRequest, dto.ErrorResponse{
 		Success: false,
@@ -94,12 +156,27 @@ func (h *Handler) ErrorResponse(message string) dto.ErrorResponse {
 	}
 }
 
+// ErrorInternalServer responds to an unexpected error. A request whose
+// client went away or whose query ran out of time is logged apart from real
+// server errors, so cancellations do not read as database failures.
 func (h *Handler) ErrorInternalServer(c *gin.Context, err error) {
-	log.Error().Err(err).Msg("Handler.ErrorIntenalServer")
-	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-		Success: false,
-		Message: "terdapat kesalahan server",
-	})
+	switch {
+	case errors.Is(err, context.Canceled) || c.Request.Context().Err() != nil:
+		log.Warn().Err(err).Str("path", c.FullPath()).Msg("Handler.RequestCanceled")
+		c.AbortWithStatus(StatusClientClosedRequest)
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Warn().Err(err).Str("path", c.FullPath()).Msg("Handler.QueryTimeout")
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+			Success: false,
+			Message: "waktu pemrosesan habis, silakan coba lagi",
+		})
+	default:
+		log.Error().Err(err).Msg("Handler.ErrorIntenalServer")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Message: "terdapat kesalahan server",
+		})
+	}
 }
 
 func (h *Handler) verifyAuthAccessToken(r *http.Request) (jwt.MapClaims, error) {
@@ -107,7 +184,7 @@ func (h *Handler) verifyAuthAccessToken(r *http.Request) (jwt.MapClaims, error)
 	if len(strArr) != 2 {
 		return nil, exception.ErrBearerTokenInvalid
 	}
-	return util.VerifyAuthAccessToken(h.cfg, strArr[1])
+	return util.VerifyAuthAccessToken(h.keys, strArr[1])
 }
 
 func (h *Handler) verifyAuthRefreshToken(r *http.Request) (jwt.MapClaims, error) {
@@ -117,7 +194,7 @@ func (h *Handler) verifyAuthRefreshToken(r *http.Request) (jwt.MapClaims, error)
 		return nil, exception.ErrBearerTokenInvalid
 	}
 
-	return util.VerifyAuthRefreshToken(h.cfg, strArr[1])
+	return util.VerifyAuthRefreshToken(h.keys, strArr[1])
 }
 
 func (h *Handler) AuthAccess() gin.HandlerFunc {
@@ -132,7 +209,22 @@ func (h *Handler) AuthAccess() gin.HandlerFunc {
 			return
 		}
 
-		account, err := h.accountRepo.GetByUsername(token["sub"].(string))
+		sessionID, _ := token["sid"].(string)
+		revoked, err := h.sessionRepo.IsRevoked(c.Request.Context(), sessionID)
+		if err != nil {
+			h.ErrorInternalServer(c, err)
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Success: false,
+				Message: exception.ErrSessionRevoked.Error(),
+			})
+			return
+		}
+
+		account, err := h.accountRepo.GetByUsername(c.Request.Context(), token["sub"].(string))
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
 				Success: false,
@@ -150,10 +242,97 @@ func (h *Handler) AuthAccess() gin.HandlerFunc {
 
 		c.Set(ParamTokenUserID, account.ID)
 		c.Set(ParamTokenUsername, account.Username)
+		c.Set(ParamTokenSession, sessionID)
+		// The role is read from the database rather than the token's claim
+		// so that a demotion takes effect without waiting for expiry.
+		c.Set(ParamTokenRole, account.Role)
 		c.Next()
 	}
 }
 
+// Authenticate accepts an API key holding scope, sent in the X-API-Key
+// header, and otherwise behaves as AuthAccess. Either way the request acts
+// as the owning account, so RequireRole still applies to it.
+func (h *Handler) Authenticate(scope domain.TypeScope) gin.HandlerFunc {
+	authAccess := h.AuthAccess()
+	return func(c *gin.Context) {
+		if c.GetHeader(HeaderAPIKey) == "" {
+			authAccess(c)
+			return
+		}
+
+		h.authAPIKey(c, scope)
+	}
+}
+
+func (h *Handler) authAPIKey(c *gin.Context, scope domain.TypeScope) {
+	key, err := h.apiKeyRepo.GetByHash(c.Request.Context(), util.SHA256Hex(c.GetHeader(HeaderAPIKey)))
+	if err != nil && !errors.Is(err, exception.ErrAPIKeyInvalid) {
+		h.ErrorInternalServer(c, err)
+		c.Abort()
+		return
+	}
+
+	now := time.Now().UTC()
+	if err != nil || !key.IsActive(now) || key.Account == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Message: exception.ErrAPIKeyInvalid.Error(),
+		})
+		return
+	}
+	if !key.HasScope(scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Success: false,
+			Message: exception.ErrAPIKeyScope.Error(),
+		})
+		return
+	}
+
+	if err := h.apiKeyRepo.Touch(c.Request.Context(), key.ID, c.ClientIP(), now); err != nil {
+		exception.LogError(err, "Handler.authAPIKey")
+	}
+
+	c.Set(ParamTokenUserID, key.Account.ID)
+	c.Set(ParamTokenUsername, key.Account.Username)
+	c.Set(ParamTokenRole, key.Account.Role)
+	c.Set(ParamAPIKeyID, key.ID)
+	c.Next()
+}
+
+// RequireRole lets the request through only when the account resolved by
+// AuthAccess or Authenticate holds one of the given roles, so it must be chained after it.
+func (h *Handler) RequireRole(roles ...domain.TypeRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := h.Role(c)
+		for _, r := range roles {
+			if r == role {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Success: false,
+			Message: exception.ErrForbidden.Error(),
+		})
+	}
+}
+
+// Role returns the role of the account resolved by AuthAccess, or an empty
+// role on routes that are not authenticated.
+func (h *Handler) Role(c *gin.Context) domain.TypeRole {
+	role, _ := c.Get(ParamTokenRole)
+	r, _ := role.(domain.TypeRole)
+	return r
+}
+
+// IsStaff reports whether the logged-in account is an admin or librarian.
+func (h *Handler) IsStaff(c *gin.Context) bool {
+	role := h.Role(c)
+	return role == domain.RoleAdmin || role == domain.RoleLibrarian
+}
+
 func (h *Handler) AuthRefresh() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, err := h.verifyAuthRefreshToken(c.Request)
@@ -166,6 +345,7 @@ func (h *Handler) AuthRefresh() gin.HandlerFunc {
 			return
 		}
 		c.Set(ParamTokenUsername, token["sub"])
+		c.Set(ParamTokenID, token["jti"])
 		c.Next()
 	}
 }
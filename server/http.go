@@ -1,10 +1,10 @@
 package server
 
 import (
-	"base-gin/config"
-	"base-gin/repository"
+	"base-gin/util"
 	"context"
 	"errors"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -23,21 +23,20 @@ const (
 	ParamTokenUser     = "x-token-user"
 	ParamTokenUserID   = "x-token-user-id"
 	ParamTokenUsername = "x-token-uname"
-)
+	ParamTokenRole     = "x-token-role"
+	ParamTokenID       = "x-token-id"
+	ParamTokenSession  = "x-token-sid"
+	ParamAPIKeyID      = "x-apikey-id"
 
-var (
-	handler *Handler
+	HeaderAPIKey = "X-API-Key"
 )
 
-func Init(
-	cfg *config.Config,
-	accountRepo *repository.AccountRepository,
-) *gin.Engine {
+// NewEngine returns a router with the middleware every instance uses.
+func NewEngine() *gin.Engine {
 	app := gin.New()
 	app.Use(gin.Recovery())       // panic handling
 	registerCustomValidationTag() // returns json field name on errors
-
-	handler = NewHandler(cfg, accountRepo)
+	registerCustomValidation()
 
 	return app
 }
@@ -54,8 +53,28 @@ func registerCustomValidationTag() {
 	}
 }
 
+// registerCustomValidation adds the validation tags this app defines. isbn
+// replaces the built-in tag of that name, so binding accepts exactly what
+// util.NormalizeISBN does, and keeps its translated message.
+func registerCustomValidation() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		err := v.RegisterValidation("isbn", func(fl validator.FieldLevel) bool {
+			_, err := util.NormalizeISBN(fl.Field().String())
+			return err == nil
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("registerCustomValidation")
+		}
+	}
+}
+
 func Serve(handler http.Handler) {
+	// Requests derive their context from base, so queries still running
+	// when the shutdown grace period ends are cancelled with it.
+	base, cancelBase := context.WithCancel(context.Background())
+
 	srv := &http.Server{
+		BaseContext:       func(net.Listener) context.Context { return base },
 		Addr:              os.Getenv("SERVER_ADDRESS"),
 		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
@@ -82,14 +101,7 @@ func Serve(handler http.Handler) {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Error().Stack().Err(err).Msg("Graceful Errors: Server forced to shutdown")
 	}
+	cancelBase()
 
 	log.Info().Msg("Graceful Info: Server exiting")
 }
-
-func GetHandler() *Handler {
-	if handler == nil {
-		panic("handler is no initialised")
-	}
-
-	return handler
-}
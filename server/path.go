@@ -3,12 +3,52 @@ package server
 const (
 	rootPath = "/v1"
 
-	RootAccount   = rootPath + "/accounts"
-	RootPerson    = rootPath + "/persons"
-	RootPublisher = rootPath + "/publishers"
-	RootAuthor = rootPath + "/authors"
-	RootBook = rootPath + "/books"
-	RootBorrowing = rootPath + "/borrowings"
-
-	PathLogin = "/login"
+	RootWellKnown = "/.well-known"
+	PathJWKS      = "/jwks.json"
+
+	RootAccount    = rootPath + "/accounts"
+	RootPerson     = rootPath + "/persons"
+	RootPersonFine = RootPerson + "/:id/fines"
+	RootPublisher  = rootPath + "/publishers"
+	RootAuthor     = rootPath + "/authors"
+	RootAuthorBook = RootAuthor + "/:id/books"
+	RootBook       = rootPath + "/books"
+	RootBookCopy   = RootBook + "/:id/copies"
+	RootBookHold   = RootBook + "/:id/holds"
+	RootBorrowing  = rootPath + "/borrowings"
+	RootCategory   = rootPath + "/categories"
+	RootSearch     = rootPath + "/search"
+
+	PathLogin    = "/login"
+	PathRegister = "/register"
+	PathRefresh  = "/refresh"
+	PathRole     = "/:id/role"
+
+	PathLoginTwoFactor      = "/login/2fa"
+	PathLoginTwoFactorSetup = "/login/2fa/setup"
+	PathTwoFactorSetup      = "/2fa/setup"
+	PathTwoFactorEnable     = "/2fa/enable"
+	PathTwoFactorDisable    = "/2fa/disable"
+	PathRoleTwoFactor       = "/roles/:role/2fa"
+
+	PathAPIKeys = "/api-keys"
+	PathAPIKey  = "/api-keys/:keyID"
+
+	PathSessions        = "/sessions"
+	PathLogout          = "/logout"
+	PathLogoutAll       = "/logout-all"
+	PathAccountSessions = "/:id/sessions"
+
+	PathPassword       = "/password"
+	PathPasswordForgot = "/password/forgot"
+	PathPasswordReset  = "/password/reset"
+	PathOverdue        = "/overdue"
+	PathRenew          = "/:id/renew"
+	PathReturn         = "/:id/return"
+
+	PathPersonAccount = "/:id/account"
+
+	PathReturnByBook = "/return-by-book/:barcode"
+	PathPayment      = "/payments"
+	PathWaive        = "/:fineID/waive"
 )
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"base-gin/config"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThrottleEntry is the state of one throttling counter.
+type ThrottleEntry struct {
+	Count   int
+	ResetAt time.Time
+}
+
+// ThrottleStore keeps throttling counters. Counters use a fixed window that
+// starts with the first hit and lasts for the given duration. Deployments
+// running more than one instance should plug in a shared store.
+type ThrottleStore interface {
+	// Take counts a hit on key unless the counter has already reached
+	// limit. The check and the increment are one step, so concurrent hits
+	// cannot all get in under the limit.
+	Take(key string, limit int, window time.Duration) (entry ThrottleEntry, ok bool, err error)
+	// Release gives back a hit counted by Take.
+	Release(key string) error
+	Reset(key string) error
+}
+
+// MemoryThrottleStore is an in-process ThrottleStore.
+type MemoryThrottleStore struct {
+	mu      sync.Mutex
+	entries map[string]ThrottleEntry
+}
+
+func NewMemoryThrottleStore() *MemoryThrottleStore {
+	return &MemoryThrottleStore{entries: make(map[string]ThrottleEntry)}
+}
+
+func (s *MemoryThrottleStore) Take(key string, limit int, window time.Duration) (ThrottleEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.entries[key]
+	if !ok || !entry.ResetAt.After(now) {
+		s.prune(now)
+		entry = ThrottleEntry{ResetAt: now.Add(window)}
+	}
+	if entry.Count >= limit {
+		return entry, false, nil
+	}
+	entry.Count++
+	s.entries[key] = entry
+
+	return entry, true, nil
+}
+
+func (s *MemoryThrottleStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if entry.Count <= 1 {
+		delete(s.entries, key)
+		return nil
+	}
+	entry.Count--
+	s.entries[key] = entry
+
+	return nil
+}
+
+func (s *MemoryThrottleStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+// prune drops lapsed counters so the map does not grow with every client
+// ever seen. The caller must hold the lock.
+func (s *MemoryThrottleStore) prune(now time.Time) {
+	for k, v := range s.entries {
+		if !v.ResetAt.After(now) {
+			delete(s.entries, k)
+		}
+	}
+}
+
+// LoginThrottle limits failed logins per username and per client IP.
+//
+// Every attempt is counted up front by Allow, so a burst of concurrent
+// attempts cannot all pass the check before any of them has failed. An
+// attempt counts as failed unless it ends with Succeed or Release.
+type LoginThrottle struct {
+	store      ThrottleStore
+	maxAttempt int
+	window     time.Duration
+}
+
+func NewLoginThrottle(cfg *config.Config, store ThrottleStore) *LoginThrottle {
+	return &LoginThrottle{
+		store:      store,
+		maxAttempt: cfg.AuthN.LoginMaxAttempt,
+		window:     time.Duration(cfg.AuthN.LoginThrottleTTL) * time.Second,
+	}
+}
+
+func userKey(username string) string {
+	return "login:user:" + strings.ToLower(username)
+}
+
+func ipKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// Allow counts an attempt against both counters. It returns
+// ErrRequestThrottled, along with how long the client has to wait, once
+// either counter has reached the limit; the attempt is then not counted.
+func (t *LoginThrottle) Allow(username, ip string) (time.Duration, error) {
+	if t.maxAttempt <= 0 {
+		return 0, nil
+	}
+
+	var taken []string
+	var retryAfter time.Duration
+	for _, key := range []string{userKey(username), ipKey(ip)} {
+		entry, ok, err := t.store.Take(key, t.maxAttempt, t.window)
+		if err != nil {
+			_ = t.release(taken)
+			return 0, err
+		}
+		if ok {
+			taken = append(taken, key)
+		} else if wait := time.Until(entry.ResetAt); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+	if retryAfter > 0 {
+		_ = t.release(taken)
+		return retryAfter, ErrRequestThrottled
+	}
+
+	return 0, nil
+}
+
+// Succeed clears the username's counter after a successful login and gives
+// back the attempt on the IP's. Failures from the same IP still count, so
+// logging into an account of one's own does not clear the way for guessing
+// at others.
+func (t *LoginThrottle) Succeed(username, ip string) error {
+	if t.maxAttempt <= 0 {
+		return nil
+	}
+	if err := t.store.Reset(userKey(username)); err != nil {
+		return err
+	}
+
+	return t.store.Release(ipKey(ip))
+}
+
+// Release gives back an attempt that ended without the credentials being
+// checked, e.g. on a server error.
+func (t *LoginThrottle) Release(username, ip string) error {
+	if t.maxAttempt <= 0 {
+		return nil
+	}
+
+	return t.release([]string{userKey(username), ipKey(ip)})
+}
+
+func (t *LoginThrottle) release(keys []string) error {
+	for _, key := range keys {
+		if err := t.store.Release(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,36 +2,90 @@ package service
 
 import (
 	"base-gin/config"
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
+	"base-gin/mailer"
 	"base-gin/repository"
 	"base-gin/util"
-	"base-gin/domain/dao"
+	"context"
 	"errors"
+	"fmt"
+	"time"
 
-
+	"github.com/google/uuid"
 	"gorm.io/gorm"
-	"github.com/gin-gonic/gin"
 )
 
-type AccountService struct {
-	cfg  *config.Config
-	repo *repository.AccountRepository
+type AccountService interface {
+	Login(ctx context.Context, p dto.AccountLoginReq, client dto.ClientInfo) (dto.AccountLoginResp, error)
+	VerifyChallenge(ctx context.Context, challengeToken string) (string, error)
+	LoginTwoFactorSetup(ctx context.Context, username string) (dto.TwoFactorSetupResp, error)
+	LoginTwoFactor(ctx context.Context, username, code string, client dto.ClientInfo) (dto.AccountLoginResp, error)
+	Refresh(ctx context.Context, username, tokenID string) (dto.AccountLoginResp, error)
+	Register(ctx context.Context, params *dto.AccountRegisterReq) (dto.AccountRegisterResp, error)
+	Create(ctx context.Context, params dto.AccountCreateReq) (*dao.Account, error)
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*dao.Account, error)
+	GetAccountByID(ctx context.Context, id uint) (*dao.Account, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dao.Account, error)
+	GetSessionList(ctx context.Context, accountID uint, currentSessionID string) ([]dto.SessionResp, error)
+	Logout(ctx context.Context, accountID uint, sessionID string) error
+	LogoutAll(ctx context.Context, accountID uint) (int, error)
+	UpdateRole(ctx context.Context, params *dto.AccountRoleUpdateReq) error
+	Update(ctx context.Context, params *dto.AccountUpdateReq) (dao.Account, error)
+	ChangePassword(ctx context.Context, accountID uint, params *dto.AccountPasswordChangeReq) error
+	ForgotPassword(ctx context.Context, params *dto.AccountPasswordForgotReq) error
+	ResetPassword(ctx context.Context, params *dto.AccountPasswordResetReq) error
+}
+
+type accountService struct {
+	cfg               *config.Config
+	keys              *util.KeySet
+	uow               repository.UnitOfWork
+	repo              repository.AccountRepository
+	refreshTokenRepo  repository.RefreshTokenRepository
+	sessionRepo       repository.SessionRepository
+	passwordResetRepo repository.PasswordResetRepository
+	twoFactor         TwoFactorService
+	mailer            mailer.Mailer
 }
 
 func NewAccountService(
 	cfg *config.Config,
-	accountRepo *repository.AccountRepository,
-) *AccountService {
-	return &AccountService{cfg: cfg, repo: accountRepo}
+	keys *util.KeySet,
+	uow repository.UnitOfWork,
+	accountRepo repository.AccountRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.SessionRepository,
+	passwordResetRepo repository.PasswordResetRepository,
+	twoFactor TwoFactorService,
+	mail mailer.Mailer,
+) AccountService {
+	return &accountService{
+		cfg:               cfg,
+		keys:              keys,
+		uow:               uow,
+		repo:              accountRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		sessionRepo:       sessionRepo,
+		passwordResetRepo: passwordResetRepo,
+		twoFactor:         twoFactor,
+		mailer:            mail,
+	}
 }
 
+func (s *accountService) refreshTTL() time.Duration {
+	return time.Duration(s.cfg.AuthN.JWTRefreshTTL) * time.Second
+}
 
-
-func (s *AccountService) Login(p dto.AccountLoginReq) (dto.AccountLoginResp, error) {
+// Login verifies the credential and opens a new session for the client.
+// Accounts with 2FA enabled, or whose role requires it, get a challenge
+// token instead and finish with LoginTwoFactor.
+func (s *accountService) Login(ctx context.Context, p dto.AccountLoginReq, client dto.ClientInfo) (dto.AccountLoginResp, error) {
 	var resp dto.AccountLoginResp
 
-	item, err := s.repo.GetByUsername(p.Username)
+	item, err := s.repo.GetByUsername(ctx, p.Username)
 	if err != nil {
 		return resp, err
 	}
@@ -40,12 +94,150 @@ func (s *AccountService) Login(p dto.AccountLoginReq) (dto.AccountLoginResp, err
 		return resp, exception.ErrUserLoginFailed
 	}
 
-	aToken, err := util.CreateAuthAccessToken(*s.cfg, item.Username)
+	required, err := s.twoFactor.IsRequired(ctx, item.Role)
+	if err != nil {
+		return resp, err
+	}
+	if item.HasTwoFactor() || required {
+		token, err := util.CreateAuthChallengeToken(s.keys, item.Username)
+		if err != nil {
+			return resp, err
+		}
+
+		resp.ChallengeToken = token
+		resp.TwoFactorSetup = !item.HasTwoFactor()
+
+		return resp, nil
+	}
+
+	return s.openSession(ctx, &item, client)
+}
+
+// VerifyChallenge returns the username a login challenge token was issued
+// for.
+func (s *accountService) VerifyChallenge(ctx context.Context, challengeToken string) (string, error) {
+	claims, err := util.VerifyAuthChallengeToken(s.keys, challengeToken)
+	if err != nil {
+		return "", exception.ErrChallengeInvalid
+	}
+
+	username, _ := claims["sub"].(string)
+	if username == "" {
+		return "", exception.ErrChallengeInvalid
+	}
+
+	return username, nil
+}
+
+// LoginTwoFactorSetup starts enrollment for an account whose role requires
+// 2FA but which has not enabled it yet.
+func (s *accountService) LoginTwoFactorSetup(ctx context.Context, username string) (dto.TwoFactorSetupResp, error) {
+	item, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return dto.TwoFactorSetupResp{}, err
+	}
+
+	return s.twoFactor.Setup(ctx, item.ID)
+}
+
+// LoginTwoFactor completes a challenged login. For an enrolled account code
+// is a TOTP or recovery code; during enrollment it confirms the new secret
+// and the response also carries the recovery codes.
+func (s *accountService) LoginTwoFactor(ctx context.Context,
+	username, code string,
+	client dto.ClientInfo,
+) (dto.AccountLoginResp, error) {
+	var resp dto.AccountLoginResp
+
+	item, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return resp, err
+	}
+
+	var recoveryCodes []string
+	if item.HasTwoFactor() {
+		err = s.twoFactor.Verify(ctx, &item, code)
+	} else {
+		var enabled dto.TwoFactorRecoveryResp
+		enabled, err = s.twoFactor.Enable(ctx, item.ID, code)
+		recoveryCodes = enabled.RecoveryCodes
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	resp, err = s.openSession(ctx, &item, client)
+	resp.RecoveryCodes = recoveryCodes
+
+	return resp, err
+}
+
+// openSession records a new session for the client and issues its tokens.
+func (s *accountService) openSession(ctx context.Context, item *dao.Account, client dto.ClientInfo) (dto.AccountLoginResp, error) {
+	session := dao.Session{
+		ID:        uuid.NewString(),
+		AccountID: item.ID,
+		IPAddress: client.IPAddress,
+		UserAgent: truncate(client.UserAgent, 255),
+		UserOS:    truncate(client.UserOS, 64),
+		ExpiresAt: time.Now().UTC().Add(s.refreshTTL()),
+	}
+	if err := s.sessionRepo.Create(ctx, &session); err != nil {
+		return dto.AccountLoginResp{}, err
+	}
+
+	return s.issueTokens(ctx, item, session.ID, "")
+}
+
+// Refresh exchanges the refresh token identified by tokenID for a new
+// access/refresh pair. The presented token cannot be used again.
+func (s *accountService) Refresh(ctx context.Context, username, tokenID string) (dto.AccountLoginResp, error) {
+	if tokenID == "" {
+		return dto.AccountLoginResp{}, exception.ErrRefreshTokenInvalid
+	}
+
+	item, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return dto.AccountLoginResp{}, err
+	}
+
+	return s.issueTokens(ctx, &item, "", tokenID)
+}
+
+// issueTokens signs a new access/refresh pair for the account. With an
+// empty previousTokenID the pair starts the given session, otherwise the
+// refresh token it names is rotated within its own session.
+func (s *accountService) issueTokens(
+	ctx context.Context,
+	item *dao.Account,
+	sessionID, previousTokenID string,
+) (dto.AccountLoginResp, error) {
+	var resp dto.AccountLoginResp
+
+	now := time.Now().UTC()
+	next := dao.RefreshToken{
+		TokenID:   uuid.NewString(),
+		SessionID: sessionID,
+		AccountID: item.ID,
+		ExpiresAt: now.Add(s.refreshTTL()),
+	}
+
+	var err error
+	if previousTokenID == "" {
+		err = s.refreshTokenRepo.Create(ctx, &next)
+	} else {
+		err = s.refreshTokenRepo.Rotate(ctx, previousTokenID, &next, now)
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	aToken, err := util.CreateAuthAccessToken(*s.cfg, s.keys, item.Username, string(item.Role), next.SessionID)
 	if err != nil {
 		return resp, err
 	}
 
-	rToken, err := util.CreateAuthRefreshToken(*s.cfg, item.Username)
+	rToken, err := util.CreateAuthRefreshToken(*s.cfg, s.keys, item.Username, next.TokenID)
 	if err != nil {
 		return resp, err
 	}
@@ -56,28 +248,93 @@ func (s *AccountService) Login(p dto.AccountLoginReq) (dto.AccountLoginResp, err
 	return resp, nil
 }
 
-func (s *AccountService) Create(params dto.AccountCreateReq) (*dao.Account, error) {
+// Register creates a member account and its person profile together.
+func (s *accountService) Register(ctx context.Context, params *dto.AccountRegisterReq) (dto.AccountRegisterResp, error) {
+	var resp dto.AccountRegisterResp
+
+	person, err := params.ToPerson()
+	if err != nil {
+		return resp, err
+	}
+
+	account, err := dao.NewUser(params.Username, params.Password, s.cfg.AuthN.PasswordEncryptionSecret)
+	if err != nil {
+		return resp, err
+	}
+	if params.Email != "" {
+		account.Email = &params.Email
+	}
+
+	// The account and its person are stored together so neither exists
+	// without the other.
+	err = s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		_, err := repos.Account.GetByUsername(ctx, account.Username)
+		if err == nil {
+			return exception.ErrUserConflict
+		}
+		if !errors.Is(err, exception.ErrUserNotFound) {
+			return err
+		}
+
+		if err := repos.Account.Create(ctx, &account); err != nil {
+			return err
+		}
+
+		person.AccountID = &account.ID
+		return repos.Person.Create(ctx, &person)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return resp, exception.ErrUserConflict
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	resp.ID = account.ID
+	resp.Username = account.Username
+	resp.PersonID = person.ID
+
+	return resp, nil
+}
+
+func (s *accountService) Create(ctx context.Context, params dto.AccountCreateReq) (*dao.Account, error) {
 	newItem := params.ToEntity()
-	err := s.repo.Create(&newItem)
+	err := s.repo.Create(ctx, &newItem)
 	return &newItem, err
 }
 
-
-func (s *AccountService) Delete(id uint) error {
+// Delete deletes the account together with its person, credentials and
+// sessions. Either everything goes or, on any failure, nothing does.
+func (s *accountService) Delete(ctx context.Context, id uint) error {
 	if id <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Delete(id)
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		for _, deleteByAccount := range []func(context.Context, uint) error{
+			repos.Person.DeleteByAccount,
+			repos.APIKey.DeleteByAccount,
+			repos.TwoFactor.DeleteByAccount,
+			repos.PasswordReset.DeleteByAccount,
+			repos.RefreshToken.DeleteByAccount,
+			repos.Session.DeleteByAccount,
+		} {
+			if err := deleteByAccount(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		return repos.Account.Delete(ctx, id)
+	})
 }
 
-func (s *AccountService) GetByID(id uint) (*dao.Account, error) {
-	return s.repo.GetByID(id)
+func (s *accountService) GetByID(ctx context.Context, id uint) (*dao.Account, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *AccountService) GetAccountByID(id uint) (*dao.Account, error) {
+func (s *accountService) GetAccountByID(ctx context.Context, id uint) (*dao.Account, error) {
 	// retrieve account by ID from database or repository
-	account, err := s.repo.GetByID(id)
+	account, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, exception.ErrDataNotFound
@@ -87,26 +344,155 @@ func (s *AccountService) GetAccountByID(id uint) (*dao.Account, error) {
 	return account, nil
 }
 
-func (s *AccountService) GetAccountByToken(c *gin.Context) (*dao.Account, error) {
-	// implement the logic to retrieve the account by token
-	// for example:
-	token := c.GetHeader("Authorization")
-	account, err := s.repo.GetByToken(token)
+func (s *accountService) GetList(ctx context.Context, params *dto.Filter) ([]dao.Account, error) {
+	return s.repo.GetList(ctx)
+}
+
+// GetSessionList returns the account's active sessions, flagging the one
+// the request was made with.
+func (s *accountService) GetSessionList(ctx context.Context, accountID uint, currentSessionID string) ([]dto.SessionResp, error) {
+	items, err := s.sessionRepo.GetActiveList(ctx, accountID, time.Now().UTC())
 	if err != nil {
 		return nil, err
 	}
-	return account, nil
+
+	resp := make([]dto.SessionResp, 0, len(items))
+	for _, item := range items {
+		var t dto.SessionResp
+		t.FromEntity(&item)
+		t.Current = item.ID == currentSessionID
+
+		resp = append(resp, t)
+	}
+
+	return resp, nil
+}
+
+// Logout revokes one of the account's sessions.
+func (s *accountService) Logout(ctx context.Context, accountID uint, sessionID string) error {
+	return s.sessionRepo.Revoke(ctx, accountID, sessionID, time.Now().UTC())
 }
 
-func (s *AccountService) GetList(params *dto.Filter) ([]dao.Account, error) {
-	return s.repo.GetList()
+// LogoutAll revokes every session of the account and returns how many were
+// active.
+func (s *accountService) LogoutAll(ctx context.Context, accountID uint) (int, error) {
+	if accountID <= 0 {
+		return 0, exception.ErrUserNotFound
+	}
+
+	return s.sessionRepo.RevokeByAccount(ctx, accountID, time.Now().UTC())
+}
+
+func (s *accountService) UpdateRole(ctx context.Context, params *dto.AccountRoleUpdateReq) error {
+	if params.ID <= 0 {
+		return exception.ErrUserNotFound
+	}
+
+	return s.repo.UpdateRole(ctx, params.ID, params.Role)
 }
 
-func (s *AccountService) Update(params *dto.AccountUpdateReq) (dao.Account, error) {
+func (s *accountService) Update(ctx context.Context, params *dto.AccountUpdateReq) (dao.Account, error) {
 	account := &dao.Account{
-		ID:        params.ID,
-		Username:  params.Username,
-		Password:  params.Password,
+		ID:       params.ID,
+		Username: params.Username,
 	}
-	return *account, s.repo.Update(account)
-}
\ No newline at end of file
+	if params.Email != "" {
+		account.Email = &params.Email
+	}
+	if err := account.SetPassword(params.Password, s.cfg.AuthN.PasswordEncryptionSecret); err != nil {
+		return *account, err
+	}
+	return *account, s.repo.Update(ctx, account)
+}
+
+// ChangePassword sets a new password after checking the current one.
+func (s *accountService) ChangePassword(ctx context.Context, accountID uint, params *dto.AccountPasswordChangeReq) error {
+	item, err := s.repo.GetByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return exception.ErrUserNotFound
+	}
+
+	if !item.VerifyPassword(params.CurrentPassword) {
+		return exception.ErrPasswordMismatch
+	}
+
+	if err := item.SetPassword(params.NewPassword, s.cfg.AuthN.PasswordEncryptionSecret); err != nil {
+		return err
+	}
+
+	return s.repo.UpdatePassword(ctx, item.ID, item.Password)
+}
+
+// ForgotPassword emails a reset token to the account registered with the
+// address. Unknown addresses are ignored so callers cannot probe which
+// emails have an account.
+func (s *accountService) ForgotPassword(ctx context.Context, params *dto.AccountPasswordForgotReq) error {
+	item, err := s.repo.GetByEmail(ctx, params.Email)
+	if err != nil {
+		if errors.Is(err, exception.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token := util.RandomString(40)
+	now := time.Now().UTC()
+	ttl := time.Duration(s.cfg.AuthN.PasswordResetTTL) * time.Second
+	reset := dao.PasswordReset{
+		CreatedAt: now,
+		AccountID: item.ID,
+		TokenHash: util.SHA256Hex(token),
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.passwordResetRepo.Create(ctx, &reset); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Halo %s,\n\nGunakan token berikut untuk mengatur ulang kata sandi Anda: %s\n",
+		item.Username, token,
+	)
+	if s.cfg.AuthN.PasswordResetURL != "" {
+		body += fmt.Sprintf("atau buka %s?token=%s\n", s.cfg.AuthN.PasswordResetURL, token)
+	}
+	body += fmt.Sprintf(
+		"\nToken berlaku selama %d menit dan hanya dapat digunakan sekali. "+
+			"Abaikan email ini jika Anda tidak memintanya.\n",
+		int(ttl.Minutes()),
+	)
+
+	return s.mailer.Send(mailer.Message{
+		To:      *item.Email,
+		Subject: "Atur ulang kata sandi",
+		Body:    body,
+	})
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword and
+// logs the account out everywhere.
+func (s *accountService) ResetPassword(ctx context.Context, params *dto.AccountPasswordResetReq) error {
+	passwordHash, err := util.PasswordHash(params.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	accountID, err := s.passwordResetRepo.Consume(ctx, util.SHA256Hex(params.Token), passwordHash, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sessionRepo.RevokeByAccount(ctx, accountID, now)
+
+	return err
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"base-gin/util"
+	"context"
+	"time"
+)
+
+const (
+	apiKeyPrefix       = "lk_"
+	apiKeyLength       = 40
+	apiKeyPrefixLength = 10
+)
+
+type APIKeyService interface {
+	Create(ctx context.Context, accountID uint, params *dto.APIKeyCreateReq) (dto.APIKeyCreateResp, error)
+	GetList(ctx context.Context, accountID uint) ([]dto.APIKeyResp, error)
+	Revoke(ctx context.Context, accountID, id uint) error
+}
+
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: apiKeyRepo}
+}
+
+// Create issues a key for the account. The plain key is only part of the
+// returned response; the stored row holds its digest.
+func (s *apiKeyService) Create(ctx context.Context, accountID uint, params *dto.APIKeyCreateReq) (dto.APIKeyCreateResp, error) {
+	var resp dto.APIKeyCreateResp
+
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(time.Now()) {
+		return resp, exception.ErrAPIKeyExpiry
+	}
+
+	plain := apiKeyPrefix + util.RandomString(apiKeyLength)
+	item := dao.APIKey{
+		AccountID: accountID,
+		Name:      params.Name,
+		Prefix:    plain[:apiKeyPrefixLength],
+		KeyHash:   util.SHA256Hex(plain),
+		ExpiresAt: params.ExpiresAt,
+	}
+	item.SetScopes(params.Scopes)
+
+	if err := s.repo.Create(ctx, &item); err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(&item)
+	resp.Key = plain
+
+	return resp, nil
+}
+
+func (s *apiKeyService) GetList(ctx context.Context, accountID uint) ([]dto.APIKeyResp, error) {
+	items, err := s.repo.GetList(ctx, accountID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.APIKeyResp, 0, len(items))
+	for _, item := range items {
+		var t dto.APIKeyResp
+		t.FromEntity(&item)
+		resp = append(resp, t)
+	}
+
+	return resp, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, accountID, id uint) error {
+	if id <= 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return s.repo.Revoke(ctx, accountID, id, time.Now().UTC())
+}
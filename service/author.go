@@ -4,25 +4,36 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
+	"base-gin/search"
+	"context"
 )
 
-type AuthorService struct {
-	repo *repository.AuthorRepository
+type AuthorService interface {
+	Create(ctx context.Context, params *dto.AuthorCreateReq) error
+	GetByID(ctx context.Context, id uint) (dto.AuthorResp, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dto.AuthorResp, error)
+	Update(ctx context.Context, params *dto.AuthorUpdateReq) error
+	Delete(ctx context.Context, id uint) error
 }
 
-func NewAuthorService(authorRepo *repository.AuthorRepository) *AuthorService {
-	return &AuthorService{repo: authorRepo}
+type authorService struct {
+	repo  repository.AuthorRepository
+	index search.Index
 }
 
-func (s *AuthorService) Create(params *dto.AuthorCreateReq) error {
+func NewAuthorService(authorRepo repository.AuthorRepository, index search.Index) AuthorService {
+	return &authorService{repo: authorRepo, index: index}
+}
+
+func (s *authorService) Create(ctx context.Context, params *dto.AuthorCreateReq) error {
 	newItem := params.ToEntity()
-	return s.repo.Create(&newItem)
+	return reindex(s.index, s.repo.Create(ctx, &newItem))
 }
 
-func (s *AuthorService) GetByID(id uint) (dto.AuthorResp, error) {
+func (s *authorService) GetByID(ctx context.Context, id uint) (dto.AuthorResp, error) {
 	var resp dto.AuthorResp
 
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
@@ -35,10 +46,10 @@ func (s *AuthorService) GetByID(id uint) (dto.AuthorResp, error) {
 	return resp, nil
 }
 
-func (s *AuthorService) GetList(params *dto.Filter) ([]dto.AuthorResp, error) {
+func (s *authorService) GetList(ctx context.Context, params *dto.Filter) ([]dto.AuthorResp, error) {
 	var resp []dto.AuthorResp
 
-	items, err := s.repo.GetList(params)
+	items, err := s.repo.GetList(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -56,18 +67,18 @@ func (s *AuthorService) GetList(params *dto.Filter) ([]dto.AuthorResp, error) {
 	return resp, nil
 }
 
-func (s *AuthorService) Update(params *dto.AuthorUpdateReq) error {
+func (s *authorService) Update(ctx context.Context, params *dto.AuthorUpdateReq) error {
 	if params.ID <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Update(params)
+	return reindex(s.index, s.repo.Update(ctx, params))
 }
 
-func (s *AuthorService) Delete(id uint) error {
+func (s *authorService) Delete(ctx context.Context, id uint) error {
 	if id <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Delete(id)
-}
\ No newline at end of file
+	return reindex(s.index, s.repo.Delete(ctx, id))
+}
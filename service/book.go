@@ -1,28 +1,92 @@
 package service
 
 import (
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
+	"base-gin/search"
+	"base-gin/util"
+	"context"
 )
 
-type BookService struct {
-	repo *repository.BookRepository
+type BookService interface {
+	Create(ctx context.Context, params *dto.BookCreateReq) error
+	GetByID(ctx context.Context, id uint) (dto.BookResp, error)
+	GetList(ctx context.Context, params *dto.BookFilter) ([]dto.BookResp, error)
+	GetListByAuthor(ctx context.Context, authorID uint, params *dto.Filter) ([]dto.BookResp, error)
+	Update(ctx context.Context, params *dto.BookUpdateReq) error
+	Delete(ctx context.Context, id uint) error
 }
 
-func NewBookService(bookRepo *repository.BookRepository) *BookService {
-	return &BookService{repo: bookRepo}
+type bookService struct {
+	repo         repository.BookRepository
+	copyRepo     repository.BookCopyRepository
+	categoryRepo repository.CategoryRepository
+	index        search.Index
 }
 
-func (s *BookService) Create(params *dto.BookCreateReq) error {
+func NewBookService(
+	bookRepo repository.BookRepository,
+	bookCopyRepo repository.BookCopyRepository,
+	categoryRepo repository.CategoryRepository,
+	index search.Index,
+) BookService {
+	return &bookService{repo: bookRepo, copyRepo: bookCopyRepo, categoryRepo: categoryRepo, index: index}
+}
+
+func (s *bookService) Create(ctx context.Context, params *dto.BookCreateReq) error {
+	if err := normalizeISBN(&params.ISBN); err != nil {
+		return err
+	}
+
 	newItem := params.ToEntity()
-	return s.repo.Create(&newItem)
+	if err := checkContributors(newItem.Contributors); err != nil {
+		return err
+	}
+
+	return reindex(s.index, s.repo.Create(ctx, &newItem))
+}
+
+// normalizeISBN rewrites a non-empty ISBN into its ISBN-13 form.
+func normalizeISBN(isbn *string) error {
+	if *isbn == "" {
+		return nil
+	}
+
+	normalized, err := util.NormalizeISBN(*isbn)
+	if err != nil {
+		return err
+	}
+	*isbn = normalized
+
+	return nil
 }
 
-func (s *BookService) GetByID(id uint) (dto.BookResp, error) {
+// checkContributors rejects a list that credits the same author twice in
+// the same role.
+func checkContributors(items []dao.BookContributor) error {
+	type credit struct {
+		authorID uint
+		role     string
+	}
+
+	seen := map[credit]bool{}
+	for _, item := range items {
+		key := credit{item.AuthorID, string(item.Role)}
+		if seen[key] {
+			return exception.ErrContributorDuplicate
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+func (s *bookService) GetByID(ctx context.Context, id uint) (dto.BookResp, error) {
 	var resp dto.BookResp
 
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
@@ -30,25 +94,105 @@ func (s *BookService) GetByID(id uint) (dto.BookResp, error) {
 		return resp, exception.ErrUserNotFound
 	}
 
+	counts, err := s.copyRepo.CountByBookIDs(ctx, []uint{item.ID})
+	if err != nil {
+		return resp, err
+	}
+
+	tree, err := s.categoryTreeFor(ctx, []dao.Book{*item})
+	if err != nil {
+		return resp, err
+	}
+
 	resp.FromEntity(item)
+	count := counts[item.ID]
+	resp.SetCopyCount(count.Total, count.Available)
+	setCategoryPaths(&resp, tree)
 
 	return resp, nil
 }
 
-func (s *BookService) GetList(params *dto.Filter) ([]dto.BookResp, error) {
-	var resp []dto.BookResp
+// categoryTreeFor loads the category tree when one of the books has
+// categories whose paths are to be shown.
+func (s *bookService) categoryTreeFor(ctx context.Context, items []dao.Book) (categoryTree, error) {
+	for _, item := range items {
+		if len(item.Categories) > 0 {
+			return loadCategoryTree(ctx, s.categoryRepo)
+		}
+	}
 
-	items, err := s.repo.GetList(params)
+	return categoryTree{}, nil
+}
+
+func setCategoryPaths(resp *dto.BookResp, tree categoryTree) {
+	for i := range resp.Categories {
+		resp.Categories[i].Path = tree.path(uint(resp.Categories[i].ID))
+	}
+}
+
+// GetList lists books. Filtering by a category also takes in the books of
+// every category below it.
+func (s *bookService) GetList(ctx context.Context, params *dto.BookFilter) ([]dto.BookResp, error) {
+	if err := normalizeISBN(&params.ISBN); err != nil {
+		return nil, err
+	}
+	if params.CategoryID != 0 {
+		tree, err := loadCategoryTree(ctx, s.categoryRepo)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tree[params.CategoryID]; !ok {
+			return nil, exception.ErrCategoryNotFound
+		}
+		params.CategoryIDs = tree.descendants(params.CategoryID)
+	}
+
+	items, err := s.repo.GetList(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toRespList(ctx, items)
+}
+
+// GetListByAuthor lists the books the author contributed to in any role.
+func (s *bookService) GetListByAuthor(ctx context.Context, authorID uint, params *dto.Filter) ([]dto.BookResp, error) {
+	items, err := s.repo.GetListByAuthor(ctx, authorID, params)
 	if err != nil {
 		return nil, err
 	}
+
+	return s.toRespList(ctx, items)
+}
+
+// toRespList converts books to responses carrying their copy counts and
+// category paths.
+func (s *bookService) toRespList(ctx context.Context, items []dao.Book) ([]dto.BookResp, error) {
+	var resp []dto.BookResp
+
 	if len(items) < 1 {
 		return nil, exception.ErrDataNotFound
 	}
 
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	counts, err := s.copyRepo.CountByBookIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.categoryTreeFor(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, item := range items {
 		var t dto.BookResp
 		t.FromEntity(&item)
+		count := counts[item.ID]
+		t.SetCopyCount(count.Total, count.Available)
+		setCategoryPaths(&t, tree)
 
 		resp = append(resp, t)
 	}
@@ -56,18 +200,24 @@ func (s *BookService) GetList(params *dto.Filter) ([]dto.BookResp, error) {
 	return resp, nil
 }
 
-func (s *BookService) Update(params *dto.BookUpdateReq) error {
+func (s *bookService) Update(ctx context.Context, params *dto.BookUpdateReq) error {
 	if params.ID <= 0 {
 		return exception.ErrUserNotFound
 	}
+	if err := normalizeISBN(&params.ISBN); err != nil {
+		return err
+	}
+	if err := checkContributors(params.ToContributors()); err != nil {
+		return err
+	}
 
-	return s.repo.Update(params)
+	return reindex(s.index, s.repo.Update(ctx, params))
 }
 
-func (s *BookService) Delete(id uint) error {
+func (s *bookService) Delete(ctx context.Context, id uint) error {
 	if id <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Delete(id)
-}
\ No newline at end of file
+	return reindex(s.index, s.repo.Delete(ctx, id))
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+)
+
+type BookCopyService interface {
+	Create(ctx context.Context, params *dto.BookCopyCreateReq) error
+	GetByID(ctx context.Context, bookID, id uint) (dto.BookCopyResp, error)
+	GetList(ctx context.Context, bookID uint, params *dto.Filter) ([]dto.BookCopyResp, error)
+	Update(ctx context.Context, params *dto.BookCopyUpdateReq) error
+	Delete(ctx context.Context, bookID, id uint) error
+}
+
+type bookCopyService struct {
+	repo     repository.BookCopyRepository
+	bookRepo repository.BookRepository
+}
+
+func NewBookCopyService(
+	bookCopyRepo repository.BookCopyRepository,
+	bookRepo repository.BookRepository,
+) BookCopyService {
+	return &bookCopyService{repo: bookCopyRepo, bookRepo: bookRepo}
+}
+
+func (s *bookCopyService) Create(ctx context.Context, params *dto.BookCopyCreateReq) error {
+	if _, err := s.bookRepo.GetByID(ctx, params.BookID); err != nil {
+		return err
+	}
+
+	newItem := params.ToEntity()
+	return s.repo.Create(ctx, &newItem)
+}
+
+func (s *bookCopyService) GetByID(ctx context.Context, bookID, id uint) (dto.BookCopyResp, error) {
+	var resp dto.BookCopyResp
+
+	item, err := s.repo.GetByID(ctx, bookID, id)
+	if err != nil {
+		return resp, err
+	}
+
+	onLoan, err := s.repo.GetOnLoanIDs(ctx, []uint{item.ID})
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(item)
+	resp.OnLoan = onLoan[item.ID]
+
+	return resp, nil
+}
+
+func (s *bookCopyService) GetList(ctx context.Context, bookID uint, params *dto.Filter) ([]dto.BookCopyResp, error) {
+	var resp []dto.BookCopyResp
+
+	items, err := s.repo.GetList(ctx, bookID, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) < 1 {
+		return nil, exception.ErrDataNotFound
+	}
+
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	onLoan, err := s.repo.GetOnLoanIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		var t dto.BookCopyResp
+		t.FromEntity(&item)
+		t.OnLoan = onLoan[item.ID]
+
+		resp = append(resp, t)
+	}
+
+	return resp, nil
+}
+
+func (s *bookCopyService) Update(ctx context.Context, params *dto.BookCopyUpdateReq) error {
+	if params.ID <= 0 {
+		return exception.ErrUserNotFound
+	}
+
+	if _, err := s.repo.GetByID(ctx, params.BookID, params.ID); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, params)
+}
+
+// Delete removes a copy from the catalogue. Copies that are currently lent
+// out cannot be deleted.
+func (s *bookCopyService) Delete(ctx context.Context, bookID, id uint) error {
+	if id <= 0 {
+		return exception.ErrDataNotFound
+	}
+
+	onLoan, err := s.repo.GetOnLoanIDs(ctx, []uint{id})
+	if err != nil {
+		return err
+	}
+	if onLoan[id] {
+		return exception.ErrBookUnavailable
+	}
+
+	return s.repo.Delete(ctx, bookID, id)
+}
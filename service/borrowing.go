@@ -1,28 +1,91 @@
 package service
 
 import (
+	"base-gin/config"
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
+	"context"
+	"time"
 )
 
-type BorrowingService struct {
-	repo *repository.BorrowingRepository
+type BorrowingService interface {
+	Create(ctx context.Context, params *dto.BorrowingCreateReq) error
+	GetByID(ctx context.Context, id uint) (dto.BorrowingResp, error)
+	GetList(ctx context.Context, params *dto.BorrowingFilter) ([]dto.BorrowingResp, error)
+	GetOverdueList(ctx context.Context, params *dto.Filter) ([]dto.BorrowingResp, error)
+	Renew(ctx context.Context, id uint) (dto.BorrowingResp, error)
+	Return(ctx context.Context, id, returnedByID uint) (dto.BorrowingResp, error)
+	ReturnByBarcode(ctx context.Context, barcode string, returnedByID uint) (dto.BorrowingResp, error)
+	Delete(ctx context.Context, id uint) error
 }
 
-func NewBorrowingService(borrowingRepo *repository.BorrowingRepository) *BorrowingService {
-	return &BorrowingService{repo: borrowingRepo}
+type borrowingService struct {
+	cfg      *config.Config
+	uow      repository.UnitOfWork
+	repo     repository.BorrowingRepository
+	holdRepo repository.HoldRepository
 }
 
-func (s *BorrowingService) Create(params *dto.BorrowingCreateReq) error {
+func NewBorrowingService(
+	cfg *config.Config,
+	uow repository.UnitOfWork,
+	borrowingRepo repository.BorrowingRepository,
+	holdRepo repository.HoldRepository,
+) BorrowingService {
+	return &borrowingService{
+		cfg:      cfg,
+		uow:      uow,
+		repo:     borrowingRepo,
+		holdRepo: holdRepo,
+	}
+}
+
+// loanPeriod is the time a patron may keep a book before it is due.
+func (s *borrowingService) loanPeriod() time.Duration {
+	return time.Duration(s.cfg.Library.LoanPeriodDays) * 24 * time.Hour
+}
+
+func (s *borrowingService) circulationPolicy() dao.CirculationPolicy {
+	return dao.CirculationPolicy{
+		Fine: dao.FinePolicy{
+			PerDay: s.cfg.Library.FinePerDay,
+			Max:    s.cfg.Library.FineMax,
+		},
+		HoldPickup: holdPickup(s.cfg),
+	}
+}
+
+// Create lends a book copy to a person. Persons whose outstanding fines
+// exceed the configured threshold cannot borrow, and a copy set aside for a
+// hold can only be borrowed by the patron who placed it. The checks and the
+// loan run in one transaction.
+func (s *borrowingService) Create(ctx context.Context, params *dto.BorrowingCreateReq) error {
 	newItem := params.ToEntity()
-	return s.repo.Create(&newItem)
+
+	now := time.Now().UTC()
+	newItem.BorrowDate = &now
+	dueDate := newItem.BorrowDate.Add(s.loanPeriod())
+	newItem.DueDate = &dueDate
+
+	return s.uow.Do(ctx, func(repos *repository.Repositories) error {
+		balance, err := repos.Fine.GetBalance(ctx, params.PersonID)
+		if err != nil {
+			return err
+		}
+		if balance > s.cfg.Library.FineBlockThreshold {
+			return exception.ErrFineBalanceTooHigh
+		}
+
+		return repos.Borrowing.CreateIfAvailable(ctx, &newItem, s.circulationPolicy())
+	})
 }
 
-func (s *BorrowingService) GetByID(id uint) (dto.BorrowingResp, error) {
+func (s *borrowingService) GetByID(ctx context.Context, id uint) (dto.BorrowingResp, error) {
 	var resp dto.BorrowingResp
 
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
@@ -35,10 +98,10 @@ func (s *BorrowingService) GetByID(id uint) (dto.BorrowingResp, error) {
 	return resp, nil
 }
 
-func (s *BorrowingService) GetList(params *dto.Filter) ([]dto.BorrowingResp, error) {
+func (s *borrowingService) GetList(ctx context.Context, params *dto.BorrowingFilter) ([]dto.BorrowingResp, error) {
 	var resp []dto.BorrowingResp
 
-	items, err := s.repo.GetList(params)
+	items, err := s.repo.GetList(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -56,18 +119,111 @@ func (s *BorrowingService) GetList(params *dto.Filter) ([]dto.BorrowingResp, err
 	return resp, nil
 }
 
-func (s *BorrowingService) Update(params *dto.BorrowingUpdateReq) error {
-	if params.ID <= 0 {
-		return exception.ErrUserNotFound
+func (s *borrowingService) GetOverdueList(ctx context.Context, params *dto.Filter) ([]dto.BorrowingResp, error) {
+	var resp []dto.BorrowingResp
+
+	items, err := s.repo.GetOverdueList(ctx, time.Now().UTC(), params)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) < 1 {
+		return nil, exception.ErrDataNotFound
+	}
+
+	for _, item := range items {
+		var t dto.BorrowingResp
+		t.FromEntity(&item)
+
+		resp = append(resp, t)
+	}
+
+	return resp, nil
+}
+
+// Renew extends the due date of an open borrowing by one loan period. Loans
+// that are already overdue, have used up their renewals or whose book is
+// held by another patron are refused.
+func (s *borrowingService) Renew(ctx context.Context, id uint) (dto.BorrowingResp, error) {
+	var resp dto.BorrowingResp
+
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case item.ReturnDate != nil:
+		return resp, exception.ErrBorrowingReturned
+	case item.IsOverdue(now):
+		return resp, exception.ErrBorrowingOverdue
+	case item.RenewalCount >= s.cfg.Library.RenewalLimit:
+		return resp, exception.ErrRenewalLimit
+	}
+
+	holds, err := s.holdRepo.CountWaitingByOthers(ctx, item.BookID, item.PersonID)
+	if err != nil {
+		return resp, err
+	}
+	if holds > 0 {
+		return resp, exception.ErrHoldPending
+	}
+
+	dueDate := now
+	if item.DueDate != nil {
+		dueDate = *item.DueDate
+	}
+	dueDate = dueDate.Add(s.loanPeriod())
+
+	if err := s.repo.Renew(ctx, item.ID, item.RenewalCount, dueDate); err != nil {
+		return resp, err
+	}
+
+	item.DueDate = &dueDate
+	item.RenewalCount++
+	resp.FromEntity(item)
+
+	return resp, nil
+}
+
+// Return checks a borrowing back in using the server clock and records the
+// account that processed it. Late returns accrue a fine.
+func (s *borrowingService) Return(ctx context.Context, id, returnedByID uint) (dto.BorrowingResp, error) {
+	var resp dto.BorrowingResp
+
+	if id <= 0 {
+		return resp, exception.ErrUserNotFound
 	}
 
-	return s.repo.Update(params)
+	item, err := s.repo.Return(ctx, id, returnedByID, time.Now().UTC(), s.circulationPolicy())
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(item)
+
+	return resp, nil
+}
+
+// ReturnByBarcode checks in whichever borrowing is currently open for the
+// book copy with the given barcode.
+func (s *borrowingService) ReturnByBarcode(ctx context.Context, barcode string, returnedByID uint) (dto.BorrowingResp, error) {
+	var resp dto.BorrowingResp
+
+	item, err := s.repo.ReturnByBarcode(ctx, barcode, returnedByID, time.Now().UTC(), s.circulationPolicy())
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(item)
+
+	return resp, nil
 }
 
-func (s *BorrowingService) Delete(id uint) error {
+func (s *borrowingService) Delete(ctx context.Context, id uint) error {
 	if id <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Delete(id)
-}
\ No newline at end of file
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"errors"
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, params *dto.CategoryCreateReq) error
+	GetByID(ctx context.Context, id uint) (dto.CategoryResp, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dto.CategoryResp, error)
+	Update(ctx context.Context, params *dto.CategoryUpdateReq) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type categoryService struct {
+	repo repository.CategoryRepository
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{repo: categoryRepo}
+}
+
+// categoryTree is the whole category tree keyed by ID. The tree is small
+// enough to walk in memory rather than with recursive queries.
+type categoryTree map[uint]dao.Category
+
+func loadCategoryTree(ctx context.Context, repo repository.CategoryRepository) (categoryTree, error) {
+	items, err := repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := categoryTree{}
+	for _, item := range items {
+		tree[item.ID] = item
+	}
+
+	return tree, nil
+}
+
+// ancestors returns the IDs from the category up to its root, the category
+// first.
+func (t categoryTree) ancestors(id uint) []uint {
+	var ids []uint
+	seen := map[uint]bool{}
+	for {
+		item, ok := t[id]
+		if !ok || seen[id] {
+			return ids
+		}
+		seen[id] = true
+		ids = append(ids, id)
+
+		if item.ParentID == nil {
+			return ids
+		}
+		id = *item.ParentID
+	}
+}
+
+// path returns the names from the root down to the category.
+func (t categoryTree) path(id uint) []string {
+	ids := t.ancestors(id)
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[len(ids)-1-i] = t[id].Name
+	}
+
+	return names
+}
+
+// descendants returns the category and every category below it.
+func (t categoryTree) descendants(id uint) []uint {
+	children := map[uint][]uint{}
+	for _, item := range t {
+		if item.ParentID != nil {
+			children[*item.ParentID] = append(children[*item.ParentID], item.ID)
+		}
+	}
+
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+
+	return ids
+}
+
+func (s *categoryService) Create(ctx context.Context, params *dto.CategoryCreateReq) error {
+	if params.ParentID != nil {
+		_, err := s.repo.GetByID(ctx, *params.ParentID)
+		if errors.Is(err, exception.ErrCategoryNotFound) {
+			return exception.ErrCategoryParent
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	newItem := params.ToEntity()
+	return s.repo.Create(ctx, &newItem)
+}
+
+func (s *categoryService) GetByID(ctx context.Context, id uint) (dto.CategoryResp, error) {
+	var resp dto.CategoryResp
+
+	tree, err := loadCategoryTree(ctx, s.repo)
+	if err != nil {
+		return resp, err
+	}
+	item, ok := tree[id]
+	if !ok {
+		return resp, exception.ErrCategoryNotFound
+	}
+
+	resp.FromEntity(&item)
+	resp.Path = tree.path(id)
+
+	return resp, nil
+}
+
+func (s *categoryService) GetList(ctx context.Context, params *dto.Filter) ([]dto.CategoryResp, error) {
+	var resp []dto.CategoryResp
+
+	items, err := s.repo.GetList(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) < 1 {
+		return nil, exception.ErrDataNotFound
+	}
+
+	tree, err := loadCategoryTree(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		var t dto.CategoryResp
+		t.FromEntity(&item)
+		t.Path = tree.path(item.ID)
+
+		resp = append(resp, t)
+	}
+
+	return resp, nil
+}
+
+// Update rejects moving a category under itself or one of its descendants.
+func (s *categoryService) Update(ctx context.Context, params *dto.CategoryUpdateReq) error {
+	if params.ID <= 0 {
+		return exception.ErrCategoryNotFound
+	}
+
+	tree, err := loadCategoryTree(ctx, s.repo)
+	if err != nil {
+		return err
+	}
+	if _, ok := tree[params.ID]; !ok {
+		return exception.ErrCategoryNotFound
+	}
+
+	if params.ParentID != nil {
+		if _, ok := tree[*params.ParentID]; !ok {
+			return exception.ErrCategoryParent
+		}
+		for _, id := range tree.ancestors(*params.ParentID) {
+			if id == params.ID {
+				return exception.ErrCategoryCycle
+			}
+		}
+	}
+
+	return s.repo.Update(ctx, params)
+}
+
+func (s *categoryService) Delete(ctx context.Context, id uint) error {
+	if id <= 0 {
+		return exception.ErrCategoryNotFound
+	}
+
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/repository"
+	"context"
+)
+
+type FineService interface {
+	GetBalance(ctx context.Context, personID uint, params *dto.Filter) (dto.FineBalanceResp, error)
+	Pay(ctx context.Context, params *dto.FinePaymentReq, recordedByID uint) error
+	Waive(ctx context.Context, personID, fineID uint, params *dto.FineWaiveReq, recordedByID uint) error
+}
+
+type fineService struct {
+	repo repository.FineRepository
+}
+
+func NewFineService(fineRepo repository.FineRepository) FineService {
+	return &fineService{repo: fineRepo}
+}
+
+func (s *fineService) GetBalance(ctx context.Context, personID uint, params *dto.Filter) (dto.FineBalanceResp, error) {
+	resp := dto.FineBalanceResp{PersonID: int(personID), Entries: []dto.FineResp{}}
+
+	balance, err := s.repo.GetBalance(ctx, personID)
+	if err != nil {
+		return resp, err
+	}
+	resp.Balance = balance
+
+	items, err := s.repo.GetListByPerson(ctx, personID, params)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, item := range items {
+		var t dto.FineResp
+		t.FromEntity(&item)
+
+		resp.Entries = append(resp.Entries, t)
+	}
+
+	return resp, nil
+}
+
+func (s *fineService) Pay(ctx context.Context, params *dto.FinePaymentReq, recordedByID uint) error {
+	payment := params.ToEntity()
+	payment.RecordedByID = &recordedByID
+
+	return s.repo.Pay(ctx, &payment)
+}
+
+func (s *fineService) Waive(ctx context.Context, personID, fineID uint, params *dto.FineWaiveReq, recordedByID uint) error {
+	waiver := dao.Fine{
+		Note:         params.Note,
+		RecordedByID: &recordedByID,
+	}
+
+	return s.repo.Waive(ctx, personID, fineID, &waiver)
+}
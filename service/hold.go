@@ -0,0 +1,104 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"context"
+	"time"
+)
+
+type HoldService interface {
+	Create(ctx context.Context, params *dto.HoldCreateReq) error
+	GetQueue(ctx context.Context, bookID uint) ([]dto.HoldResp, error)
+	GetByID(ctx context.Context, bookID, id uint) (dto.HoldResp, error)
+	Cancel(ctx context.Context, bookID, id uint) error
+}
+
+type holdService struct {
+	cfg  *config.Config
+	repo repository.HoldRepository
+}
+
+func NewHoldService(cfg *config.Config, holdRepo repository.HoldRepository) HoldService {
+	return &holdService{cfg: cfg, repo: holdRepo}
+}
+
+// holdPickup is how long a returned copy stays set aside for a ready hold.
+func holdPickup(cfg *config.Config) time.Duration {
+	return time.Duration(cfg.Library.HoldPickupDays) * 24 * time.Hour
+}
+
+func (s *holdService) Create(ctx context.Context, params *dto.HoldCreateReq) error {
+	newItem := params.ToEntity()
+	return s.repo.Create(ctx, &newItem, time.Now().UTC(), holdPickup(s.cfg))
+}
+
+// GetQueue returns the active holds of a book with their queue positions.
+// A ready hold past its pickup window is listed until the next hold, loan or
+// return expires it.
+func (s *holdService) GetQueue(ctx context.Context, bookID uint) ([]dto.HoldResp, error) {
+	var resp []dto.HoldResp
+
+	items, err := s.repo.GetQueue(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) < 1 {
+		return nil, exception.ErrDataNotFound
+	}
+
+	position := 0
+	for _, item := range items {
+		var t dto.HoldResp
+		t.FromEntity(&item)
+		if item.Status == domain.HoldStatusWaiting {
+			position++
+			t.Position = position
+		}
+
+		resp = append(resp, t)
+	}
+
+	return resp, nil
+}
+
+func (s *holdService) GetByID(ctx context.Context, bookID, id uint) (dto.HoldResp, error) {
+	var resp dto.HoldResp
+
+	item, err := s.repo.GetByID(ctx, bookID, id)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.FromEntity(item)
+	if item.Status != domain.HoldStatusWaiting {
+		return resp, nil
+	}
+
+	queue, err := s.repo.GetQueue(ctx, bookID)
+	if err != nil {
+		return resp, err
+	}
+	for _, queued := range queue {
+		if queued.Status != domain.HoldStatusWaiting {
+			continue
+		}
+		resp.Position++
+		if queued.ID == item.ID {
+			break
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *holdService) Cancel(ctx context.Context, bookID, id uint) error {
+	if id <= 0 {
+		return exception.ErrUserNotFound
+	}
+
+	return s.repo.Cancel(ctx, bookID, id, time.Now().UTC(), holdPickup(s.cfg))
+}
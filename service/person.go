@@ -4,20 +4,33 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
+	"context"
 )
 
-type PersonService struct {
-	repo *repository.PersonRepository
+type PersonService interface {
+	GetAccountProfile(ctx context.Context, accountID uint) (dto.AccountProfileResp, error)
+	GetIDByAccountID(ctx context.Context, accountID uint) (uint, error)
+	GetByID(ctx context.Context, id uint) (dto.PersonDetailResp, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dto.PersonDetailResp, error)
+	Update(ctx context.Context, params *dto.PersonUpdateReq) error
+	Create(ctx context.Context, params *dto.PersonCreateReq) error
+	Delete(ctx context.Context, id uint) error
+	AttachAccount(ctx context.Context, params *dto.PersonAccountLinkReq) error
+	DetachAccount(ctx context.Context, id uint) error
 }
 
-func NewPersonService(personRepo *repository.PersonRepository) *PersonService {
-	return &PersonService{repo: personRepo}
+type personService struct {
+	repo repository.PersonRepository
 }
 
-func (s *PersonService) GetAccountProfile(accountID uint) (dto.AccountProfileResp, error) {
+func NewPersonService(personRepo repository.PersonRepository) PersonService {
+	return &personService{repo: personRepo}
+}
+
+func (s *personService) GetAccountProfile(ctx context.Context, accountID uint) (dto.AccountProfileResp, error) {
 	var resp dto.AccountProfileResp
 
-	item, err := s.repo.GetByAccountID(accountID)
+	item, err := s.repo.GetByAccountID(ctx, accountID)
 	if err != nil {
 		return resp, err
 	}
@@ -27,10 +40,20 @@ func (s *PersonService) GetAccountProfile(accountID uint) (dto.AccountProfileRes
 	return resp, nil
 }
 
-func (s *PersonService) GetByID(id uint) (dto.PersonDetailResp, error) {
+// GetIDByAccountID returns the ID of the person linked to an account.
+func (s *personService) GetIDByAccountID(ctx context.Context, accountID uint) (uint, error) {
+	item, err := s.repo.GetByAccountID(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	return item.ID, nil
+}
+
+func (s *personService) GetByID(ctx context.Context, id uint) (dto.PersonDetailResp, error) {
 	var resp dto.PersonDetailResp
 
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
@@ -43,10 +66,10 @@ func (s *PersonService) GetByID(id uint) (dto.PersonDetailResp, error) {
 	return resp, nil
 }
 
-func (s *PersonService) GetList(params *dto.Filter) ([]dto.PersonDetailResp, error) {
+func (s *personService) GetList(ctx context.Context, params *dto.Filter) ([]dto.PersonDetailResp, error) {
 	var resp []dto.PersonDetailResp
 
-	items, err := s.repo.GetList(params)
+	items, err := s.repo.GetList(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +87,7 @@ func (s *PersonService) GetList(params *dto.Filter) ([]dto.PersonDetailResp, err
 	return resp, nil
 }
 
-func (s *PersonService) Update(params *dto.PersonUpdateReq) error {
+func (s *personService) Update(ctx context.Context, params *dto.PersonUpdateReq) error {
 	if params.ID <= 0 {
 		return exception.ErrUserNotFound
 	}
@@ -76,14 +99,30 @@ func (s *PersonService) Update(params *dto.PersonUpdateReq) error {
 	}
 	params.BirthDate = birthDate
 
-	return s.repo.Update(params)
+	return s.repo.Update(ctx, params)
 }
 
-func (s* PersonService) Create(params *dto.PersonCreateReq) error {
+func (s *personService) Create(ctx context.Context, params *dto.PersonCreateReq) error {
 	newItem := params.ToEntity()
-	return s.repo.Create(&newItem)
+	return s.repo.Create(ctx, &newItem)
+}
+
+func (s *personService) Delete(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *personService) AttachAccount(ctx context.Context, params *dto.PersonAccountLinkReq) error {
+	if params.ID <= 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return s.repo.AttachAccount(ctx, params.ID, params.AccountID)
 }
 
-func (s* PersonService) Delete(id uint) error {
-	return s.repo.Delete(id)
+func (s *personService) DetachAccount(ctx context.Context, id uint) error {
+	if id <= 0 {
+		return exception.ErrDataNotFound
+	}
+
+	return s.repo.DetachAccount(ctx, id)
 }
@@ -4,25 +4,36 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/exception"
 	"base-gin/repository"
+	"base-gin/search"
+	"context"
 )
 
-type PublisherService struct {
-	repo *repository.PublisherRepository
+type PublisherService interface {
+	Create(ctx context.Context, params *dto.PublisherCreateReq) error
+	GetByID(ctx context.Context, id uint) (dto.PublisherResp, error)
+	GetList(ctx context.Context, params *dto.Filter) ([]dto.PublisherResp, error)
+	Update(ctx context.Context, params *dto.PublisherUpdateReq) error
+	Delete(ctx context.Context, id uint) error
 }
 
-func NewPublisherService(publisherRepo *repository.PublisherRepository) *PublisherService {
-	return &PublisherService{repo: publisherRepo}
+type publisherService struct {
+	repo  repository.PublisherRepository
+	index search.Index
 }
 
-func (s *PublisherService) Create(params *dto.PublisherCreateReq) error {
+func NewPublisherService(publisherRepo repository.PublisherRepository, index search.Index) PublisherService {
+	return &publisherService{repo: publisherRepo, index: index}
+}
+
+func (s *publisherService) Create(ctx context.Context, params *dto.PublisherCreateReq) error {
 	newItem := params.ToEntity()
-	return s.repo.Create(&newItem)
+	return reindex(s.index, s.repo.Create(ctx, &newItem))
 }
 
-func (s *PublisherService) GetByID(id uint) (dto.PublisherResp, error) {
+func (s *publisherService) GetByID(ctx context.Context, id uint) (dto.PublisherResp, error) {
 	var resp dto.PublisherResp
 
-	item, err := s.repo.GetByID(id)
+	item, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return resp, err
 	}
@@ -36,10 +47,10 @@ func (s *PublisherService) GetByID(id uint) (dto.PublisherResp, error) {
 	return resp, nil
 }
 
-func (s *PublisherService) GetList(params *dto.Filter) ([]dto.PublisherResp, error) {
+func (s *publisherService) GetList(ctx context.Context, params *dto.Filter) ([]dto.PublisherResp, error) {
 	var resp []dto.PublisherResp
 
-	items, err := s.repo.GetList(params)
+	items, err := s.repo.GetList(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -57,18 +68,18 @@ func (s *PublisherService) GetList(params *dto.Filter) ([]dto.PublisherResp, err
 	return resp, nil
 }
 
-func (s *PublisherService) Update(params *dto.PublisherUpdateReq) error {
+func (s *publisherService) Update(ctx context.Context, params *dto.PublisherUpdateReq) error {
 	if params.ID <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Update(params)
+	return reindex(s.index, s.repo.Update(ctx, params))
 }
 
-func (s *PublisherService) Delete(id uint) error {
+func (s *publisherService) Delete(ctx context.Context, id uint) error {
 	if id <= 0 {
 		return exception.ErrDataNotFound
 	}
 
-	return s.repo.Delete(id)
+	return reindex(s.index, s.repo.Delete(ctx, id))
 }
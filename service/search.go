@@ -0,0 +1,61 @@
+package service
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/search"
+	"context"
+)
+
+// defaultSearchLimit is the number of results when none is asked for.
+const defaultSearchLimit = 20
+
+type SearchService interface {
+	Search(ctx context.Context, params *dto.SearchReq) ([]dto.SearchResp, error)
+}
+
+type searchService struct {
+	index search.Index
+}
+
+func NewSearchService(index search.Index) SearchService {
+	return &searchService{index: index}
+}
+
+// Search returns books, authors and publishers matching the query, best
+// match first.
+func (s *searchService) Search(ctx context.Context, params *dto.SearchReq) ([]dto.SearchResp, error) {
+	var resp []dto.SearchResp
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	hits, err := s.index.Search(ctx, search.ParseQuery(params.Query, search.Kind(params.Type)), limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) < 1 {
+		return nil, exception.ErrDataNotFound
+	}
+
+	for _, hit := range hits {
+		var t dto.SearchResp
+		t.FromHit(&hit)
+
+		resp = append(resp, t)
+	}
+
+	return resp, nil
+}
+
+// reindex tells index about a committed change to the catalogue, passing err
+// through. A failed write leaves the index alone.
+func reindex(index search.Index, err error) error {
+	if err == nil {
+		index.Invalidate()
+	}
+
+	return err
+}
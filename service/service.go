@@ -2,52 +2,66 @@ package service
 
 import (
 	"base-gin/config"
+	"base-gin/mailer"
 	"base-gin/repository"
+	"base-gin/search"
+	"base-gin/util"
 )
 
-var (
-	accountService   *AccountService
-	personService    *PersonService
-	publisherService *PublisherService
-	authorService 	 *AuthorService
-	bookService 	 *BookService
-	borrowingService *BorrowingService
-)
-
-func SetupServices(cfg *config.Config) {
-	accountService = NewAccountService(cfg, repository.GetAccountRepo())
-	personService = NewPersonService(repository.GetPersonRepo())
-	publisherService = NewPublisherService(repository.GetPublisherRepo())
-	authorService = NewAuthorService(repository.GetAuthorRepo())
-	bookService = NewBookService(repository.GetBookRepo())
-	borrowingService = NewBorrowingService(repository.GetBorrowingRepo())
-}
-
-func GetAccountService() *AccountService {
-	if accountService == nil {
-		panic("account service is not initialised")
-	}
-
-	return accountService
+// Services holds one instance of every service, built on the given
+// repositories.
+type Services struct {
+	Account   AccountService
+	Person    PersonService
+	Publisher PublisherService
+	Author    AuthorService
+	Category  CategoryService
+	Book      BookService
+	BookCopy  BookCopyService
+	Borrowing BorrowingService
+	Fine      FineService
+	Hold      HoldService
+	TwoFactor TwoFactorService
+	APIKey    APIKeyService
+	Search    SearchService
 }
 
-func GetPersonService() *PersonService {
-	return personService
-}
+// NewServices builds every service on repos. Work that must commit as a
+// whole runs through uow; changes to the catalogue are reported to index;
+// tokens are signed with keys.
+func NewServices(
+	cfg *config.Config,
+	keys *util.KeySet,
+	repos *repository.Repositories,
+	uow repository.UnitOfWork,
+	mail mailer.Mailer,
+	index search.Index,
+) *Services {
+	twoFactor := NewTwoFactorService(cfg, repos.TwoFactor, repos.Account)
 
-func GetPublisherService() *PublisherService {
-	return publisherService
-}
-
-func GetAuthorService() *AuthorService {
-	return authorService
-}
-
-
-func GetBookService() *BookService {
-	return bookService
-}
-
-func GetBorrowingService() *BorrowingService {
-	return borrowingService
+	return &Services{
+		Account: NewAccountService(
+			cfg,
+			keys,
+			uow,
+			repos.Account,
+			repos.RefreshToken,
+			repos.Session,
+			repos.PasswordReset,
+			twoFactor,
+			mail,
+		),
+		Person:    NewPersonService(repos.Person),
+		Publisher: NewPublisherService(repos.Publisher, index),
+		Author:    NewAuthorService(repos.Author, index),
+		Category:  NewCategoryService(repos.Category),
+		Book:      NewBookService(repos.Book, repos.BookCopy, repos.Category, index),
+		BookCopy:  NewBookCopyService(repos.BookCopy, repos.Book),
+		Borrowing: NewBorrowingService(cfg, uow, repos.Borrowing, repos.Hold),
+		Fine:      NewFineService(repos.Fine),
+		Hold:      NewHoldService(cfg, repos.Hold),
+		TwoFactor: twoFactor,
+		APIKey:    NewAPIKeyService(repos.APIKey),
+		Search:    NewSearchService(index),
+	}
 }
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"base-gin/config"
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"base-gin/util"
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	totpIssuerDefault  = "base-gin"
+)
+
+type TwoFactorService interface {
+	IsRequired(ctx context.Context, role domain.TypeRole) (bool, error)
+	SetRequired(ctx context.Context, params *dto.RoleTwoFactorReq) error
+	Setup(ctx context.Context, accountID uint) (dto.TwoFactorSetupResp, error)
+	Enable(ctx context.Context, accountID uint, code string) (dto.TwoFactorRecoveryResp, error)
+	Disable(ctx context.Context, accountID uint, params *dto.TwoFactorDisableReq) error
+	Verify(ctx context.Context, item *dao.Account, code string) error
+}
+
+type twoFactorService struct {
+	cfg         *config.Config
+	repo        repository.TwoFactorRepository
+	accountRepo repository.AccountRepository
+}
+
+func NewTwoFactorService(
+	cfg *config.Config,
+	twoFactorRepo repository.TwoFactorRepository,
+	accountRepo repository.AccountRepository,
+) TwoFactorService {
+	return &twoFactorService{
+		cfg:         cfg,
+		repo:        twoFactorRepo,
+		accountRepo: accountRepo,
+	}
+}
+
+func (s *twoFactorService) getAccount(ctx context.Context, accountID uint) (*dao.Account, error) {
+	item, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, exception.ErrUserNotFound
+	}
+
+	return item, nil
+}
+
+// IsRequired reports whether accounts of the role must use 2FA to log in.
+func (s *twoFactorService) IsRequired(ctx context.Context, role domain.TypeRole) (bool, error) {
+	policy, err := s.repo.GetRolePolicy(ctx, role)
+	if err != nil {
+		return false, err
+	}
+
+	return policy.RequireTwoFactor, nil
+}
+
+func (s *twoFactorService) SetRequired(ctx context.Context, params *dto.RoleTwoFactorReq) error {
+	switch params.Role {
+	case domain.RoleAdmin, domain.RoleLibrarian, domain.RoleMember:
+	default:
+		return exception.ErrDataNotFound
+	}
+
+	return s.repo.SetRequired(ctx, params.Role, *params.Required)
+}
+
+// Setup generates a new secret for the account. 2FA is not enforced until
+// Enable confirms the authenticator app produces matching codes.
+func (s *twoFactorService) Setup(ctx context.Context, accountID uint) (dto.TwoFactorSetupResp, error) {
+	var resp dto.TwoFactorSetupResp
+
+	item, err := s.getAccount(ctx, accountID)
+	if err != nil {
+		return resp, err
+	}
+	if item.HasTwoFactor() {
+		return resp, exception.ErrTwoFactorEnabled
+	}
+
+	secret := util.NewTOTPSecret()
+	encrypted, err := util.EncryptAESGCM(secret, s.cfg.AuthN.PasswordEncryptionSecret)
+	if err != nil {
+		return resp, err
+	}
+	if err := s.repo.SetSecret(ctx, item.ID, encrypted); err != nil {
+		return resp, err
+	}
+
+	issuer := s.cfg.App.Name
+	if issuer == "" {
+		issuer = totpIssuerDefault
+	}
+
+	resp.Secret = secret
+	resp.URI = util.TOTPURI(issuer, item.Username, secret)
+
+	return resp, nil
+}
+
+// Enable turns 2FA on once code matches the secret from Setup and returns
+// the recovery codes. They are shown only this once.
+func (s *twoFactorService) Enable(ctx context.Context, accountID uint, code string) (dto.TwoFactorRecoveryResp, error) {
+	var resp dto.TwoFactorRecoveryResp
+
+	item, err := s.getAccount(ctx, accountID)
+	if err != nil {
+		return resp, err
+	}
+	if item.HasTwoFactor() {
+		return resp, exception.ErrTwoFactorEnabled
+	}
+	if item.TOTPSecret == "" {
+		return resp, exception.ErrTwoFactorNotSetup
+	}
+
+	secret, err := util.DecryptAESGCM(item.TOTPSecret, s.cfg.AuthN.PasswordEncryptionSecret)
+	if err != nil {
+		return resp, err
+	}
+
+	now := time.Now().UTC()
+	step, ok := util.VerifyTOTP(secret, code, now, item.TOTPLastStep)
+	if !ok {
+		return resp, exception.ErrTwoFactorCodeInvalid
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		plain := strings.ToLower(util.RandomString(recoveryCodeLength))
+		codes[i] = plain[:recoveryCodeLength/2] + "-" + plain[recoveryCodeLength/2:]
+		hashes[i] = util.SHA256Hex(plain)
+	}
+
+	if err := s.repo.Enable(ctx, item.ID, now, step, hashes); err != nil {
+		return resp, err
+	}
+
+	resp.RecoveryCodes = codes
+
+	return resp, nil
+}
+
+// Disable turns 2FA off after checking the password. Accounts whose role
+// requires 2FA cannot opt out.
+func (s *twoFactorService) Disable(ctx context.Context, accountID uint, params *dto.TwoFactorDisableReq) error {
+	item, err := s.getAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if !item.VerifyPassword(params.Password) {
+		return exception.ErrPasswordMismatch
+	}
+	if !item.HasTwoFactor() {
+		return exception.ErrTwoFactorNotSetup
+	}
+
+	required, err := s.IsRequired(ctx, item.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return exception.ErrTwoFactorRequired
+	}
+
+	return s.repo.Disable(ctx, item.ID)
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code.
+// Each is good for a single login.
+func (s *twoFactorService) Verify(ctx context.Context, item *dao.Account, code string) error {
+	if !item.HasTwoFactor() {
+		return exception.ErrTwoFactorNotSetup
+	}
+
+	code = strings.TrimSpace(code)
+	now := time.Now().UTC()
+
+	secret, err := util.DecryptAESGCM(item.TOTPSecret, s.cfg.AuthN.PasswordEncryptionSecret)
+	if err != nil {
+		return err
+	}
+	if step, ok := util.VerifyTOTP(secret, code, now, item.TOTPLastStep); ok {
+		claimed, err := s.repo.ClaimStep(ctx, item.ID, step)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return exception.ErrTwoFactorCodeInvalid
+		}
+
+		return nil
+	}
+
+	plain := strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	if len(plain) != recoveryCodeLength {
+		return exception.ErrTwoFactorCodeInvalid
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, item.ID, util.SHA256Hex(plain), now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return exception.ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
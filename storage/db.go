@@ -3,6 +3,8 @@ package storage
 import (
 	"base-gin/config"
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
@@ -10,13 +12,33 @@ import (
 
 	"github.com/rs/zerolog"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-var db *gorm.DB
+var (
+	ErrDriverUnknown = errors.New("driver database tidak dikenali")
+)
+
+// dialector picks the gorm driver named by DB_DRIVER.
+func dialector(cfg config.DBConfig) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "mysql":
+		return mysql.Open(cfg.DSN), nil
+	case "sqlite":
+		return sqlite.Open(cfg.DSN), nil
+	case "postgres":
+		return postgres.Open(cfg.DSN), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrDriverUnknown, cfg.Driver)
+	}
+}
 
-func InitDB(config config.Config) {
+// NewDB opens a connection pool to the database named in config. Each call
+// returns an independent pool.
+func NewDB(config config.Config) *gorm.DB {
 	logLevel := logger.Silent
 	if config.App.Mode == "debug" {
 		logLevel = logger.Error
@@ -33,11 +55,17 @@ func InitDB(config config.Config) {
 		},
 	)
 
-	gormDB, err := gorm.Open(mysql.Open(config.DB.DSN), &gorm.Config{
+	dial, err := dialector(config.DB)
+	if err != nil {
+		log.Fatal().Err(err).Msg("tidak dapat terhubung ke database")
+	}
+
+	gormDB, err := gorm.Open(dial, &gorm.Config{
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
 		SkipDefaultTransaction: true,
+		TranslateError:         true, // unique violations surface as gorm.ErrDuplicatedKey
 		Logger:                 zeroLogger,
 	})
 	if err != nil {
@@ -54,17 +82,49 @@ func InitDB(config config.Config) {
 	sqlDB.SetMaxIdleConns(config.DB.MaxIdlePool)
 	sqlDB.SetConnMaxLifetime(time.Duration(config.DB.MaxIdleSecond) * time.Second)
 
-	db = gormDB
-}
+	if config.DB.QueryTimeout > 0 {
+		err := registerQueryTimeout(gormDB, time.Duration(config.DB.QueryTimeout)*time.Second)
+		if err != nil {
+			log.Fatal().Stack().Err(err).Msg("tidak dapat mengubah pengaturan database")
+		}
+	}
 
-func NewDBContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 5*time.Second)
+	return gormDB
 }
 
-func GetDB() *gorm.DB {
-	if db == nil {
-		panic("db is not initialised")
+const cancelQueryKey = "storage:cancel_query"
+
+// registerQueryTimeout bounds every create, query, update and delete run
+// through db by timeout, on top of whatever deadline the caller's context
+// carries. Repositories only pass the request context along. Raw statements,
+// such as migrations, are not bounded.
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	start := func(tx *gorm.DB) {
+		ctx, cancel := context.WithTimeout(tx.Statement.Context, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(cancelQueryKey, cancel)
+	}
+	release := func(tx *gorm.DB) {
+		if cancel, ok := tx.InstanceGet(cancelQueryKey); ok {
+			cancel.(context.CancelFunc)()
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("*").Register("storage:query_timeout", start),
+		cb.Create().After("*").Register("storage:query_release", release),
+		cb.Query().Before("*").Register("storage:query_timeout", start),
+		cb.Query().After("*").Register("storage:query_release", release),
+		cb.Update().Before("*").Register("storage:query_timeout", start),
+		cb.Update().After("*").Register("storage:query_release", release),
+		cb.Delete().Before("*").Register("storage:query_timeout", start),
+		cb.Delete().After("*").Register("storage:query_release", release),
+	} {
+		if err != nil {
+			return err
+		}
 	}
 
-	return db
+	return nil
 }
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork runs a piece of work in a single database transaction. The
+// work receives R, typically a set of repositories, built on the
+// transaction, so every statement it issues commits or rolls back together.
+type UnitOfWork[R any] struct {
+	db   *gorm.DB
+	bind func(tx *gorm.DB) R
+}
+
+// NewUnitOfWork returns a UnitOfWork that opens its transactions on db and
+// uses bind to build what the work receives from each transaction.
+func NewUnitOfWork[R any](db *gorm.DB, bind func(tx *gorm.DB) R) *UnitOfWork[R] {
+	return &UnitOfWork[R]{db: db, bind: bind}
+}
+
+// Do runs fn in a new transaction bound to ctx. The transaction commits when
+// fn returns nil and rolls back when fn returns an error or panics; a panic
+// is raised again once the rollback is done. Transactions opened by
+// repositories inside fn become savepoints of this one.
+func (u *UnitOfWork[R]) Do(ctx context.Context, fn func(repos R) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(u.bind(tx))
+	})
+}
@@ -1,22 +1,24 @@
 package integration_test
 
 import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
 	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
 	"base-gin/server"
-	"testing"
-	"base-gin/domain/dao"
 	"base-gin/util"
-	"fmt"
-	"strings"
+	"context"
 	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
 	"net/http"
 	"net/http/httptest"
-	"github.com/gin-gonic/gin"
-	"base-gin/repository"
-
+	"strings"
+	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
-
 )
 
 func TestAccount_Login_Success(t *testing.T) {
@@ -29,6 +31,140 @@ func TestAccount_Login_Success(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 }
 
+func TestAccount_Login_Throttled(t *testing.T) {
+	useApplication(t, cfg)
+
+	req := dto.AccountLoginReq{
+		Username: "admin",
+		Password: "wrong-password",
+	}
+	for i := 0; i < cfg.AuthN.LoginMaxAttempt; i++ {
+		w := doTest("POST", server.RootAccount+server.PathLogin, req, "")
+		assert.Equal(t, 400, w.Code)
+	}
+
+	// Once the limit is hit even the right password is refused.
+	req.Password = password
+	w := doTest("POST", server.RootAccount+server.PathLogin, req, "")
+	assert.Equal(t, 429, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestAccount_Login_Throttled_OwnAccount(t *testing.T) {
+	useApplication(t, cfg)
+
+	guess := dto.AccountLoginReq{Username: "admin", Password: "wrong-password"}
+	own := dto.AccountLoginReq{Username: "member", Password: password}
+	for i := 0; i < cfg.AuthN.LoginMaxAttempt-1; i++ {
+		w := doTest("POST", server.RootAccount+server.PathLogin, guess, "")
+		assert.Equal(t, 400, w.Code)
+	}
+
+	// Logging into one's own account does not clear the IP's failures.
+	w := doTest("POST", server.RootAccount+server.PathLogin, own, "")
+	assert.Equal(t, 200, w.Code)
+	w = doTest("POST", server.RootAccount+server.PathLogin, guess, "")
+	assert.Equal(t, 400, w.Code)
+
+	w = doTest("POST", server.RootAccount+server.PathLogin, own, "")
+	assert.Equal(t, 429, w.Code)
+}
+
+func TestAccount_Refresh_Rotation(t *testing.T) {
+	req := dto.AccountLoginReq{
+		Username: "admin",
+		Password: password,
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathLogin, req, "")
+	assert.Equal(t, 200, w.Code)
+	var login dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &login)
+
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, login.Data.RefreshToken)
+	assert.Equal(t, 200, w.Code)
+	var refreshed dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &refreshed)
+	assert.NotEqual(t, login.Data.RefreshToken, refreshed.Data.RefreshToken)
+
+	// An access token is not accepted in place of a refresh token.
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, refreshed.Data.AccessToken)
+	assert.Equal(t, 401, w.Code)
+
+	// Replaying the first refresh token revokes the whole family, including
+	// the token issued in exchange for it.
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, login.Data.RefreshToken)
+	assert.Equal(t, 401, w.Code)
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, refreshed.Data.RefreshToken)
+	assert.Equal(t, 401, w.Code)
+	w = doTest("GET", server.RootAccount, nil, refreshed.Data.AccessToken)
+	assert.Equal(t, 401, w.Code)
+}
+
+func login(t *testing.T, username string) dto.AccountLoginResp {
+	req := dto.AccountLoginReq{
+		Username: username,
+		Password: password,
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathLogin, req, "")
+	assert.Equal(t, 200, w.Code)
+	var resp dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	return resp.Data
+}
+
+func TestAccount_Logout_Success(t *testing.T) {
+	first := login(t, "admin")
+	second := login(t, "admin")
+
+	w := doTest("GET", server.RootAccount+server.PathSessions, nil, first.AccessToken)
+	assert.Equal(t, 200, w.Code)
+	var sessions dto.SuccessResponse[[]dto.SessionResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &sessions)
+	assert.GreaterOrEqual(t, len(sessions.Data), 2)
+
+	w = doTest("POST", server.RootAccount+server.PathLogout, nil, first.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", server.RootAccount, nil, first.AccessToken)
+	assert.Equal(t, 401, w.Code)
+	w = doTest("POST", server.RootAccount+server.PathRefresh, nil, first.RefreshToken)
+	assert.Equal(t, 401, w.Code)
+
+	// Other sessions are unaffected.
+	w = doTest("GET", server.RootAccount, nil, second.AccessToken)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestAccount_LogoutAll_Success(t *testing.T) {
+	first := login(t, "member")
+	second := login(t, "member")
+
+	w := doTest("POST", server.RootAccount+server.PathLogoutAll, nil, first.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", server.RootAccount, nil, first.AccessToken)
+	assert.Equal(t, 401, w.Code)
+	w = doTest("GET", server.RootAccount, nil, second.AccessToken)
+	assert.Equal(t, 401, w.Code)
+}
+
+func TestAccount_RevokeSessions_Success(t *testing.T) {
+	member := login(t, "member")
+	path := fmt.Sprintf("%s/%d/sessions", server.RootAccount, dummyMember.Account.ID)
+
+	w := doTest("DELETE", path, nil, member.AccessToken)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("DELETE", path, nil, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", server.RootAccount, nil, member.AccessToken)
+	assert.Equal(t, 401, w.Code)
+}
+
 func TestAccount_GetProfile_Success(t *testing.T) {
 	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
 
@@ -47,6 +183,32 @@ func TestAccount_GetProfile_ErrorAccessToken(t *testing.T) {
 	assert.Equal(t, 401, w.Code)
 }
 
+func TestAccount_UpdateRole_Success(t *testing.T) {
+	o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	_ = accountRepo.Create(context.Background(), &o)
+
+	req := dto.AccountRoleUpdateReq{Role: domain.RoleLibrarian}
+
+	w := doTest(
+		"PUT",
+		fmt.Sprintf("%s/%d/role", server.RootAccount, o.ID),
+		req,
+		createAuthAccessToken(dummyMember.Account.Username),
+	)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest(
+		"PUT",
+		fmt.Sprintf("%s/%d/role", server.RootAccount, o.ID),
+		req,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+
+	item, _ := accountRepo.GetByID(context.Background(), o.ID)
+	assert.Equal(t, domain.RoleLibrarian, item.Role)
+}
+
 func TestAccount_Create_Success(t *testing.T) {
 	req := dto.AccountCreateReq{
 		Username: util.RandomStringAlpha(10),
@@ -57,13 +219,19 @@ func TestAccount_Create_Success(t *testing.T) {
 	fmt.Println("Request URL:", server.RootAccount)
 	fmt.Println("Response Body:", w.Body.String())
 	assert.Equal(t, 201, w.Code)
+
+	var resp dto.SuccessResponse[map[string]interface{}]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, req.Username, resp.Data["username"])
+	assert.NotContains(t, resp.Data, "Password", "Hash sandi tidak boleh dikirim")
+	assert.NotContains(t, resp.Data, "TOTPSecret")
 }
 func TestAccount_Delete_Success(t *testing.T) {
 	o := dao.Account{
 		Username: util.RandomStringAlpha(10),
 		Password: password,
 	}
-	err := accountRepo.Create(&o)
+	err := accountRepo.Create(context.Background(), &o)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -75,7 +243,7 @@ func TestAccount_Delete_Success(t *testing.T) {
 		t.Errorf("expected status code 200, but got %d", w.Code)
 	}
 
-	item, err := accountRepo.GetByID(o.ID)
+	item, err := accountRepo.GetByID(context.Background(), o.ID)
 	if err != nil && !strings.Contains(err.Error(), "record not found") {
 		t.Fatal(err)
 	}
@@ -84,6 +252,24 @@ func TestAccount_Delete_Success(t *testing.T) {
 	}
 }
 
+func TestAccount_Delete_WithPersonAndSessions(t *testing.T) {
+	a := createDummyAccount(util.RandomStringAlpha(10), domain.RoleMember)
+	p := createDummyProfile(a)
+	_ = createAuthAccessToken(a.Username)
+
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	w := doTest("DELETE", fmt.Sprintf("%s/%d", server.RootAccount, a.ID), nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	_, err := accountRepo.GetByUsername(context.Background(), a.Username)
+	assert.ErrorIs(t, err, exception.ErrUserNotFound)
+	item, _ := personRepo.GetByID(context.Background(), p.ID)
+	assert.Nil(t, item, "Person milik akun harus ikut terhapus")
+	sessions, err := sessionRepo.GetActiveList(context.Background(), a.ID, time.Now())
+	assert.Nil(t, err)
+	assert.Empty(t, sessions)
+}
+
 func TestAccount_GetByID_Success(t *testing.T) {
 
 	o := dao.Account{
@@ -91,9 +277,8 @@ func TestAccount_GetByID_Success(t *testing.T) {
 		Password: password,
 	}
 
-
 	accountRepo := repository.NewAccountRepository(db)
-	err := accountRepo.Create(&o)
+	err := accountRepo.Create(context.Background(), &o)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -101,7 +286,6 @@ func TestAccount_GetByID_Success(t *testing.T) {
 	url := fmt.Sprintf("/accounts/%d", o.ID)
 	req := httptest.NewRequest("GET", url, nil)
 
-
 	w := httptest.NewRecorder()
 	router := gin.Default()
 	router.GET("/accounts/:id", func(c *gin.Context) {
@@ -110,7 +294,7 @@ func TestAccount_GetByID_Success(t *testing.T) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 			return
 		}
-		account, err := accountRepo.GetByID(id)
+		account, err := accountRepo.GetByID(context.Background(), id)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
 			return
@@ -127,7 +311,7 @@ func TestAccount_GetByID_Success(t *testing.T) {
 	assert.Equal(t, o.Username, responseAccount.Username)
 	assert.Equal(t, o.Password, responseAccount.Password)
 
-	err = accountRepo.Delete(o.ID)
+	err = accountRepo.Delete(context.Background(), o.ID)
 	assert.NoError(t, err)
 }
 
@@ -137,7 +321,7 @@ func TestAccount_Update_Success(t *testing.T) {
 		Username: "example",
 		Password: "password",
 	}
-	err := accountRepo.Create(&account)
+	err := accountRepo.Create(context.Background(), &account)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -148,18 +332,17 @@ func TestAccount_Update_Success(t *testing.T) {
 		Username: "updated-example",
 		Password: "updated-password",
 	}
-	err = accountRepo.Update(&updatedAccount)
+	err = accountRepo.Update(context.Background(), &updatedAccount)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Get the updated account
-	updatedAccountFromDB, err := accountRepo.GetByID(account.ID)
+	updatedAccountFromDB, err := accountRepo.GetByID(context.Background(), account.ID)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-
 	fmt.Println("Updated account:", updatedAccountFromDB)
 
 	if updatedAccountFromDB.Username != updatedAccount.Username {
@@ -169,6 +352,25 @@ func TestAccount_Update_Success(t *testing.T) {
 		t.Errorf("expected password to be %s, but got %s", updatedAccount.Password, updatedAccountFromDB.Password)
 	}
 }
+
+func TestAccount_Update_WithoutEmail(t *testing.T) {
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+		_ = accountRepo.Create(ctx, &o)
+
+		_, err := application.Services.Account.Update(ctx, &dto.AccountUpdateReq{
+			ID:       o.ID,
+			Username: o.Username,
+			Password: password,
+		})
+		assert.Nil(t, err, "Akun tanpa email tidak boleh bentrok dengan akun lain tanpa email")
+
+		item, _ := accountRepo.GetByID(ctx, o.ID)
+		assert.Nil(t, item.Email)
+	}
+}
+
 type MockAccountRepo struct {
 	GetListFn func(filter interface{}) ([]dao.Account, error)
 	CreateFn  func(newItem *dao.Account) error
@@ -176,21 +378,20 @@ type MockAccountRepo struct {
 	UpdateFn  func(newItem *dao.Account) error
 }
 
-
 func (m *MockAccountRepo) GetList(filter interface{}) ([]dao.Account, error) {
-    return m.GetListFn(filter)
+	return m.GetListFn(filter)
 }
 
 func (m *MockAccountRepo) Create(newItem *dao.Account) error {
-    return m.CreateFn(newItem)
+	return m.CreateFn(newItem)
 }
 
 func (m *MockAccountRepo) GetByID(id uint) (*dao.Account, error) {
-    return m.GetByIDFn(id)
+	return m.GetByIDFn(id)
 }
 
 func (m *MockAccountRepo) Update(newItem *dao.Account) error {
-    return m.UpdateFn(newItem)
+	return m.UpdateFn(newItem)
 }
 
 func TestAccountGetList_Success(t *testing.T) {
@@ -225,3 +426,85 @@ func TestAccountGetList_Success(t *testing.T) {
 	}
 }
 
+func TestAccount_ChangePassword_Success(t *testing.T) {
+	o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	_ = accountRepo.Create(context.Background(), &o)
+	token := createAuthAccessToken(o.Username)
+
+	req := dto.AccountPasswordChangeReq{
+		CurrentPassword: "not-the-password",
+		NewPassword:     "NewPaswd123",
+	}
+	w := doTest("PUT", server.RootAccount+server.PathPassword, req, token)
+	assert.Equal(t, 400, w.Code)
+
+	req.CurrentPassword = password
+	w = doTest("PUT", server.RootAccount+server.PathPassword, req, token)
+	assert.Equal(t, 200, w.Code)
+
+	login := dto.AccountLoginReq{Username: o.Username, Password: req.NewPassword}
+	w = doTest("POST", server.RootAccount+server.PathLogin, login, "")
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestAccount_ResetPassword_Success(t *testing.T) {
+	email := strings.ToLower(util.RandomStringAlpha(8)) + "@example.com"
+	o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	o.Email = &email
+	_ = accountRepo.Create(context.Background(), &o)
+
+	w := doTest("POST", server.RootAccount+server.PathPasswordForgot,
+		dto.AccountPasswordForgotReq{Email: email}, "")
+	assert.Equal(t, 200, w.Code)
+
+	var count int64
+	db.Model(&dao.PasswordReset{}).Where("account_id = ?", o.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// Unknown addresses get the same answer.
+	w = doTest("POST", server.RootAccount+server.PathPasswordForgot,
+		dto.AccountPasswordForgotReq{Email: "nobody@example.com"}, "")
+	assert.Equal(t, 200, w.Code)
+
+	// The mailed token is not observable here, so issue one directly.
+	token := util.RandomString(40)
+	_ = passwordResetRepo.Create(context.Background(), &dao.PasswordReset{
+		CreatedAt: time.Now(),
+		AccountID: o.ID,
+		TokenHash: util.SHA256Hex(token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	req := dto.AccountPasswordResetReq{Token: token, NewPassword: "NewPaswd123"}
+	w = doTest("POST", server.RootAccount+server.PathPasswordReset, req, "")
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("POST", server.RootAccount+server.PathPasswordReset, req, "")
+	assert.Equal(t, 400, w.Code)
+
+	login := dto.AccountLoginReq{Username: o.Username, Password: req.NewPassword}
+	w = doTest("POST", server.RootAccount+server.PathLogin, login, "")
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestAccount_Register_Success(t *testing.T) {
+	req := dto.AccountRegisterReq{
+		Username:     util.RandomStringAlpha(10),
+		Password:     password,
+		Fullname:     "Budi Santoso",
+		Gender:       "m",
+		BirthDateStr: "2000-01-02",
+	}
+
+	w := doTest("POST", server.RootAccount+server.PathRegister, req, "")
+	assert.Equal(t, 201, w.Code)
+	var resp dto.SuccessResponse[dto.AccountRegisterResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NotZero(t, resp.Data.PersonID)
+
+	w = doTest("GET", server.RootAccount, nil, login(t, req.Username).AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("POST", server.RootAccount+server.PathRegister, req, "")
+	assert.Equal(t, 409, w.Code)
+}
@@ -0,0 +1,78 @@
+package integration_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Scopes(t *testing.T) {
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	req := dto.APIKeyCreateReq{
+		Name:   "kiosk",
+		Scopes: []domain.TypeScope{domain.ScopeBorrowingsRead},
+	}
+	w := doTest("POST", server.RootAccount+server.PathAPIKeys, req, token)
+	assert.Equal(t, 201, w.Code)
+	var created dto.SuccessResponse[dto.APIKeyCreateResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	key := created.Data.Key
+	assert.NotEmpty(t, key)
+
+	createLateBorrowing(0)
+	w = doTestWithAPIKey("GET", server.RootBorrowing, nil, key)
+	assert.Equal(t, 200, w.Code)
+
+	// Outside its scopes the key is refused even though its owner is an admin.
+	w = doTestWithAPIKey("POST", server.RootPublisher, dto.PublisherCreateReq{Name: "Kiosk", City: "Jakarta"}, key)
+	assert.Equal(t, 403, w.Code)
+
+	// Keys cannot manage keys.
+	w = doTestWithAPIKey("GET", server.RootAccount+server.PathAPIKeys, nil, key)
+	assert.Equal(t, 401, w.Code)
+
+	w = doTest("GET", server.RootAccount+server.PathAPIKeys, nil, token)
+	assert.Equal(t, 200, w.Code)
+	var list dto.SuccessResponse[[]dto.APIKeyResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	var found *dto.APIKeyResp
+	for i := range list.Data {
+		if list.Data[i].ID == created.Data.ID {
+			found = &list.Data[i]
+		}
+	}
+	if assert.NotNil(t, found) {
+		assert.NotNil(t, found.LastUsedAt)
+		assert.NotEmpty(t, found.LastUsedIP)
+	}
+
+	path := fmt.Sprintf("%s/api-keys/%d", server.RootAccount, created.Data.ID)
+	w = doTest("DELETE", path, nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTestWithAPIKey("GET", server.RootBorrowing, nil, key)
+	assert.Equal(t, 401, w.Code)
+}
+
+func TestAPIKey_RoleStillApplies(t *testing.T) {
+	token := createAuthAccessToken(dummyMember.Account.Username)
+
+	req := dto.APIKeyCreateReq{
+		Name:   "sync",
+		Scopes: []domain.TypeScope{domain.ScopeCatalogWrite},
+	}
+	w := doTest("POST", server.RootAccount+server.PathAPIKeys, req, token)
+	assert.Equal(t, 201, w.Code)
+	var created dto.SuccessResponse[dto.APIKeyCreateResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+
+	w = doTestWithAPIKey("POST", server.RootPublisher,
+		dto.PublisherCreateReq{Name: "Sync", City: "Bandung"}, created.Data.Key)
+	assert.Equal(t, 403, w.Code)
+}
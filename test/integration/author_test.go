@@ -6,11 +6,11 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/server"
 	"base-gin/util"
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
-
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,7 +29,7 @@ func TestAuthor_Create_Success(t *testing.T) {
 		params,
 		createAuthAccessToken(dummyAdmin.Account.Username),
 	)
-	
+
 	assert.Equal(t, 201, w.Code)
 	fmt.Printf("%+v\n", params)
 }
@@ -42,12 +42,12 @@ func TestAuthor_Update_Success(t *testing.T) {
 		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_ = authorRepo.Create(&o)
+	_ = authorRepo.Create(context.Background(), &o)
 
 	newGender := domain.GenderFemale
 	params := dto.AuthorUpdateReq{
-		ID:       o.ID,
-		Fullname: util.RandomStringAlpha(7),
+		ID:        o.ID,
+		Fullname:  util.RandomStringAlpha(7),
 		Gender:    &newGender,
 		BirthDate: &birthDate,
 	}
@@ -60,7 +60,7 @@ func TestAuthor_Update_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := authorRepo.GetByID(o.ID)
+	item, _ := authorRepo.GetByID(context.Background(), o.ID)
 	assert.Equal(t, params.Fullname, item.Fullname)
 	assert.Equal(t, params.Gender, item.Gender)
 	assert.WithinDuration(t, *params.BirthDate, *item.BirthDate, time.Second, "Birth dates do not match")
@@ -74,7 +74,7 @@ func TestAuthor_Delete_Success(t *testing.T) {
 		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_ = authorRepo.Create(&o)
+	_ = authorRepo.Create(context.Background(), &o)
 
 	w := doTest(
 		"DELETE",
@@ -84,7 +84,7 @@ func TestAuthor_Delete_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := authorRepo.GetByID(o.ID)
+	item, _ := authorRepo.GetByID(context.Background(), o.ID)
 	assert.Nil(t, item)
 }
 
@@ -96,14 +96,14 @@ func TestAuthor_GetList_Success(t *testing.T) {
 		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_ = authorRepo.Create(&o1)
+	_ = authorRepo.Create(context.Background(), &o1)
 
 	o2 := dao.Author{
 		Fullname:  util.RandomStringAlpha(6),
 		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_ = authorRepo.Create(&o2)
+	_ = authorRepo.Create(context.Background(), &o2)
 
 	w := doTest(
 		"GET",
@@ -138,7 +138,7 @@ func TestAuthor_GetDetail_Success(t *testing.T) {
 		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_ = authorRepo.Create(&o)
+	_ = authorRepo.Create(context.Background(), &o)
 
 	w := doTest(
 		"GET",
@@ -0,0 +1,88 @@
+package integration_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookCopy_Create_Success(t *testing.T) {
+	b := CreateBook()
+
+	acquired := time.Now().AddDate(-1, 0, 0)
+	params := dto.BookCopyCreateReq{
+		Barcode:         util.RandomString(12),
+		ShelfLocation:   "A-01",
+		Condition:       string(domain.CopyConditionNew),
+		AcquisitionDate: &acquired,
+	}
+
+	w := doTest(
+		"POST",
+		strings.Replace(server.RootBookCopy, ":id", fmt.Sprint(b.ID), 1),
+		params,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 201, w.Code)
+
+	items, err := bookCopyRepo.GetList(context.Background(), b.ID, &dto.Filter{})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, params.Barcode, items[0].Barcode)
+	assert.Equal(t, domain.CopyStatusCirculating, items[0].Status)
+}
+
+func TestBookCopy_Delete_OnLoan(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	p := CreatePerson()
+
+	borrowDate := time.Now()
+	_ = borrowingRepo.Create(context.Background(), &dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   p.ID,
+	})
+
+	w := doTest(
+		"DELETE",
+		fmt.Sprintf("%s/%d", strings.Replace(server.RootBookCopy, ":id", fmt.Sprint(b.ID), 1), c.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestBook_GetByID_CopyCount(t *testing.T) {
+	b := CreateBook()
+	c1 := CreateBookCopy(b)
+	CreateBookCopy(b)
+	p := CreatePerson()
+
+	borrowDate := time.Now()
+	_ = borrowingRepo.Create(context.Background(), &dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c1.ID,
+		PersonID:   p.ID,
+	})
+
+	w := doTest("GET", fmt.Sprintf("%s/%d", server.RootBook, b.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, 2, resp.Data.TotalCopies)
+	assert.Equal(t, 1, resp.Data.AvailableCopies)
+}
@@ -6,6 +6,8 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/server"
 	"base-gin/util"
+	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -18,31 +20,31 @@ func TestBook_Create_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(8),
 		City: util.RandomStringAlpha(10),
 	}
-	_= publisherRepo.Create(&p)
+	_ = publisherRepo.Create(context.Background(), &p)
 
 	birthDate := time.Now().AddDate(-30, 0, 0)
 	gender := domain.GenderMale
 	a := dao.Author{
-		Fullname: util.RandomStringAlpha(8),
-		Gender: &gender,
+		Fullname:  util.RandomStringAlpha(8),
+		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_= authorRepo.Create(&a)
-	
+	_ = authorRepo.Create(context.Background(), &a)
+
 	params := dto.BookCreateReq{
-		Title:       util.RandomStringAlpha(10),
-		Subtitle:    util.RandomStringAlpha(15),
-		AuthorID:    a.ID,
-		PublisherID: p.ID,
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dto.BookContributorReq{{AuthorID: a.ID, Role: "author"}},
+		PublisherID:  p.ID,
 	}
-	
+
 	w := doTest(
 		"POST",
-		server.RootBook, 
+		server.RootBook,
 		params,
 		createAuthAccessToken(dummyAdmin.Account.Username),
 	)
-	
+
 	assert.Equal(t, 201, w.Code)
 
 	fmt.Printf("%+v\n", params)
@@ -53,45 +55,45 @@ func TestBook_Update_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(8),
 		City: util.RandomStringAlpha(10),
 	}
-	_= publisherRepo.Create(&p)
+	_ = publisherRepo.Create(context.Background(), &p)
 
-    birthDate := time.Now().AddDate(-30, 0, 0)
+	birthDate := time.Now().AddDate(-30, 0, 0)
 	gender := domain.GenderMale
 	a := dao.Author{
-		Fullname: util.RandomStringAlpha(8),
-		Gender: &gender,
+		Fullname:  util.RandomStringAlpha(8),
+		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_= authorRepo.Create(&a)
-
-    p2 := dao.Publisher{
-        Name: util.RandomStringAlpha(7),
-        City: util.RandomStringAlpha(8),
-    }
-    _ = publisherRepo.Create(&p2)
-
-    a2 := dao.Author{
-        Fullname:  util.RandomStringAlpha(9),
-        Gender:    &gender,
-        BirthDate: &birthDate,
-    }
-    _ = authorRepo.Create(&a2)
-
-    b := dao.Book{
-		Title:       util.RandomStringAlpha(10),
-		Subtitle:    util.RandomStringAlpha(15),
-		AuthorID:    a.ID,
-		PublisherID: p.ID,
+	_ = authorRepo.Create(context.Background(), &a)
+
+	p2 := dao.Publisher{
+		Name: util.RandomStringAlpha(7),
+		City: util.RandomStringAlpha(8),
+	}
+	_ = publisherRepo.Create(context.Background(), &p2)
+
+	a2 := dao.Author{
+		Fullname:  util.RandomStringAlpha(9),
+		Gender:    &gender,
+		BirthDate: &birthDate,
+	}
+	_ = authorRepo.Create(context.Background(), &a2)
+
+	b := dao.Book{
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dao.BookContributor{{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+		PublisherID:  p.ID,
 	}
-	_ = bookRepo.Create(&b)
+	_ = bookRepo.Create(context.Background(), &b)
 
 	fmt.Printf("First Borrowing: %+v\n", b)
 
 	params := dto.BookUpdateReq{
-		Title:       util.RandomStringAlpha(7),
-		Subtitle:    util.RandomStringAlpha(12),
-		AuthorID:    a2.ID,
-		PublisherID: p2.ID,
+		Title:        util.RandomStringAlpha(7),
+		Subtitle:     util.RandomStringAlpha(12),
+		Contributors: []dto.BookContributorReq{{AuthorID: a2.ID, Role: "author"}},
+		PublisherID:  p2.ID,
 	}
 
 	w := doTest(
@@ -103,58 +105,59 @@ func TestBook_Update_Success(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 
 	fmt.Printf("Updated Borrowing: %+v\n", params)
-	item, _ := bookRepo.GetByID(b.ID)
+	item, _ := bookRepo.GetByID(context.Background(), b.ID)
 	assert.Equal(t, params.Title, item.Title)
 	assert.Equal(t, params.Subtitle, item.Subtitle)
-	assert.Equal(t, params.AuthorID, item.AuthorID)
+	if assert.Len(t, item.Contributors, 1) {
+		assert.Equal(t, a2.ID, item.Contributors[0].AuthorID)
+	}
 	assert.Equal(t, params.PublisherID, item.PublisherID)
 }
 
-
 func TestBook_GetList_Success(t *testing.T) {
-    p := dao.Publisher{
-        Name: util.RandomStringAlpha(8),
-        City: util.RandomStringAlpha(10),
-    }
-    _ = publisherRepo.Create(&p)
-
-    birthDate := time.Now().AddDate(-30, 0, 0)
-    gender := domain.GenderMale
-    a := dao.Author{
-        Fullname:  util.RandomStringAlpha(8),
-        Gender:    &gender,
-        BirthDate: &birthDate,
-    }
-    _ = authorRepo.Create(&a)
-
-    p2 := dao.Publisher{
-        Name: util.RandomStringAlpha(8),
-        City: util.RandomStringAlpha(10),
-    }
-    _ = publisherRepo.Create(&p2)
-
-    a2 := dao.Author{
-        Fullname:  util.RandomStringAlpha(8),
-        Gender:    &gender,
-        BirthDate: &birthDate,
-    }
-    _ = authorRepo.Create(&a2)
-
-    b1 := dao.Book{
-		Title:       util.RandomStringAlpha(10),
-		Subtitle:    util.RandomStringAlpha(15),
-		AuthorID:    a.ID,
-		PublisherID: p.ID,
+	p := dao.Publisher{
+		Name: util.RandomStringAlpha(8),
+		City: util.RandomStringAlpha(10),
+	}
+	_ = publisherRepo.Create(context.Background(), &p)
+
+	birthDate := time.Now().AddDate(-30, 0, 0)
+	gender := domain.GenderMale
+	a := dao.Author{
+		Fullname:  util.RandomStringAlpha(8),
+		Gender:    &gender,
+		BirthDate: &birthDate,
+	}
+	_ = authorRepo.Create(context.Background(), &a)
+
+	p2 := dao.Publisher{
+		Name: util.RandomStringAlpha(8),
+		City: util.RandomStringAlpha(10),
 	}
-	_ = bookRepo.Create(&b1)
+	_ = publisherRepo.Create(context.Background(), &p2)
 
-    b2 := dao.Book{
-		Title:       util.RandomStringAlpha(7),
-		Subtitle:    util.RandomStringAlpha(14),
-		AuthorID:    a2.ID,
-		PublisherID: p2.ID,
+	a2 := dao.Author{
+		Fullname:  util.RandomStringAlpha(8),
+		Gender:    &gender,
+		BirthDate: &birthDate,
 	}
-	_ = bookRepo.Create(&b2)
+	_ = authorRepo.Create(context.Background(), &a2)
+
+	b1 := dao.Book{
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dao.BookContributor{{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+		PublisherID:  p.ID,
+	}
+	_ = bookRepo.Create(context.Background(), &b1)
+
+	b2 := dao.Book{
+		Title:        util.RandomStringAlpha(7),
+		Subtitle:     util.RandomStringAlpha(14),
+		Contributors: []dao.BookContributor{{AuthorID: a2.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+		PublisherID:  p2.ID,
+	}
+	_ = bookRepo.Create(context.Background(), &b2)
 
 	w := doTest(
 		"GET",
@@ -178,7 +181,7 @@ func TestBook_GetList_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	body = w.Body.String() 
+	body = w.Body.String()
 	assert.Contains(t, body, b1.Title)
 	assert.NotContains(t, body, b2.Title)
 
@@ -190,26 +193,26 @@ func TestBook_GetByID_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(8),
 		City: util.RandomStringAlpha(10),
 	}
-	_= publisherRepo.Create(&p)
+	_ = publisherRepo.Create(context.Background(), &p)
 
 	birthDate := time.Now().AddDate(-30, 0, 0)
 	gender := domain.GenderMale
 	a := dao.Author{
-		Fullname: util.RandomStringAlpha(8),
-		Gender: &gender,
+		Fullname:  util.RandomStringAlpha(8),
+		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_= authorRepo.Create(&a)
+	_ = authorRepo.Create(context.Background(), &a)
 
 	b := dao.Book{
-		Title:       util.RandomStringAlpha(10),
-		Subtitle:    util.RandomStringAlpha(15),
-		AuthorID:    a.ID,
-		PublisherID: p.ID,
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dao.BookContributor{{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+		PublisherID:  p.ID,
 	}
-	_ = bookRepo.Create(&b)
+	_ = bookRepo.Create(context.Background(), &b)
 
-	o, err := bookRepo.GetByID(b.ID)
+	o, err := bookRepo.GetByID(context.Background(), b.ID)
 	assert.Nil(t, err)
 	fmt.Printf("%+v\n", o)
 }
@@ -219,25 +222,25 @@ func TestBook_Delete_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(8),
 		City: util.RandomStringAlpha(10),
 	}
-	_= publisherRepo.Create(&p)
+	_ = publisherRepo.Create(context.Background(), &p)
 
 	birthDate := time.Now().AddDate(-30, 0, 0)
 	gender := domain.GenderMale
 	a := dao.Author{
-		Fullname: util.RandomStringAlpha(8),
-		Gender: &gender,
+		Fullname:  util.RandomStringAlpha(8),
+		Gender:    &gender,
 		BirthDate: &birthDate,
 	}
-	_= authorRepo.Create(&a)
+	_ = authorRepo.Create(context.Background(), &a)
 
 	b := dao.Book{
-		Title:       util.RandomStringAlpha(10),
-		Subtitle:    util.RandomStringAlpha(15),
-		AuthorID:    a.ID,
-		PublisherID: p.ID,
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dao.BookContributor{{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+		PublisherID:  p.ID,
 	}
-	_ = bookRepo.Create(&b)
-	
+	_ = bookRepo.Create(context.Background(), &b)
+
 	w := doTest(
 		"DELETE",
 		fmt.Sprintf("%s/%d", server.RootBook, b.ID),
@@ -247,6 +250,358 @@ func TestBook_Delete_Success(t *testing.T) {
 
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := bookRepo.GetByID(b.ID)
+	item, _ := bookRepo.GetByID(context.Background(), b.ID)
 	assert.Nil(t, item)
-}
\ No newline at end of file
+}
+func TestBook_Create_Contributors(t *testing.T) {
+	a := CreateAuthor()
+	editor := CreateAuthor()
+	p := CreatePublisher()
+
+	params := dto.BookCreateReq{
+		Title:    util.RandomStringAlpha(10),
+		Subtitle: util.RandomStringAlpha(15),
+		Contributors: []dto.BookContributorReq{
+			{AuthorID: editor.ID, Role: "editor"},
+			{AuthorID: a.ID, Role: "author"},
+			{AuthorID: a.ID, Role: "illustrator"},
+		},
+		PublisherID: p.ID,
+	}
+	w := doTest("POST", server.RootBook, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 201, w.Code)
+
+	w = doTest("GET", server.RootBook+"?q="+params.Title, nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data, 1) && assert.Len(t, resp.Data[0].Contributors, 3) {
+		contributors := resp.Data[0].Contributors
+		assert.Equal(t, int(editor.ID), contributors[0].AuthorID)
+		assert.Equal(t, domain.ContributorRoleEditor, contributors[0].Role)
+		assert.Equal(t, editor.Fullname, contributors[0].Fullname)
+		assert.Equal(t, domain.ContributorRoleAuthor, contributors[1].Role)
+		assert.Equal(t, domain.ContributorRoleIllustrator, contributors[2].Role)
+	}
+}
+
+func TestBook_Create_ContributorInvalid(t *testing.T) {
+	a := CreateAuthor()
+	p := CreatePublisher()
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	params := dto.BookCreateReq{
+		Title:    util.RandomStringAlpha(10),
+		Subtitle: util.RandomStringAlpha(15),
+		Contributors: []dto.BookContributorReq{
+			{AuthorID: a.ID, Role: "author"},
+			{AuthorID: a.ID, Role: "author"},
+		},
+		PublisherID: p.ID,
+	}
+	w := doTest("POST", server.RootBook, params, accessToken)
+	assert.Equal(t, 400, w.Code)
+
+	params.Contributors = []dto.BookContributorReq{{AuthorID: 999999, Role: "author"}}
+	w = doTest("POST", server.RootBook, params, accessToken)
+	assert.Equal(t, 400, w.Code)
+
+	w = doTest("GET", server.RootBook+"?q="+params.Title, nil, "")
+	assert.Equal(t, 404, w.Code, "Buku tidak boleh tersimpan")
+}
+
+func TestBook_GetListByAuthor(t *testing.T) {
+	a := CreateAuthor()
+	translator := CreateAuthor()
+	p := CreatePublisher()
+
+	written := dao.Book{
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dao.BookContributor{{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+		PublisherID:  p.ID,
+	}
+	_ = bookRepo.Create(context.Background(), &written)
+
+	translated := dao.Book{
+		Title:    util.RandomStringAlpha(10),
+		Subtitle: util.RandomStringAlpha(15),
+		Contributors: []dao.BookContributor{
+			{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1},
+			{AuthorID: translator.ID, Role: domain.ContributorRoleTranslator, Position: 2},
+		},
+		PublisherID: p.ID,
+	}
+	_ = bookRepo.Create(context.Background(), &translated)
+
+	w := doTest("GET", fmt.Sprintf("%s/%d/books", server.RootAuthor, a.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, written.Title)
+	assert.Contains(t, body, translated.Title)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/books", server.RootAuthor, translator.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+	body = w.Body.String()
+	assert.NotContains(t, body, written.Title)
+	assert.Contains(t, body, translated.Title)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d/books", server.RootAuthor, CreateAuthor().ID), nil, "")
+	assert.Equal(t, 404, w.Code)
+}
+
+// randomISBN returns a valid ISBN-10 and its ISBN-13 form.
+func randomISBN() (string, string) {
+	body := util.RandomNumber(9)
+	for _, check := range "0123456789X" {
+		isbn10 := body + string(check)
+		if isbn13, err := util.NormalizeISBN(isbn10); err == nil {
+			return isbn10, isbn13
+		}
+	}
+
+	return "", ""
+}
+
+func TestBook_Create_ISBN(t *testing.T) {
+	a := CreateAuthor()
+	p := CreatePublisher()
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	isbn10, isbn13 := randomISBN()
+
+	year, pages := 2019, 320
+	params := dto.BookCreateReq{
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dto.BookContributorReq{{AuthorID: a.ID, Role: "author"}},
+		PublisherID:  p.ID,
+		BookDetailReq: dto.BookDetailReq{
+			ISBN:            isbn10,
+			Edition:         "2nd",
+			PublicationYear: &year,
+			Language:        "id",
+			Pages:           &pages,
+			Description:     util.RandomStringAlpha(40),
+		},
+	}
+	w := doTest("POST", server.RootBook, params, accessToken)
+	assert.Equal(t, 201, w.Code)
+
+	for _, isbn := range []string{isbn13, isbn10} {
+		w = doTest("GET", server.RootBook+"?isbn="+isbn, nil, "")
+		assert.Equal(t, 200, w.Code)
+
+		var resp dto.SuccessResponse[[]dto.BookResp]
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if assert.Len(t, resp.Data, 1) {
+			item := resp.Data[0]
+			assert.Equal(t, params.Title, item.Title)
+			if assert.NotNil(t, item.ISBN) {
+				assert.Equal(t, isbn13, *item.ISBN, "ISBN-10 harus disimpan sebagai ISBN-13")
+			}
+			assert.Equal(t, "2nd", item.Edition)
+			assert.Equal(t, &year, item.PublicationYear)
+			assert.Equal(t, "id", item.Language)
+			assert.Equal(t, &pages, item.Pages)
+			assert.Equal(t, params.Description, item.Description)
+		}
+	}
+
+	params.Title = util.RandomStringAlpha(10)
+	params.ISBN = isbn13
+	w = doTest("POST", server.RootBook, params, accessToken)
+	assert.Equal(t, 409, w.Code, "ISBN harus unik")
+}
+
+func TestBook_Create_ISBNInvalid(t *testing.T) {
+	a := CreateAuthor()
+	p := CreatePublisher()
+
+	params := dto.BookCreateReq{
+		Title:         util.RandomStringAlpha(10),
+		Subtitle:      util.RandomStringAlpha(15),
+		Contributors:  []dto.BookContributorReq{{AuthorID: a.ID, Role: "author"}},
+		PublisherID:   p.ID,
+		BookDetailReq: dto.BookDetailReq{ISBN: "9780306406158"},
+	}
+	w := doTest("POST", server.RootBook, params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 422, w.Code)
+
+	w = doTest("GET", server.RootBook+"?isbn=0306406153", nil, "")
+	assert.Equal(t, 422, w.Code)
+}
+
+func TestBook_Update_ISBN(t *testing.T) {
+	b := CreateBook()
+	other := CreateBook()
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	_, isbn13 := randomISBN()
+
+	params := dto.BookUpdateReq{
+		Title:         b.Title,
+		Subtitle:      b.Subtitle,
+		Contributors:  []dto.BookContributorReq{{AuthorID: b.Contributors[0].AuthorID, Role: "author"}},
+		PublisherID:   b.PublisherID,
+		BookDetailReq: dto.BookDetailReq{ISBN: isbn13, Language: "en"},
+	}
+	w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, b.ID), params, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	item, _ := bookRepo.GetByID(context.Background(), b.ID)
+	if assert.NotNil(t, item.ISBN) {
+		assert.Equal(t, isbn13, *item.ISBN)
+	}
+	assert.Equal(t, "en", item.Language)
+
+	params.Title = other.Title
+	params.PublisherID = other.PublisherID
+	w = doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, other.ID), params, accessToken)
+	assert.Equal(t, 409, w.Code, "ISBN harus unik")
+}
+
+func TestBook_GetList_Category(t *testing.T) {
+	a := CreateAuthor()
+	p := CreatePublisher()
+	root := CreateCategory(nil)
+	child := CreateCategory(root)
+	other := CreateCategory(nil)
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	params := dto.BookCreateReq{
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dto.BookContributorReq{{AuthorID: a.ID, Role: "author"}},
+		PublisherID:  p.ID,
+		CategoryIDs:  []uint{child.ID},
+	}
+	w := doTest("POST", server.RootBook, params, accessToken)
+	assert.Equal(t, 201, w.Code)
+
+	w = doTest("GET", fmt.Sprintf("%s?category_id=%d", server.RootBook, root.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data, 1, "Buku dalam subkategori harus ikut tampil") {
+		item := resp.Data[0]
+		assert.Equal(t, params.Title, item.Title)
+		if assert.Len(t, item.Categories, 1) {
+			assert.Equal(t, child.Code, item.Categories[0].Code)
+			assert.Equal(t, []string{root.Name, child.Name}, item.Categories[0].Path)
+		}
+	}
+
+	w = doTest("GET", fmt.Sprintf("%s?category_id=%d", server.RootBook, other.ID), nil, "")
+	assert.Equal(t, 404, w.Code)
+
+	params.Title = util.RandomStringAlpha(10)
+	params.CategoryIDs = []uint{999999}
+	w = doTest("POST", server.RootBook, params, accessToken)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestBook_Update_Category(t *testing.T) {
+	b := CreateBook()
+	first := CreateCategory(nil)
+	second := CreateCategory(nil)
+
+	params := dto.BookUpdateReq{
+		Title:        b.Title,
+		Subtitle:     b.Subtitle,
+		Contributors: []dto.BookContributorReq{{AuthorID: b.Contributors[0].AuthorID, Role: "author"}},
+		PublisherID:  b.PublisherID,
+		CategoryIDs:  []uint{first.ID, second.ID},
+	}
+	w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, b.ID), params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	item, _ := bookRepo.GetByID(context.Background(), b.ID)
+	assert.Len(t, item.Categories, 2)
+
+	params.CategoryIDs = []uint{second.ID}
+	w = doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, b.ID), params, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	item, _ = bookRepo.GetByID(context.Background(), b.ID)
+	if assert.Len(t, item.Categories, 1) {
+		assert.Equal(t, second.ID, item.Categories[0].ID)
+	}
+}
+
+func TestBook_Update_NotFound(t *testing.T) {
+	b := CreateBook()
+	other := CreateAuthor()
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	params := dto.BookUpdateReq{
+		Title:        b.Title,
+		Subtitle:     b.Subtitle,
+		Contributors: []dto.BookContributorReq{{AuthorID: other.ID, Role: "author"}},
+		PublisherID:  b.PublisherID,
+	}
+	w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, 999999), params, accessToken)
+	assert.Equal(t, 404, w.Code)
+
+	_ = bookRepo.Delete(context.Background(), b.ID)
+	w = doTest("PUT", fmt.Sprintf("%s/%d", server.RootBook, b.ID), params, accessToken)
+	assert.Equal(t, 404, w.Code)
+
+	var contributors []dao.BookContributor
+	db.Where("book_id = ?", b.ID).Find(&contributors)
+	if assert.Len(t, contributors, 1, "Kontributor buku yang dihapus tidak boleh berubah") {
+		assert.Equal(t, b.Contributors[0].AuthorID, contributors[0].AuthorID)
+	}
+}
+
+func TestBook_GetList_FilterSort(t *testing.T) {
+	a := CreateAuthor()
+	p := CreatePublisher()
+
+	var titles []string
+	for _, title := range []string{"Alpha " + util.RandomStringAlpha(6), "Beta " + util.RandomStringAlpha(6)} {
+		b := dao.Book{
+			Title:        title,
+			Subtitle:     util.RandomStringAlpha(15),
+			Contributors: []dao.BookContributor{{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+			PublisherID:  p.ID,
+		}
+		_ = bookRepo.Create(context.Background(), &b)
+		titles = append(titles, title)
+	}
+	_ = CreateBook()
+
+	w := doTest("GET", fmt.Sprintf("%s?author_id=%d&sort=-title", server.RootBook, a.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BookResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data, 2, "Hanya buku dari penulis yang diminta") {
+		assert.Equal(t, titles[1], resp.Data[0].Title)
+		assert.Equal(t, titles[0], resp.Data[1].Title)
+	}
+
+	w = doTest("GET", fmt.Sprintf("%s?publisher_id[in]=%d,999999", server.RootBook, p.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+	resp = dto.SuccessResponse[[]dto.BookResp]{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp.Data, 2)
+}
+
+func TestBook_GetList_InvalidQuery(t *testing.T) {
+	w := doTest("GET", server.RootBook+"?sort=password&foo=1&publication_year[gt]=abc", nil, "")
+	assert.Equal(t, 422, w.Code)
+
+	var resp struct {
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	var fields []string
+	for _, e := range resp.Errors {
+		fields = append(fields, e.Field)
+	}
+	assert.ElementsMatch(t, []string{"sort", "foo", "publication_year[gt]"}, fields)
+}
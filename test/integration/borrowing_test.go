@@ -6,14 +6,17 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/server"
 	"base-gin/util"
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func CreateAuthor()*dao.Author {
+func CreateAuthor() *dao.Author {
 	birthDate := time.Now().AddDate(-30, 0, 0)
 	gender := domain.GenderMale
 	a := dao.Author{
@@ -43,10 +46,10 @@ func CreateBook() *dao.Book {
 	p := CreatePublisher()
 
 	b := dao.Book{
-		Title:       util.RandomStringAlpha(10),
-		Subtitle:    util.RandomStringAlpha(15),
-		AuthorID:    a.ID,                      
-		PublisherID: p.ID,                     
+		Title:        util.RandomStringAlpha(10),
+		Subtitle:     util.RandomStringAlpha(15),
+		Contributors: []dao.BookContributor{{AuthorID: a.ID, Role: domain.ContributorRoleAuthor, Position: 1}},
+		PublisherID:  p.ID,
 	}
 
 	db.Create(&b)
@@ -54,6 +57,19 @@ func CreateBook() *dao.Book {
 	return &b
 }
 
+func CreateBookCopy(b *dao.Book) *dao.BookCopy {
+	c := dao.BookCopy{
+		BookID:    b.ID,
+		Barcode:   util.RandomString(12),
+		Condition: domain.CopyConditionGood,
+		Status:    domain.CopyStatusCirculating,
+	}
+
+	db.Create(&c)
+
+	return &c
+}
+
 func CreatePerson() *dao.Person {
 	birthDate := time.Now().AddDate(-30, 0, 0)
 	gender := domain.GenderFemale
@@ -68,15 +84,54 @@ func CreatePerson() *dao.Person {
 
 func TestBorrowing_Create_Success(t *testing.T) {
 	b := CreateBook()
+	c := CreateBookCopy(b)
 	p := CreatePerson()
 
+	// A client cannot backdate the loan or open it already returned.
+	params := map[string]interface{}{
+		"borrow_date":  time.Now().AddDate(0, -1, 0),
+		"return_date":  time.Now().AddDate(0, 0, -7),
+		"book_copy_id": c.ID,
+		"person_id":    p.ID,
+	}
+
+	before := time.Now().Add(-time.Second)
+	w := doTest(
+		"POST",
+		server.RootBorrowing,
+		params,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+
+	assert.Equal(t, 201, w.Code)
+
+	var item dao.Borrowing
+	db.Where("book_copy_id = ?", c.ID).First(&item)
+	assert.Equal(t, p.ID, item.PersonID)
+	assert.Nil(t, item.ReturnDate)
+	if assert.NotNil(t, item.BorrowDate) {
+		assert.True(t, item.BorrowDate.After(before))
+	}
+}
+
+func TestBorrowing_Create_BookUnavailable(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	p1 := CreatePerson()
+	p2 := CreatePerson()
+
 	borrowDate := time.Now()
-	returnDate := borrowDate.AddDate(0, 0, 7) 
-	params := dto.BorrowingCreateReq{
+	open := dao.Borrowing{
 		BorrowDate: &borrowDate,
-		ReturnDate: &returnDate,
 		BookID:     b.ID,
-		PersonID:   p.ID,
+		BookCopyID: c.ID,
+		PersonID:   p1.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &open)
+
+	params := dto.BorrowingCreateReq{
+		BookCopyID: c.ID,
+		PersonID:   p2.ID,
 	}
 
 	w := doTest(
@@ -85,128 +140,242 @@ func TestBorrowing_Create_Success(t *testing.T) {
 		params,
 		createAuthAccessToken(dummyAdmin.Account.Username),
 	)
-	
-	assert.Equal(t, 201, w.Code)
-	
-	assert.NotNil(t, params.BorrowDate)
-	assert.NotNil(t, params.ReturnDate)
-	assert.Equal(t, b.ID, params.BookID)
-	assert.Equal(t, p.ID, params.PersonID)
-	
-	fmt.Printf("%+v\n", params)
+	assert.Equal(t, 409, w.Code)
 }
 
-func TestBorrowing_Update_Success(t *testing.T) {
+func TestBorrowing_Renew_Success(t *testing.T) {
 	b := CreateBook()
-	b2 := CreateBook()
+	c := CreateBookCopy(b)
 	p := CreatePerson()
 
 	borrowDate := time.Now()
-	returnDate := borrowDate.AddDate(0, 0, 7) 
+	dueDate := borrowDate.AddDate(0, 0, cfg.Library.LoanPeriodDays)
 	params := dao.Borrowing{
 		BorrowDate: &borrowDate,
-		ReturnDate: &returnDate,
+		DueDate:    &dueDate,
 		BookID:     b.ID,
+		BookCopyID: c.ID,
 		PersonID:   p.ID,
 	}
-	_ = borrowingRepo.Create(&params)
+	_ = borrowingRepo.Create(context.Background(), &params)
 
-	fmt.Printf("First Borrowing: %+v\n", params)
-	paramsUpdate := dto.BorrowingUpdateReq{
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/renew", server.RootBorrowing, params.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+
+	item, _ := borrowingRepo.GetByID(context.Background(), params.ID)
+	assert.Equal(t, 1, item.RenewalCount)
+	assert.WithinDuration(t, dueDate.AddDate(0, 0, cfg.Library.LoanPeriodDays), *item.DueDate, time.Second)
+}
+
+func TestBorrowing_Renew_Overdue(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	p := CreatePerson()
+
+	borrowDate := time.Now().AddDate(0, 0, -30)
+	dueDate := borrowDate.AddDate(0, 0, 14)
+	params := dao.Borrowing{
 		BorrowDate: &borrowDate,
-		ReturnDate: &returnDate,
-		BookID:     b2.ID,
+		DueDate:    &dueDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
 		PersonID:   p.ID,
 	}
+	_ = borrowingRepo.Create(context.Background(), &params)
 
 	w := doTest(
-		"PUT",
-		fmt.Sprintf("%s/%d", server.RootBorrowing, params.ID),
-		paramsUpdate,
+		"POST",
+		fmt.Sprintf("%s/%d/renew", server.RootBorrowing, params.ID),
+		nil,
 		createAuthAccessToken(dummyAdmin.Account.Username),
 	)
+	assert.Equal(t, 409, w.Code)
 
+	w = doTest(
+		"GET",
+		server.RootBorrowing+server.PathOverdue,
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
 	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), c.Barcode)
+
+	// Overdue loans are open by definition, so there is no return filter.
+	w = doTest(
+		"GET",
+		server.RootBorrowing+server.PathOverdue+"?returned=true",
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 422, w.Code)
+}
 
-	// Fokus perhatikan pada BookID
-	fmt.Printf("Updated Borrowing: %+v\n", paramsUpdate)
+func TestBorrowing_Return_Success(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	p := CreatePerson()
 
-	item, _ := borrowingRepo.GetByID(params.ID)
-	assert.WithinDuration(t, *paramsUpdate.BorrowDate, *item.BorrowDate, time.Second)
-	assert.WithinDuration(t, *paramsUpdate.ReturnDate, *item.ReturnDate, time.Second)
-	assert.Equal(t, paramsUpdate.BookID, item.BookID)
-	assert.Equal(t, paramsUpdate.PersonID, item.PersonID)
+	borrowDate := time.Now()
+	params := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   p.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &params)
+
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/return", server.RootBorrowing, params.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+
+	item, _ := borrowingRepo.GetByID(context.Background(), params.ID)
+	assert.NotNil(t, item.ReturnDate)
+	assert.Equal(t, dummyAdmin.Account.ID, *item.ReturnedByID)
+	assert.Equal(t, b.ID, item.BookID)
+	assert.Equal(t, p.ID, item.PersonID)
+
+	w = doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/return", server.RootBorrowing, params.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestBorrowing_ReturnByBook_Success(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	p := CreatePerson()
+
+	borrowDate := time.Now()
+	params := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   p.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &params)
+
+	// the copy's own ID is not what the desk scans
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/return-by-book/%d", server.RootBorrowing, c.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 404, w.Code)
+
+	w = doTest(
+		"POST",
+		server.RootBorrowing+"/return-by-book/"+url.PathEscape(c.Barcode),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+	var resp dto.SuccessResponse[dto.BorrowingResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, int(params.ID), resp.Data.ID)
+
+	item, _ := borrowingRepo.GetByID(context.Background(), params.ID)
+	assert.NotNil(t, item.ReturnDate)
+	assert.Equal(t, dummyAdmin.Account.ID, *item.ReturnedByID)
+
+	w = doTest(
+		"POST",
+		server.RootBorrowing+"/return-by-book/"+url.PathEscape(c.Barcode),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 404, w.Code)
 }
 
 func TestBorrowing_Getlist_Success(t *testing.T) {
 	b1 := CreateBook()
+	c1 := CreateBookCopy(b1)
 	b2 := CreateBook()
+	c2 := CreateBookCopy(b2)
 	p1 := CreatePerson()
 	p2 := CreatePerson()
 
 	borrowDate := time.Now()
-	returnDate := borrowDate.AddDate(0, 0, 7) 
+	returnDate := borrowDate.AddDate(0, 0, 7)
 	params1 := dao.Borrowing{
 		BorrowDate: &borrowDate,
 		ReturnDate: &returnDate,
 		BookID:     b1.ID,
+		BookCopyID: c1.ID,
 		PersonID:   p1.ID,
 	}
-	_ = borrowingRepo.Create(&params1)
+	_ = borrowingRepo.Create(context.Background(), &params1)
 
 	params2 := dao.Borrowing{
 		BorrowDate: &borrowDate,
 		ReturnDate: &returnDate,
 		BookID:     b2.ID,
+		BookCopyID: c2.ID,
 		PersonID:   p2.ID,
 	}
-	_ = borrowingRepo.Create(&params2)
+	_ = borrowingRepo.Create(context.Background(), &params2)
 
 	w := doTest(
 		"GET",
 		server.RootBorrowing,
 		nil,
-		"",
+		createAuthAccessToken(dummyAdmin.Account.Username),
 	)
 
 	assert.Equal(t, 200, w.Code)
-	
+
 	body := w.Body.String()
 	fmt.Printf("%+v\n", body)
 }
 
 func TestBorrowing_GetByID_Success(t *testing.T) {
 	b := CreateBook()
+	c := CreateBookCopy(b)
 	p := CreatePerson()
-	
+
 	borrowDate := time.Now()
-	returnDate := borrowDate.AddDate(0, 0, 7) 
+	returnDate := borrowDate.AddDate(0, 0, 7)
 	params := dao.Borrowing{
 		BorrowDate: &borrowDate,
 		ReturnDate: &returnDate,
 		BookID:     b.ID,
+		BookCopyID: c.ID,
 		PersonID:   p.ID,
 	}
-	_ = borrowingRepo.Create(&params)
+	_ = borrowingRepo.Create(context.Background(), &params)
 
-	o, err := bookRepo.GetByID(params.ID)
+	o, err := bookRepo.GetByID(context.Background(), params.ID)
 	assert.Nil(t, err)
 	fmt.Printf("%+v\n", o)
 }
 
 func TestBorrowing_Delete_Success(t *testing.T) {
 	b := CreateBook()
+	c := CreateBookCopy(b)
 	p := CreatePerson()
 
 	borrowDate := time.Now()
-	returnDate := borrowDate.AddDate(0, 0, 7) 
+	returnDate := borrowDate.AddDate(0, 0, 7)
 	params := dao.Borrowing{
 		BorrowDate: &borrowDate,
 		ReturnDate: &returnDate,
 		BookID:     b.ID,
+		BookCopyID: c.ID,
 		PersonID:   p.ID,
 	}
-	_ = borrowingRepo.Create(&params)
+	_ = borrowingRepo.Create(context.Background(), &params)
 
 	w := doTest(
 		"DELETE",
@@ -216,6 +385,84 @@ func TestBorrowing_Delete_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := borrowingRepo.GetByID(params.ID)
+	item, _ := borrowingRepo.GetByID(context.Background(), params.ID)
 	assert.Nil(t, item)
-}
\ No newline at end of file
+}
+
+func TestBorrowing_Getlist_MemberOwnOnly(t *testing.T) {
+	b := CreateBook()
+	c1 := CreateBookCopy(b)
+	c2 := CreateBookCopy(b)
+	other := CreatePerson()
+
+	borrowDate := time.Now()
+	own := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c1.ID,
+		PersonID:   dummyMember.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &own)
+	notOwn := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c2.ID,
+		PersonID:   other.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &notOwn)
+
+	token := createAuthAccessToken(dummyMember.Account.Username)
+
+	w := doTest("GET", server.RootBorrowing, nil, token)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), c1.Barcode)
+	assert.NotContains(t, w.Body.String(), c2.Barcode)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d", server.RootBorrowing, notOwn.ID), nil, token)
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("POST", fmt.Sprintf("%s/%d/return", server.RootBorrowing, own.ID), nil, token)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestBorrowing_GetList_Filter(t *testing.T) {
+	b := CreateBook()
+	returnedCopy := CreateBookCopy(b)
+	openCopy := CreateBookCopy(b)
+	p := CreatePerson()
+
+	borrowDate := time.Now().AddDate(0, 0, -3)
+	returnDate := time.Now()
+	returned := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		ReturnDate: &returnDate,
+		BookID:     b.ID,
+		BookCopyID: returnedCopy.ID,
+		PersonID:   p.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &returned)
+
+	open := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: openCopy.ID,
+		PersonID:   p.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &open)
+
+	url := fmt.Sprintf("%s?book_id=%d&returned=false&borrow_date=%s",
+		server.RootBorrowing, b.ID, borrowDate.UTC().Format("2006-01-02"))
+	w := doTest("GET", url, nil, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.BorrowingResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data, 1) {
+		assert.Equal(t, int(open.ID), resp.Data[0].ID)
+	}
+
+	url = fmt.Sprintf("%s?book_id=%d&borrow_date[gt]=%s",
+		server.RootBorrowing, b.ID, borrowDate.UTC().Format("2006-01-02"))
+	w = doTest("GET", url, nil, createAuthAccessToken(dummyAdmin.Account.Username))
+	assert.Equal(t, 404, w.Code)
+}
@@ -0,0 +1,129 @@
+package integration_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateCategory(parent *dao.Category) *dao.Category {
+	c := dao.Category{
+		Code: util.RandomNumber(3) + "." + util.RandomNumber(4),
+		Name: util.RandomStringAlpha(10),
+	}
+	if parent != nil {
+		c.ParentID = &parent.ID
+	}
+	_ = categoryRepo.Create(context.Background(), &c)
+
+	return &c
+}
+
+func TestCategory_Create_Success(t *testing.T) {
+	root := CreateCategory(nil)
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	params := dto.CategoryCreateReq{
+		ParentID: &root.ID,
+		Code:     util.RandomNumber(3) + "." + util.RandomNumber(3),
+		Name:     util.RandomStringAlpha(8),
+	}
+	w := doTest("POST", server.RootCategory, params, accessToken)
+	assert.Equal(t, 201, w.Code)
+
+	w = doTest("GET", server.RootCategory+"?q="+params.Code, nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.CategoryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if assert.Len(t, resp.Data, 1) {
+		assert.Equal(t, []string{root.Name, params.Name}, resp.Data[0].Path)
+	}
+
+	params.Name = util.RandomStringAlpha(8)
+	w = doTest("POST", server.RootCategory, params, accessToken)
+	assert.Equal(t, 409, w.Code, "Kode klasifikasi harus unik")
+
+	missing := uint(999999)
+	params.Code = util.RandomNumber(3) + "." + util.RandomNumber(3)
+	params.ParentID = &missing
+	w = doTest("POST", server.RootCategory, params, accessToken)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestCategory_Create_Forbidden(t *testing.T) {
+	params := dto.CategoryCreateReq{
+		Code: util.RandomNumber(3),
+		Name: util.RandomStringAlpha(8),
+	}
+	w := doTest("POST", server.RootCategory, params, createAuthAccessToken(dummyMember.Account.Username))
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestCategory_Update_Cycle(t *testing.T) {
+	root := CreateCategory(nil)
+	child := CreateCategory(root)
+	grandchild := CreateCategory(child)
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	params := dto.CategoryUpdateReq{
+		ParentID: &grandchild.ID,
+		Code:     root.Code,
+		Name:     root.Name,
+	}
+	w := doTest("PUT", fmt.Sprintf("%s/%d", server.RootCategory, root.ID), params, accessToken)
+	assert.Equal(t, 400, w.Code)
+
+	params.ParentID = &root.ID
+	w = doTest("PUT", fmt.Sprintf("%s/%d", server.RootCategory, root.ID), params, accessToken)
+	assert.Equal(t, 400, w.Code, "Kategori tidak boleh menjadi induk dirinya sendiri")
+
+	// moving the grandchild up to the root is allowed
+	params = dto.CategoryUpdateReq{
+		ParentID: &root.ID,
+		Code:     grandchild.Code,
+		Name:     util.RandomStringAlpha(8),
+	}
+	w = doTest("PUT", fmt.Sprintf("%s/%d", server.RootCategory, grandchild.ID), params, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", fmt.Sprintf("%s/%d", server.RootCategory, grandchild.ID), nil, "")
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.CategoryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, []string{root.Name, params.Name}, resp.Data.Path)
+
+	w = doTest("PUT", fmt.Sprintf("%s/%d", server.RootCategory, 999999), params, accessToken)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestCategory_Delete(t *testing.T) {
+	root := CreateCategory(nil)
+	child := CreateCategory(root)
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	b := CreateBook()
+	_ = db.Create(&dao.BookCategory{BookID: b.ID, CategoryID: child.ID}).Error
+
+	w := doTest("DELETE", fmt.Sprintf("%s/%d", server.RootCategory, root.ID), nil, accessToken)
+	assert.Equal(t, 409, w.Code, "Kategori yang masih memiliki subkategori tidak boleh dihapus")
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", server.RootCategory, child.ID), nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	_, err := categoryRepo.GetByID(context.Background(), child.ID)
+	assert.NotNil(t, err)
+	item, _ := bookRepo.GetByID(context.Background(), b.ID)
+	assert.Empty(t, item.Categories)
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", server.RootCategory, child.ID), nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+}
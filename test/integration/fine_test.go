@@ -0,0 +1,123 @@
+package integration_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createLateBorrowing(daysLate int) (*dao.Borrowing, *dao.Person) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	p := CreatePerson()
+
+	borrowDate := time.Now().AddDate(0, 0, -cfg.Library.LoanPeriodDays-daysLate)
+	// Every started day is charged, so keep the return inside the last one.
+	dueDate := time.Now().AddDate(0, 0, -daysLate).Add(time.Minute)
+	item := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		DueDate:    &dueDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   p.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &item)
+
+	return &item, p
+}
+
+func TestFine_LateReturn_Accrued(t *testing.T) {
+	item, p := createLateBorrowing(3)
+
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/return", server.RootBorrowing, item.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+
+	balance, err := fineRepo.GetBalance(context.Background(), p.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 3*cfg.Library.FinePerDay, balance)
+
+	w = doTest(
+		"GET",
+		fmt.Sprintf("%s/%d/fines", server.RootPerson, p.ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[dto.FineBalanceResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, balance, resp.Data.Balance)
+	assert.Len(t, resp.Data.Entries, 1)
+}
+
+func TestFine_PayAndWaive_Success(t *testing.T) {
+	item, p := createLateBorrowing(2)
+	_, _ = borrowingRepo.Return(context.Background(), item.ID, dummyAdmin.Account.ID, time.Now(), dao.CirculationPolicy{
+		Fine: dao.FinePolicy{
+			PerDay: cfg.Library.FinePerDay,
+			Max:    cfg.Library.FineMax,
+		},
+	})
+
+	fines, _ := fineRepo.GetListByPerson(context.Background(), p.ID, &dto.Filter{})
+	assert.Len(t, fines, 1)
+
+	w := doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/fines/payments", server.RootPerson, p.ID),
+		dto.FinePaymentReq{Amount: cfg.Library.FinePerDay},
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 201, w.Code)
+
+	w = doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/fines/%d/waive", server.RootPerson, p.ID, fines[0].ID),
+		dto.FineWaiveReq{Note: "first offence"},
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 201, w.Code)
+
+	balance, _ := fineRepo.GetBalance(context.Background(), p.ID)
+	assert.Equal(t, int64(0), balance)
+
+	w = doTest(
+		"POST",
+		fmt.Sprintf("%s/%d/fines/%d/waive", server.RootPerson, p.ID, fines[0].ID),
+		nil,
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestFine_Borrowing_Blocked(t *testing.T) {
+	p := CreatePerson()
+	_ = fineRepo.Create(context.Background(), &dao.Fine{
+		PersonID: p.ID,
+		Kind:     domain.FineKindFine,
+		Amount:   cfg.Library.FineBlockThreshold + 1,
+	})
+
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	w := doTest(
+		"POST",
+		server.RootBorrowing,
+		dto.BorrowingCreateReq{BookCopyID: c.ID, PersonID: p.ID},
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 403, w.Code)
+}
@@ -0,0 +1,201 @@
+package integration_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func holdPath(bookID uint) string {
+	return strings.Replace(server.RootBookHold, ":id", fmt.Sprint(bookID), 1)
+}
+
+func TestHold_Create_CopyAvailable(t *testing.T) {
+	b := CreateBook()
+	CreateBookCopy(b)
+	p := CreatePerson()
+
+	w := doTest(
+		"POST",
+		holdPath(b.ID),
+		dto.HoldCreateReq{PersonID: p.ID},
+		createAuthAccessToken(dummyAdmin.Account.Username),
+	)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestHold_Queue_ReadyOnReturn(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	borrower := CreatePerson()
+	first := CreatePerson()
+	second := CreatePerson()
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	borrowDate := time.Now()
+	loan := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   borrower.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &loan)
+
+	w := doTest("POST", holdPath(b.ID), dto.HoldCreateReq{PersonID: first.ID}, token)
+	assert.Equal(t, 201, w.Code)
+	w = doTest("POST", holdPath(b.ID), dto.HoldCreateReq{PersonID: second.ID}, token)
+	assert.Equal(t, 201, w.Code)
+	w = doTest("POST", holdPath(b.ID), dto.HoldCreateReq{PersonID: second.ID}, token)
+	assert.Equal(t, 409, w.Code)
+
+	w = doTest("GET", holdPath(b.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+	var queue dto.SuccessResponse[[]dto.HoldResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &queue)
+	assert.Len(t, queue.Data, 2)
+	assert.Equal(t, int(first.ID), queue.Data[0].PersonID)
+	assert.Equal(t, 1, queue.Data[0].Position)
+	assert.Equal(t, 2, queue.Data[1].Position)
+
+	// Renewing is refused while others are waiting.
+	w = doTest("POST", fmt.Sprintf("%s/%d/renew", server.RootBorrowing, loan.ID), nil, token)
+	assert.Equal(t, 409, w.Code)
+
+	w = doTest("POST", fmt.Sprintf("%s/%d/return", server.RootBorrowing, loan.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	queued, _ := holdRepo.GetQueue(context.Background(), b.ID)
+	assert.Equal(t, domain.HoldStatusReady, queued[0].Status)
+	assert.Equal(t, first.ID, queued[0].PersonID)
+	assert.Equal(t, c.ID, *queued[0].BookCopyID)
+
+	// Only the patron whose hold is ready may borrow the copy.
+	w = doTest("POST", server.RootBorrowing,
+		dto.BorrowingCreateReq{BookCopyID: c.ID, PersonID: second.ID}, token)
+	assert.Equal(t, 409, w.Code)
+	w = doTest("POST", server.RootBorrowing,
+		dto.BorrowingCreateReq{BookCopyID: c.ID, PersonID: first.ID}, token)
+	assert.Equal(t, 201, w.Code)
+
+	queued, _ = holdRepo.GetQueue(context.Background(), b.ID)
+	assert.Len(t, queued, 1)
+	assert.Equal(t, second.ID, queued[0].PersonID)
+}
+
+func TestHold_Borrow_OtherCopy(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	borrower := CreatePerson()
+	first := CreatePerson()
+	second := CreatePerson()
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	borrowDate := time.Now()
+	loan := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   borrower.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &loan)
+
+	w := doTest("POST", holdPath(b.ID), dto.HoldCreateReq{PersonID: first.ID}, token)
+	assert.Equal(t, 201, w.Code)
+	w = doTest("POST", holdPath(b.ID), dto.HoldCreateReq{PersonID: second.ID}, token)
+	assert.Equal(t, 201, w.Code)
+	w = doTest("POST", fmt.Sprintf("%s/%d/return", server.RootBorrowing, loan.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	// The first patron takes a newly acquired copy instead of the one set
+	// aside for them, which then goes to the second.
+	other := CreateBookCopy(b)
+	w = doTest("POST", server.RootBorrowing,
+		dto.BorrowingCreateReq{BookCopyID: other.ID, PersonID: first.ID}, token)
+	assert.Equal(t, 201, w.Code)
+
+	queued, _ := holdRepo.GetQueue(context.Background(), b.ID)
+	if assert.Len(t, queued, 1) {
+		assert.Equal(t, second.ID, queued[0].PersonID)
+		assert.Equal(t, domain.HoldStatusReady, queued[0].Status)
+		assert.Equal(t, c.ID, *queued[0].BookCopyID)
+	}
+}
+
+func TestHold_Create_ExpiresLapsedHolds(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	borrower := CreatePerson()
+	first := CreatePerson()
+	second := CreatePerson()
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	borrowDate := time.Now()
+	loan := dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   borrower.ID,
+	}
+	_ = borrowingRepo.Create(context.Background(), &loan)
+
+	w := doTest("POST", holdPath(b.ID), dto.HoldCreateReq{PersonID: first.ID}, token)
+	assert.Equal(t, 201, w.Code)
+	w = doTest("POST", fmt.Sprintf("%s/%d/return", server.RootBorrowing, loan.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	db.Model(&dao.Hold{}).
+		Where("book_id = ? AND person_id = ?", b.ID, first.ID).
+		Update("expires_at", time.Now().Add(-time.Hour))
+
+	// Reading the queue leaves the lapsed hold alone.
+	w = doTest("GET", holdPath(b.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+	var hold dao.Hold
+	db.Where("book_id = ? AND person_id = ?", b.ID, first.ID).First(&hold)
+	assert.Equal(t, domain.HoldStatusReady, hold.Status)
+
+	// Placing a hold expires it first, which frees the copy.
+	w = doTest("POST", holdPath(b.ID), dto.HoldCreateReq{PersonID: second.ID}, token)
+	assert.Equal(t, 409, w.Code)
+	w = doTest("POST", server.RootBorrowing,
+		dto.BorrowingCreateReq{BookCopyID: c.ID, PersonID: second.ID}, token)
+	assert.Equal(t, 201, w.Code)
+
+	hold = dao.Hold{}
+	db.Where("book_id = ? AND person_id = ?", b.ID, first.ID).First(&hold)
+	assert.Equal(t, domain.HoldStatusExpired, hold.Status)
+}
+
+func TestHold_Cancel_Success(t *testing.T) {
+	b := CreateBook()
+	c := CreateBookCopy(b)
+	borrower := CreatePerson()
+	p := CreatePerson()
+	token := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	borrowDate := time.Now()
+	_ = borrowingRepo.Create(context.Background(), &dao.Borrowing{
+		BorrowDate: &borrowDate,
+		BookID:     b.ID,
+		BookCopyID: c.ID,
+		PersonID:   borrower.ID,
+	})
+
+	hold := dao.Hold{BookID: b.ID, PersonID: p.ID}
+	_ = holdRepo.Create(context.Background(), &hold, time.Now().UTC(), 72*time.Hour)
+
+	w := doTest("DELETE", fmt.Sprintf("%s/%d", holdPath(b.ID), hold.ID), nil, token)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("DELETE", fmt.Sprintf("%s/%d", holdPath(b.ID), hold.ID), nil, token)
+	assert.Equal(t, 409, w.Code)
+}
@@ -1,16 +1,17 @@
 package integration_test
 
 import (
+	"base-gin/app"
 	"base-gin/config"
 	"base-gin/domain"
 	"base-gin/domain/dao"
+	"base-gin/migrations"
 	"base-gin/repository"
-	"base-gin/rest"
 	"base-gin/server"
-	"base-gin/service"
 	"base-gin/storage"
 	"base-gin/util"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -21,6 +22,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"gorm.io/gorm"
 )
@@ -30,19 +32,26 @@ const (
 )
 
 var (
-	cfg config.Config
-	db  *gorm.DB
-	app *gin.Engine
+	cfg         config.Config
+	db          *gorm.DB
+	application *app.App
+	hr          *server.Handler
 
 	dummyAdmin  *dao.Person
 	dummyMember *dao.Person
 
-	accountRepo   *repository.AccountRepository
-	personRepo    *repository.PersonRepository
-	publisherRepo *repository.PublisherRepository
-	authorRepo *repository.AuthorRepository
-	bookRepo *repository.BookRepository
-	borrowingRepo *repository.BorrowingRepository
+	accountRepo       repository.AccountRepository
+	personRepo        repository.PersonRepository
+	publisherRepo     repository.PublisherRepository
+	authorRepo        repository.AuthorRepository
+	categoryRepo      repository.CategoryRepository
+	bookRepo          repository.BookRepository
+	bookCopyRepo      repository.BookCopyRepository
+	borrowingRepo     repository.BorrowingRepository
+	fineRepo          repository.FineRepository
+	holdRepo          repository.HoldRepository
+	sessionRepo       repository.SessionRepository
+	passwordResetRepo repository.PasswordResetRepository
 )
 
 func TestMain(m *testing.M) {
@@ -60,28 +69,36 @@ func setup() {
 
 	cfg = config.NewConfig()
 
-	storage.InitDB(cfg)
-	db = storage.GetDB()
+	db = storage.NewDB(cfg)
 	teardownDB()
 	setupDB()
 
-	repository.SetupRepositories()
-	accountRepo = repository.GetAccountRepo()
-	personRepo = repository.GetPersonRepo()
-	publisherRepo = repository.GetPublisherRepo()
-	authorRepo = repository.GetAuthorRepo()
-	bookRepo = repository.GetBookRepo()
-	borrowingRepo = repository.GetBorrowingRepo()
-
-	a := createDummyAccount()
+	var err error
+	application, err = app.New(&cfg, db)
+	if err != nil {
+		log.Fatal(fmt.Errorf("Test.Integration: %w", err))
+	}
+	hr = application.Handler
+
+	repos := application.Repositories
+	accountRepo = repos.Account
+	personRepo = repos.Person
+	publisherRepo = repos.Publisher
+	authorRepo = repos.Author
+	categoryRepo = repos.Category
+	bookRepo = repos.Book
+	bookCopyRepo = repos.BookCopy
+	borrowingRepo = repos.Borrowing
+	fineRepo = repos.Fine
+	holdRepo = repos.Hold
+	sessionRepo = repos.Session
+	passwordResetRepo = repos.PasswordReset
+
+	a := createDummyAccount("admin", domain.RoleAdmin)
 	dummyAdmin = createDummyProfile(a)
-	dummyMember = createDummyProfile(nil)
+	m := createDummyAccount("member", domain.RoleMember)
+	dummyMember = createDummyProfile(m)
 	createDummyProfile(nil)
-
-	service.SetupServices(&cfg)
-
-	app = server.Init(&cfg, accountRepo)
-	rest.SetupRestHandlers(app)
 }
 
 func teardownDB() {
@@ -90,25 +107,41 @@ func teardownDB() {
 		&dao.Person{},
 		&dao.Publisher{},
 		&dao.Author{},
+		&dao.BookContributor{},
+		&dao.BookCategory{},
 		&dao.Book{},
+		&dao.Category{},
+		&dao.BookCopy{},
 		&dao.Borrowing{},
+		&dao.Fine{},
+		&dao.Hold{},
+		&dao.Session{},
+		&dao.RefreshToken{},
+		&dao.PasswordReset{},
+		&dao.RecoveryCode{},
+		&dao.RolePolicy{},
+		&dao.APIKey{},
+		&migrations.SchemaMigration{},
+		"books_fts",
+		"authors_fts",
+		"publishers_fts",
 	)
 }
 
 func setupDB() {
-	_ = db.AutoMigrate(
-		&dao.Account{},
-		&dao.Person{},
-		&dao.Publisher{},
-		&dao.Author{},
-		&dao.Book{},
-		&dao.Borrowing{},
-	)
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatal(fmt.Errorf("Test.Integration: %w", err))
+	}
+	if _, err := migrator.Up(); err != nil {
+		log.Fatal(fmt.Errorf("Test.Integration: %w", err))
+	}
 }
 
-func createDummyAccount() *dao.Account {
-	account, _ := dao.NewUser("admin", password, cfg.AuthN.PasswordEncryptionSecret)
-	accountRepo.Create(&account)
+func createDummyAccount(uname string, role domain.TypeRole) *dao.Account {
+	account, _ := dao.NewUser(uname, password, cfg.AuthN.PasswordEncryptionSecret)
+	account.Role = role
+	accountRepo.Create(context.Background(), &account)
 	return &account
 }
 
@@ -125,19 +158,43 @@ func createDummyProfile(account *dao.Account) *dao.Person {
 		person.Account = account
 	}
 
-	personRepo.Create(&person)
+	personRepo.Create(context.Background(), &person)
 
 	return &person
 }
 
 func createAuthAccessToken(username string) string {
-	token, err := util.CreateAuthAccessToken(cfg, username)
+	account, _ := accountRepo.GetByUsername(context.Background(), username)
+	session := dao.Session{
+		ID:        uuid.NewString(),
+		AccountID: account.ID,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	_ = sessionRepo.Create(context.Background(), &session)
+
+	token, err := util.CreateAuthAccessToken(cfg, application.KeySet, username, string(account.Role), session.ID)
 	if err != nil {
 		log.Fatal(fmt.Errorf("main_test.createAuthAccessToken %w", err))
 	}
 	return token
 }
 
+// useApplication swaps in a fresh application built with c on the same
+// database for the rest of the test, e.g. to start with clean login
+// counters or different keys.
+func useApplication(t *testing.T, c config.Config) {
+	t.Helper()
+
+	fresh, err := app.New(&c, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous, previousHandler := application, hr
+	application, hr = fresh, fresh.Handler
+	t.Cleanup(func() { application, hr = previous, previousHandler })
+}
+
 func doTest(
 	method, url string,
 	body interface{},
@@ -151,10 +208,44 @@ func doTest(
 		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", authAccessToken))
 	}
 	w := httptest.NewRecorder()
-	app.ServeHTTP(w, r)
+	application.Engine.ServeHTTP(w, r)
 	if w.Code >= 400 {
 		fmt.Printf("[REQUEST] %s \n", string(requestBody)) //nolint:forbidigo //debug
 		fmt.Printf("[RESPONSE] %s \n", w.Body.String())    //nolint:forbidigo //debug
 	}
 	return w
 }
+
+func doTestWithAPIKey(method, url string, body interface{}, apiKey string) *httptest.ResponseRecorder {
+	requestBody, _ := json.Marshal(body)
+	r, _ := http.NewRequest(method, url, bytes.NewBuffer(requestBody))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+	r.Header.Set(server.HeaderAPIKey, apiKey)
+	r.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	application.Engine.ServeHTTP(w, r)
+	return w
+}
+
+// doTestFrom is doTest for a request coming from ip.
+func doTestFrom(ip, method, url string, body interface{}) *httptest.ResponseRecorder {
+	requestBody, _ := json.Marshal(body)
+	r, _ := http.NewRequest(method, url, bytes.NewBuffer(requestBody))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+	r.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	application.Engine.ServeHTTP(w, r)
+	return w
+}
+
+func TestMigrations_UpToDate(t *testing.T) {
+	migrator, err := migrations.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Check(); err != nil {
+		t.Fatal(err)
+	}
+}
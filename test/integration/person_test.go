@@ -0,0 +1,75 @@
+package integration_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPerson_AttachDetachAccount(t *testing.T) {
+	o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	_ = accountRepo.Create(context.Background(), &o)
+	person := createDummyProfile(nil)
+	other := createDummyProfile(nil)
+
+	adminToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	path := fmt.Sprintf("%s/%d/account", server.RootPerson, person.ID)
+	req := dto.PersonAccountLinkReq{AccountID: o.ID}
+
+	w := doTest("PUT", path, req, createAuthAccessToken(dummyMember.Account.Username))
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("PUT", path, req, adminToken)
+	assert.Equal(t, 200, w.Code)
+
+	w = doTest("GET", server.RootAccount, nil, createAuthAccessToken(o.Username))
+	assert.Equal(t, 200, w.Code)
+
+	// The account already has a person.
+	otherPath := fmt.Sprintf("%s/%d/account", server.RootPerson, other.ID)
+	w = doTest("PUT", otherPath, req, adminToken)
+	assert.Equal(t, 409, w.Code)
+
+	w = doTest("DELETE", path, nil, adminToken)
+	assert.Equal(t, 200, w.Code)
+	w = doTest("DELETE", path, nil, adminToken)
+	assert.Equal(t, 409, w.Code)
+
+	w = doTest("PUT", otherPath, req, adminToken)
+	assert.Equal(t, 200, w.Code)
+}
+
+func TestPerson_GetList_FilterSort(t *testing.T) {
+	accessToken := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	w := doTest("GET", server.RootPerson+"?has_account=true&sort=-fullname", nil, accessToken)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.SuccessResponse[[]dto.PersonDetailResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	for i := 1; i < len(resp.Data); i++ {
+		assert.GreaterOrEqual(t, resp.Data[i-1].Fullname, resp.Data[i].Fullname)
+	}
+	ids := map[int]bool{}
+	for _, item := range resp.Data {
+		ids[item.ID] = true
+	}
+	assert.True(t, ids[int(dummyAdmin.ID)])
+	assert.True(t, ids[int(dummyMember.ID)])
+
+	var unlinked []dao.Person
+	db.Where("account_id IS NULL").Find(&unlinked)
+	for _, person := range unlinked {
+		assert.False(t, ids[int(person.ID)], "Anggota tanpa akun tidak boleh tampil")
+	}
+
+	w = doTest("GET", server.RootPerson+"?foo=1", nil, accessToken)
+	assert.Equal(t, 422, w.Code)
+}
@@ -5,7 +5,9 @@ import (
 	"base-gin/domain/dto"
 	"base-gin/server"
 	"base-gin/util"
+	"context"
 	"fmt"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,7 +33,7 @@ func TestPublisher_Update_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o)
+	_ = publisherRepo.Create(context.Background(), &o)
 
 	params := dto.PublisherUpdateReq{
 		Name: util.RandomStringAlpha(7),
@@ -46,7 +48,7 @@ func TestPublisher_Update_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := publisherRepo.GetByID(o.ID)
+	item, _ := publisherRepo.GetByID(context.Background(), o.ID)
 	assert.Equal(t, params.Name, item.Name)
 	assert.Equal(t, params.City, item.City)
 	assert.Equal(t, false, item.DeletedAt.Valid)
@@ -57,7 +59,7 @@ func TestPublisher_Delete_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o)
+	_ = publisherRepo.Create(context.Background(), &o)
 
 	w := doTest(
 		"DELETE",
@@ -67,22 +69,41 @@ func TestPublisher_Delete_Success(t *testing.T) {
 	)
 	assert.Equal(t, 200, w.Code)
 
-	item, _ := publisherRepo.GetByID(o.ID)
+	item, _ := publisherRepo.GetByID(context.Background(), o.ID)
 	assert.Nil(t, item)
 }
 
+func TestPublisher_Delete_Forbidden(t *testing.T) {
+	o := dao.Publisher{
+		Name: util.RandomStringAlpha(6),
+		City: util.RandomStringAlpha(8),
+	}
+	_ = publisherRepo.Create(context.Background(), &o)
+
+	w := doTest(
+		"DELETE",
+		fmt.Sprintf("%s/%d", server.RootPublisher, o.ID),
+		nil,
+		createAuthAccessToken(dummyMember.Account.Username),
+	)
+	assert.Equal(t, 403, w.Code)
+
+	item, _ := publisherRepo.GetByID(context.Background(), o.ID)
+	assert.NotNil(t, item)
+}
+
 func TestPublisher_GetList_Success(t *testing.T) {
 	o1 := dao.Publisher{
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o1)
+	_ = publisherRepo.Create(context.Background(), &o1)
 
 	o2 := dao.Publisher{
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o2)
+	_ = publisherRepo.Create(context.Background(), &o2)
 
 	w := doTest(
 		"GET",
@@ -114,7 +135,7 @@ func TestPublisher_GetDetail_Success(t *testing.T) {
 		Name: util.RandomStringAlpha(6),
 		City: util.RandomStringAlpha(8),
 	}
-	_ = publisherRepo.Create(&o)
+	_ = publisherRepo.Create(context.Background(), &o)
 
 	w := doTest(
 		"GET",
@@ -127,3 +148,13 @@ func TestPublisher_GetDetail_Success(t *testing.T) {
 	body := w.Body.String()
 	assert.Contains(t, body, o.Name)
 }
+
+func TestPublisher_GetList_ClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := httptest.NewRequest("GET", server.RootPublisher, nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	application.Engine.ServeHTTP(w, r)
+	assert.Equal(t, server.StatusClientClosedRequest, w.Code)
+}
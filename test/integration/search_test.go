@@ -0,0 +1,115 @@
+package integration_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func doSearch(t *testing.T, query string) []dto.SearchResp {
+	t.Helper()
+
+	w := doTest("GET", server.RootSearch+"?"+query, nil, "")
+	if !assert.Equal(t, 200, w.Code, query) {
+		return nil
+	}
+
+	var resp dto.SuccessResponse[[]dto.SearchResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+
+	return resp.Data
+}
+
+func TestSearch_Success(t *testing.T) {
+	b := CreateBook()
+	var author dao.Author
+	db.First(&author, b.Contributors[0].AuthorID)
+	var publisher dao.Publisher
+	db.First(&publisher, b.PublisherID)
+
+	items := doSearch(t, "q="+url.QueryEscape(strings.ToLower(author.Fullname)))
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "author", items[0].Type, "Nama penulis harus lebih tinggi dari buku")
+		assert.Equal(t, int(author.ID), items[0].ID)
+		assert.Equal(t, "book", items[1].Type)
+		assert.Equal(t, b.Title, items[1].Title)
+		assert.Equal(t, "<em>"+author.Fullname+"</em>", items[1].Highlights["contributors"])
+	}
+
+	items = doSearch(t, "q="+url.QueryEscape(b.Title[:5]+" "+publisher.Name)+"&type=book")
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, int(b.ID), items[0].ID)
+		assert.Equal(t, "<em>"+b.Title[:5]+"</em>"+b.Title[5:], items[0].Highlights["title"])
+		assert.Equal(t, "<em>"+publisher.Name+"</em>", items[0].Highlights["publisher"])
+	}
+
+	items = doSearch(t, "q="+url.QueryEscape(publisher.Name)+"&type=publisher")
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, int(publisher.ID), items[0].ID)
+	}
+}
+
+func TestSearch_RankedBeforeLimit(t *testing.T) {
+	word := util.RandomStringAlpha(12)
+	p := CreatePublisher()
+
+	// more subtitle matches than the database hands over for ranking, all
+	// inserted before the title match
+	books := make([]dao.Book, 200)
+	for i := range books {
+		books[i] = dao.Book{Title: util.RandomStringAlpha(10), Subtitle: word + " " + util.RandomStringAlpha(8), PublisherID: p.ID}
+	}
+	db.Create(&books)
+	b := dao.Book{Title: word, Subtitle: util.RandomStringAlpha(15), PublisherID: p.ID}
+	db.Create(&b)
+
+	items := doSearch(t, "q="+strings.ToLower(word)+"&type=book&l=1")
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, int(b.ID), items[0].ID, "Judul harus lebih tinggi dari subjudul")
+	}
+}
+
+func TestSearch_FollowsChanges(t *testing.T) {
+	b := CreateBook()
+	old := b.Title
+	title := util.RandomStringAlpha(10)
+	db.Model(b).Update("title", title)
+
+	items := doSearch(t, "q="+title+"&type=book")
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, int(b.ID), items[0].ID)
+	}
+
+	w := doTest("GET", server.RootSearch+"?q="+old+"&type=book", nil, "")
+	assert.Equal(t, 404, w.Code, "Judul lama tidak boleh ditemukan lagi")
+}
+
+func TestSearch_ISBN(t *testing.T) {
+	b := CreateBook()
+	isbn10, isbn13 := randomISBN()
+	db.Model(b).Update("isbn", isbn13)
+
+	items := doSearch(t, "q="+isbn10)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, int(b.ID), items[0].ID)
+		assert.Equal(t, "<em>"+isbn13+"</em>", items[0].Highlights["isbn"])
+	}
+}
+
+func TestSearch_NotFound(t *testing.T) {
+	w := doTest("GET", server.RootSearch+"?q="+util.RandomStringAlpha(12), nil, "")
+	assert.Equal(t, 404, w.Code)
+
+	w = doTest("GET", server.RootSearch, nil, "")
+	assert.Equal(t, 422, w.Code)
+
+	w = doTest("GET", server.RootSearch+"?q=buku&type=category", nil, "")
+	assert.Equal(t, 422, w.Code)
+}
@@ -0,0 +1,143 @@
+package integration_test
+
+import (
+	"base-gin/domain"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/server"
+	"base-gin/util"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func challenge(t *testing.T, username string) dto.AccountLoginResp {
+	resp := login(t, username)
+	assert.Empty(t, resp.AccessToken)
+	assert.NotEmpty(t, resp.ChallengeToken)
+
+	return resp
+}
+
+func TestTwoFactor_Enable_Success(t *testing.T) {
+	o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	_ = accountRepo.Create(context.Background(), &o)
+	token := createAuthAccessToken(o.Username)
+
+	w := doTest("POST", server.RootAccount+server.PathTwoFactorSetup, nil, token)
+	assert.Equal(t, 200, w.Code)
+	var setup dto.SuccessResponse[dto.TwoFactorSetupResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &setup)
+	assert.NotEmpty(t, setup.Data.URI)
+
+	code, _ := util.TOTPCode(setup.Data.Secret, util.TOTPStep(time.Now()))
+	w = doTest("POST", server.RootAccount+server.PathTwoFactorEnable,
+		dto.TwoFactorEnableReq{Code: code}, token)
+	assert.Equal(t, 200, w.Code)
+	var enabled dto.SuccessResponse[dto.TwoFactorRecoveryResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &enabled)
+	assert.Len(t, enabled.Data.RecoveryCodes, 10)
+
+	// The code used to enable cannot be replayed to log in.
+	c := challenge(t, o.Username)
+	req := dto.AccountLoginTwoFactorReq{ChallengeToken: c.ChallengeToken, Code: code}
+	w = doTest("POST", server.RootAccount+server.PathLoginTwoFactor, req, "")
+	assert.Equal(t, 400, w.Code)
+
+	// A challenge token is not an access token.
+	w = doTest("GET", server.RootAccount, nil, c.ChallengeToken)
+	assert.Equal(t, 401, w.Code)
+
+	req.Code = enabled.Data.RecoveryCodes[0]
+	w = doTest("POST", server.RootAccount+server.PathLoginTwoFactor, req, "")
+	assert.Equal(t, 200, w.Code)
+	var resp dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NotEmpty(t, resp.Data.AccessToken)
+
+	// Recovery codes are single-use.
+	w = doTest("POST", server.RootAccount+server.PathLoginTwoFactor, req, "")
+	assert.Equal(t, 400, w.Code)
+
+	w = doTest("POST", server.RootAccount+server.PathTwoFactorDisable,
+		dto.TwoFactorDisableReq{Password: password}, resp.Data.AccessToken)
+	assert.Equal(t, 200, w.Code)
+
+	login(t, o.Username)
+}
+
+func TestTwoFactor_RoleRequired(t *testing.T) {
+	o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	o.Role = domain.RoleLibrarian
+	_ = accountRepo.Create(context.Background(), &o)
+
+	adminToken := createAuthAccessToken(dummyAdmin.Account.Username)
+	path := server.RootAccount + "/roles/" + string(domain.RoleLibrarian) + "/2fa"
+	required, notRequired := true, false
+
+	w := doTest("PUT", path, dto.RoleTwoFactorReq{Required: &required},
+		createAuthAccessToken(dummyMember.Account.Username))
+	assert.Equal(t, 403, w.Code)
+
+	w = doTest("PUT", path, dto.RoleTwoFactorReq{Required: &required}, adminToken)
+	assert.Equal(t, 200, w.Code)
+	defer doTest("PUT", path, dto.RoleTwoFactorReq{Required: &notRequired}, adminToken)
+
+	c := challenge(t, o.Username)
+	assert.True(t, c.TwoFactorSetup)
+
+	w = doTest("POST", server.RootAccount+server.PathLoginTwoFactorSetup,
+		dto.AccountLoginChallengeReq{ChallengeToken: c.ChallengeToken}, "")
+	assert.Equal(t, 200, w.Code)
+	var setup dto.SuccessResponse[dto.TwoFactorSetupResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &setup)
+
+	code, _ := util.TOTPCode(setup.Data.Secret, util.TOTPStep(time.Now()))
+	req := dto.AccountLoginTwoFactorReq{ChallengeToken: c.ChallengeToken, Code: code}
+	w = doTest("POST", server.RootAccount+server.PathLoginTwoFactor, req, "")
+	assert.Equal(t, 200, w.Code)
+	var resp dto.SuccessResponse[dto.AccountLoginResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NotEmpty(t, resp.Data.AccessToken)
+	assert.Len(t, resp.Data.RecoveryCodes, 10)
+
+	w = doTest("POST", server.RootAccount+server.PathTwoFactorDisable,
+		dto.TwoFactorDisableReq{Password: password}, resp.Data.AccessToken)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestTwoFactor_Login_Throttled(t *testing.T) {
+	useApplication(t, cfg)
+
+	o, _ := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	_ = accountRepo.Create(context.Background(), &o)
+	token := createAuthAccessToken(o.Username)
+	w := doTest("POST", server.RootAccount+server.PathTwoFactorSetup, nil, token)
+	var setup dto.SuccessResponse[dto.TwoFactorSetupResp]
+	_ = json.Unmarshal(w.Body.Bytes(), &setup)
+	code, _ := util.TOTPCode(setup.Data.Secret, util.TOTPStep(time.Now()))
+	w = doTest("POST", server.RootAccount+server.PathTwoFactorEnable, dto.TwoFactorEnableReq{Code: code}, token)
+	assert.Equal(t, 200, w.Code)
+
+	// Every round comes from another IP, so only the username's counter can
+	// stop it; the password and the code each take an attempt.
+	cred := dto.AccountLoginReq{Username: o.Username, Password: password}
+	for i := 0; i < cfg.AuthN.LoginMaxAttempt/2; i++ {
+		ip := fmt.Sprintf("192.0.2.%d", i+10)
+		w = doTestFrom(ip, "POST", server.RootAccount+server.PathLogin, cred)
+		assert.Equal(t, 200, w.Code)
+		var c dto.SuccessResponse[dto.AccountLoginResp]
+		_ = json.Unmarshal(w.Body.Bytes(), &c)
+
+		req := dto.AccountLoginTwoFactorReq{ChallengeToken: c.Data.ChallengeToken, Code: "000000"}
+		w = doTestFrom(ip, "POST", server.RootAccount+server.PathLoginTwoFactor, req)
+		assert.Equal(t, 400, w.Code)
+	}
+
+	w = doTestFrom("192.0.2.200", "POST", server.RootAccount+server.PathLogin, cred)
+	assert.Equal(t, 429, w.Code, "Login dengan sandi benar tidak boleh mengosongkan hitungan kode")
+}
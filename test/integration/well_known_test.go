@@ -0,0 +1,73 @@
+package integration_test
+
+import (
+	"base-gin/config"
+	"base-gin/server"
+	"base-gin/util"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// withKeys swaps in an application built with authN for the rest of the
+// test.
+func withKeys(t *testing.T, authN config.AuthNConfig) {
+	t.Helper()
+
+	c := cfg
+	c.AuthN = authN
+	useApplication(t, c)
+}
+
+func TestWellKnown_JWKS_Rotation(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	keyFile := filepath.Join(t.TempDir(), "signing.pem")
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+
+	// Signed with JWT_SECRET, which is still set below.
+	legacy := createAuthAccessToken(dummyAdmin.Account.Username)
+
+	authN := cfg.AuthN
+	authN.JWTSigningKeyFile = keyFile
+	withKeys(t, authN)
+
+	w := doTest("GET", server.RootWellKnown+server.PathJWKS, nil, "")
+	assert.Equal(t, 200, w.Code)
+	var jwks util.JWKS
+	_ = json.Unmarshal(w.Body.Bytes(), &jwks)
+	if assert.Len(t, jwks.Keys, 1) {
+		assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	}
+
+	token := login(t, "admin").AccessToken
+	parsed, _, _ := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+	if len(jwks.Keys) == 1 {
+		assert.Equal(t, jwks.Keys[0].Kid, parsed.Header["kid"])
+	}
+
+	w = doTest("GET", server.RootAccount, nil, token)
+	assert.Equal(t, 200, w.Code)
+	w = doTest("GET", server.RootAccount, nil, legacy)
+	assert.Equal(t, 401, w.Code, "JWT_SECRET tidak boleh berlaku lagi setelah kunci penandatangan dipasang")
+
+	authN.JWTLegacyHS256Until = time.Now().Add(time.Hour).Format(time.RFC3339)
+	withKeys(t, authN)
+	w = doTest("GET", server.RootAccount, nil, legacy)
+	assert.Equal(t, 200, w.Code)
+
+	authN.JWTLegacyHS256Until = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	withKeys(t, authN)
+	w = doTest("GET", server.RootAccount, nil, legacy)
+	assert.Equal(t, 401, w.Code)
+}
@@ -0,0 +1,75 @@
+package unit_test
+
+import (
+	"base-gin/app"
+	"base-gin/config"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/migrations"
+	"base-gin/storage"
+	"base-gin/util"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSQLiteApp(t *testing.T, name string) *app.App {
+	t.Helper()
+
+	return newSQLiteAppWithConfig(t, name, cfg)
+}
+
+func newSQLiteAppWithConfig(t *testing.T, name string, c config.Config) *app.App {
+	t.Helper()
+
+	c.DB.Driver = "sqlite"
+	c.DB.DSN = "file:" + name + "?mode=memory&cache=shared"
+	db := storage.NewDB(c)
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := app.New(&c, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a
+}
+
+func TestApp_TwoInstances(t *testing.T) {
+	first := newSQLiteApp(t, "first")
+	second := newSQLiteApp(t, "second")
+
+	err := first.Services.Publisher.Create(context.Background(), &dto.PublisherCreateReq{Name: "Gramedia", City: "Jakarta"})
+	assert.Nil(t, err)
+
+	items, err := first.Services.Publisher.GetList(context.Background(), &dto.Filter{})
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	_, err = second.Services.Publisher.GetList(context.Background(), &dto.Filter{})
+	assert.ErrorIs(t, err, exception.ErrDataNotFound, "Instance kedua tidak boleh melihat data instance pertama")
+}
+
+func TestApp_TwoInstances_KeySet(t *testing.T) {
+	c := cfg
+	c.AuthN.JWTSecretKey = "first-secret"
+	first := newSQLiteAppWithConfig(t, "first-keys", c)
+	c.AuthN.JWTSecretKey = "second-secret"
+	second := newSQLiteAppWithConfig(t, "second-keys", c)
+
+	token, err := util.CreateAuthAccessToken(c, first.KeySet, "admin", "admin", "session")
+	assert.Nil(t, err)
+
+	_, err = util.VerifyAuthAccessToken(first.KeySet, token)
+	assert.Nil(t, err)
+	_, err = util.VerifyAuthAccessToken(second.KeySet, token)
+	assert.Error(t, err, "Token instance pertama tidak boleh berlaku di instance kedua")
+}
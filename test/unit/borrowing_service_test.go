@@ -0,0 +1,104 @@
+package unit_test
+
+import (
+	"base-gin/config"
+	"base-gin/domain/dao"
+	"base-gin/domain/dto"
+	"base-gin/exception"
+	"base-gin/repository"
+	"base-gin/service"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The fakes embed the repository interface so they only implement what the
+// service under test calls; anything else panics.
+
+type fakeBorrowingRepo struct {
+	repository.BorrowingRepository
+	created []dao.Borrowing
+	items   []dao.Borrowing
+}
+
+func (r *fakeBorrowingRepo) CreateIfAvailable(ctx context.Context, newItem *dao.Borrowing, policy dao.CirculationPolicy) error {
+	r.created = append(r.created, *newItem)
+	return nil
+}
+
+func (r *fakeBorrowingRepo) GetList(ctx context.Context, params *dto.BorrowingFilter) ([]dao.Borrowing, error) {
+	return r.items, nil
+}
+
+type fakeFineRepo struct {
+	repository.FineRepository
+	balance int64
+}
+
+func (r *fakeFineRepo) GetBalance(ctx context.Context, personID uint) (int64, error) {
+	return r.balance, nil
+}
+
+type fakeHoldRepo struct {
+	repository.HoldRepository
+}
+
+// fakeUnitOfWork runs the work without a transaction on the repositories it
+// was given.
+type fakeUnitOfWork struct {
+	repos *repository.Repositories
+}
+
+func (u *fakeUnitOfWork) Do(ctx context.Context, fn func(repos *repository.Repositories) error) error {
+	return fn(u.repos)
+}
+
+func newBorrowingService(borrowings *fakeBorrowingRepo, fines *fakeFineRepo) service.BorrowingService {
+	cfg := config.Config{Library: config.LibraryConfig{
+		LoanPeriodDays:     14,
+		FineBlockThreshold: 20000,
+		HoldPickupDays:     3,
+	}}
+
+	holds := &fakeHoldRepo{}
+	uow := &fakeUnitOfWork{repos: &repository.Repositories{
+		Borrowing: borrowings,
+		Fine:      fines,
+		Hold:      holds,
+	}}
+
+	return service.NewBorrowingService(&cfg, uow, borrowings, holds)
+}
+
+func TestBorrowingService_Create_SetsDueDate(t *testing.T) {
+	borrowings := &fakeBorrowingRepo{}
+	s := newBorrowingService(borrowings, &fakeFineRepo{})
+
+	before := time.Now().UTC()
+	err := s.Create(context.Background(), &dto.BorrowingCreateReq{BookCopyID: 1, PersonID: 2})
+	assert.Nil(t, err)
+
+	if assert.Len(t, borrowings.created, 1) {
+		created := borrowings.created[0]
+		assert.False(t, created.BorrowDate.Before(before), "Tanggal pinjam diisi dengan waktu server")
+		assert.Equal(t, created.BorrowDate.AddDate(0, 0, 14), *created.DueDate)
+	}
+}
+
+func TestBorrowingService_Create_FineBalanceTooHigh(t *testing.T) {
+	borrowings := &fakeBorrowingRepo{}
+	s := newBorrowingService(borrowings, &fakeFineRepo{balance: 20001})
+
+	err := s.Create(context.Background(), &dto.BorrowingCreateReq{BookCopyID: 1, PersonID: 2})
+	assert.ErrorIs(t, err, exception.ErrFineBalanceTooHigh)
+	assert.Empty(t, borrowings.created, "Peminjaman tidak boleh dibuat")
+}
+
+func TestBorrowingService_GetList_Empty(t *testing.T) {
+	s := newBorrowingService(&fakeBorrowingRepo{}, &fakeFineRepo{})
+
+	_, err := s.GetList(context.Background(), &dto.BorrowingFilter{})
+	assert.ErrorIs(t, err, exception.ErrDataNotFound)
+}
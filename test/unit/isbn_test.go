@@ -0,0 +1,30 @@
+package unit_test
+
+import (
+	"base-gin/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	valid := map[string]string{
+		"0306406152":        "9780306406157",
+		"0-306-40615-2":     "9780306406157",
+		"080442957X":        "9780804429573",
+		"080442957x":        "9780804429573",
+		"9780306406157":     "9780306406157",
+		"978-0-306-40615-7": "9780306406157",
+		"979 10 90636 07 1": "9791090636071",
+	}
+	for input, want := range valid {
+		got, err := util.NormalizeISBN(input)
+		assert.Nil(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "0306406153", "9780306406158", "030640615", "97803064061570", "03064O6152", "X306406152"} {
+		_, err := util.NormalizeISBN(input)
+		assert.ErrorIs(t, err, util.ErrISBNInvalid, input)
+	}
+}
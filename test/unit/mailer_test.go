@@ -0,0 +1,30 @@
+package unit_test
+
+import (
+	"base-gin/mailer"
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogMailer_OmitsBody(t *testing.T) {
+	var out bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(&out)
+	defer func() { log.Logger = previous }()
+
+	c := cfg
+	c.Mail.Driver = mailer.DriverLog
+	m, err := mailer.New(&c)
+	assert.Nil(t, err)
+
+	err = m.Send(mailer.Message{To: "anggota@example.com", Subject: "Reset password", Body: "token=rahasia"})
+	assert.Nil(t, err)
+
+	assert.Contains(t, out.String(), "anggota@example.com")
+	assert.Contains(t, out.String(), "Reset password")
+	assert.NotContains(t, out.String(), "rahasia", "Isi pesan tidak boleh masuk log")
+}
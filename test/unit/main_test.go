@@ -7,6 +7,7 @@ import (
 	"base-gin/repository"
 	"base-gin/storage"
 	"base-gin/util"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -29,8 +30,8 @@ var (
 	dummyAdmin  *dao.Person
 	dummyMember *dao.Person
 
-	accountRepo *repository.AccountRepository
-	personRepo  *repository.PersonRepository
+	accountRepo repository.AccountRepository
+	personRepo  repository.PersonRepository
 )
 
 func TestMain(m *testing.M) {
@@ -48,14 +49,12 @@ func setup() {
 
 	cfg = config.NewConfig()
 
-	storage.InitDB(cfg)
-	db = storage.GetDB()
+	db = storage.NewDB(cfg)
 	teardownDB()
 	setupDB()
 
-	repository.SetupRepositories()
-	accountRepo = repository.GetAccountRepo()
-	personRepo = repository.GetPersonRepo()
+	accountRepo = repository.NewAccountRepository(db)
+	personRepo = repository.NewPersonRepository(db)
 
 	a := createDummyAccount()
 	dummyAdmin = createDummyProfile(a)
@@ -65,6 +64,7 @@ func setup() {
 
 func teardownDB() {
 	_ = db.Migrator().DropTable(
+		&dao.Session{},
 		&dao.Account{},
 		&dao.Person{},
 	)
@@ -74,12 +74,13 @@ func setupDB() {
 	_ = db.AutoMigrate(
 		&dao.Account{},
 		&dao.Person{},
+		&dao.Session{},
 	)
 }
 
 func createDummyAccount() *dao.Account {
 	account, _ := dao.NewUser("admin", password, cfg.AuthN.PasswordEncryptionSecret)
-	accountRepo.Create(&account)
+	accountRepo.Create(context.Background(), &account)
 	return &account
 }
 
@@ -96,7 +97,7 @@ func createDummyProfile(account *dao.Account) *dao.Person {
 		person.Account = account
 	}
 
-	personRepo.Create(&person)
+	personRepo.Create(context.Background(), &person)
 
 	return &person
 }
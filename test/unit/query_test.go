@@ -0,0 +1,75 @@
+package unit_test
+
+import (
+	"base-gin/query"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSpec = query.Spec{
+	"title":       {Column: "books.title", Type: query.TypeString, Sort: true},
+	"borrow_date": {Column: "borrowings.borrow_date", Type: query.TypeDate, Ops: []query.Op{query.OpGte, query.OpLte}, Sort: true},
+	"author_id":   {Column: "books.id", Type: query.TypeInt, Ops: []query.Op{query.OpEq, query.OpIn}},
+	"returned":    {Column: "borrowings.return_date", Type: query.TypePresence, Ops: []query.Op{query.OpEq}},
+}
+
+func TestQuerySpec_Parse(t *testing.T) {
+	values, _ := url.ParseQuery("sort=-borrow_date,title&author_id[in]=1,2&returned=false&q=ignored")
+	params, err := testSpec.Parse(values, "q")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []query.Order{
+		{Column: "borrowings.borrow_date", Desc: true},
+		{Column: "books.title"},
+	}, params.Orders)
+	if assert.Len(t, params.Conditions, 2) {
+		assert.Equal(t, query.OpIn, params.Conditions[0].Op)
+		assert.Equal(t, []interface{}{int64(1), int64(2)}, params.Conditions[0].Value)
+		assert.Equal(t, "borrowings.return_date", params.Conditions[1].Field.Column)
+		assert.Equal(t, false, params.Conditions[1].Value)
+	}
+}
+
+func TestQuerySpec_Parse_WholeDay(t *testing.T) {
+	values, _ := url.ParseQuery("borrow_date[gte]=2024-03-01&borrow_date[lte]=2024-03-31")
+	params, err := testSpec.Parse(values)
+	assert.Nil(t, err)
+
+	if assert.Len(t, params.Conditions, 2) {
+		assert.Equal(t, query.OpGte, params.Conditions[0].Op)
+		assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), params.Conditions[0].Value)
+		assert.Equal(t, query.OpLt, params.Conditions[1].Op, "Tanggal akhir harus mencakup seluruh hari")
+		assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), params.Conditions[1].Value)
+	}
+}
+
+func TestQuerySpec_Parse_Invalid(t *testing.T) {
+	values, _ := url.ParseQuery("sort=password&title=x&author_id[gt]=1&borrow_date[gte]=kemarin&returned=maybe&role=admin")
+	_, err := testSpec.Parse(values)
+
+	var errs query.Errors
+	if assert.ErrorAs(t, err, &errs) {
+		fields := make([]string, len(errs))
+		for i, fe := range errs {
+			fields[i] = fe.Field
+		}
+		assert.Equal(t, []string{"sort", "author_id[gt]", "borrow_date[gte]", "returned", "role", "title"}, fields)
+	}
+}
+
+func TestQuery_FormKeys(t *testing.T) {
+	type filter struct {
+		Page    int    `form:"page"`
+		Ignored string `form:"-"`
+		Plain   string
+	}
+	type request struct {
+		filter
+		ISBN string `form:"isbn,omitempty"`
+	}
+
+	assert.Equal(t, []string{"page", "isbn"}, query.FormKeys(&request{}))
+}
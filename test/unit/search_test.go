@@ -0,0 +1,162 @@
+package unit_test
+
+import (
+	"base-gin/domain/dto"
+	"base-gin/search"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSource hands every document to the index and leaves the matching to
+// the ranking, so both indexes can be tested on the same data.
+type fakeSource struct {
+	docs  []search.Document
+	loads int
+}
+
+func (s *fakeSource) Match(ctx context.Context, q search.Query, limit int) ([]search.Document, error) {
+	return s.docs, nil
+}
+
+func (s *fakeSource) All(ctx context.Context) ([]search.Document, error) {
+	s.loads++
+	return s.docs, nil
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{docs: []search.Document{
+		{Kind: search.KindBook, ID: 1, Label: "Laskar Pelangi", Fields: map[string]string{
+			search.FieldTitle:        "Laskar Pelangi",
+			search.FieldSubtitle:     "Sebuah novel",
+			search.FieldContributors: "Andrea Hirata",
+			search.FieldPublisher:    "Bentang Pustaka",
+			search.FieldISBN:         "9789793062792",
+		}},
+		{Kind: search.KindBook, ID: 2, Label: "Sang Pemimpi", Fields: map[string]string{
+			search.FieldTitle:        "Sang Pemimpi",
+			search.FieldSubtitle:     "Lanjutan Laskar Pelangi",
+			search.FieldContributors: "Andrea Hirata",
+			search.FieldPublisher:    "Bentang Pustaka",
+		}},
+		{Kind: search.KindAuthor, ID: 1, Label: "Andrea Hirata", Fields: map[string]string{
+			search.FieldName: "Andrea Hirata",
+		}},
+		{Kind: search.KindPublisher, ID: 1, Label: "Bentang <Pustaka>", Fields: map[string]string{
+			search.FieldName: "Bentang <Pustaka>",
+		}},
+	}}
+}
+
+func newIndexes(source search.Source) map[string]search.Index {
+	return map[string]search.Index{
+		search.DriverDatabase: search.NewDatabaseIndex(source),
+		search.DriverMemory:   search.NewMemoryIndex(source),
+	}
+}
+
+func TestSearch_Ranking(t *testing.T) {
+	for driver, index := range newIndexes(newFakeSource()) {
+		hits, err := index.Search(context.Background(), search.ParseQuery("laskar pelangi", ""), 10)
+		assert.Nil(t, err, driver)
+		if assert.Len(t, hits, 2, driver) {
+			assert.Equal(t, uint(1), hits[0].ID, "%s: judul harus lebih tinggi dari subjudul", driver)
+			assert.Equal(t, "<em>Laskar</em> <em>Pelangi</em>", hits[0].Highlights[search.FieldTitle], driver)
+			assert.Equal(t, "Lanjutan <em>Laskar</em> <em>Pelangi</em>", hits[1].Highlights[search.FieldSubtitle], driver)
+		}
+
+		hits, err = index.Search(context.Background(), search.ParseQuery("hirata", ""), 10)
+		assert.Nil(t, err, driver)
+		if assert.Len(t, hits, 3, driver) {
+			assert.Equal(t, search.KindAuthor, hits[0].Kind, "%s: nama penulis harus paling atas", driver)
+		}
+
+		hits, err = index.Search(context.Background(), search.ParseQuery("hirata", search.KindBook), 1)
+		assert.Nil(t, err, driver)
+		if assert.Len(t, hits, 1, driver) {
+			assert.Equal(t, search.KindBook, hits[0].Kind, driver)
+		}
+
+		hits, err = index.Search(context.Background(), search.ParseQuery("pustaka", search.KindPublisher), 10)
+		assert.Nil(t, err, driver)
+		if assert.Len(t, hits, 1, driver) {
+			assert.Equal(t, "Bentang &lt;<em>Pustaka</em>&gt;", hits[0].Highlights[search.FieldName], driver)
+		}
+
+		hits, err = index.Search(context.Background(), search.ParseQuery("laskar hirata novel", ""), 10)
+		assert.Nil(t, err, driver)
+		if assert.Len(t, hits, 1, "%s: semua kata harus cocok", driver) {
+			assert.Equal(t, uint(1), hits[0].ID, driver)
+		}
+	}
+}
+
+func TestSearch_ISBN(t *testing.T) {
+	for driver, index := range newIndexes(newFakeSource()) {
+		hits, err := index.Search(context.Background(), search.ParseQuery("979-3062-79-7", ""), 10)
+		assert.Nil(t, err, driver)
+		if assert.Len(t, hits, 1, driver) {
+			assert.Equal(t, uint(1), hits[0].ID, driver)
+			assert.Equal(t, "<em>9789793062792</em>", hits[0].Highlights[search.FieldISBN], driver)
+		}
+
+		hits, err = index.Search(context.Background(), search.ParseQuery("979306", ""), 10)
+		assert.Nil(t, err, driver)
+		assert.Empty(t, hits, "%s: potongan ISBN tidak dicari", driver)
+	}
+}
+
+func TestSearch_WordPrefix(t *testing.T) {
+	for driver, index := range newIndexes(newFakeSource()) {
+		hits, err := index.Search(context.Background(), search.ParseQuery("pelan", search.KindBook), 10)
+		assert.Nil(t, err, driver)
+		if assert.Len(t, hits, 2, driver) {
+			assert.Equal(t, "Laskar <em>Pelan</em>gi", hits[0].Highlights[search.FieldTitle], driver)
+		}
+
+		hits, err = index.Search(context.Background(), search.ParseQuery("angi", ""), 10)
+		assert.Nil(t, err, driver)
+		assert.Empty(t, hits, "%s: potongan di tengah kata tidak dicocokkan", driver)
+	}
+}
+
+func TestSearch_MemoryIndexInvalidate(t *testing.T) {
+	source := newFakeSource()
+	index := search.NewMemoryIndex(source)
+	q := search.ParseQuery("ayat", "")
+
+	hits, _ := index.Search(context.Background(), q, 10)
+	assert.Empty(t, hits)
+	_, _ = index.Search(context.Background(), q, 10)
+	assert.Equal(t, 1, source.loads, "Indeks hanya dibangun sekali")
+
+	source.docs = append(source.docs, search.Document{Kind: search.KindBook, ID: 3, Label: "Ayat-Ayat Cinta", Fields: map[string]string{
+		search.FieldTitle: "Ayat-Ayat Cinta",
+	}})
+	index.Invalidate()
+
+	hits, _ = index.Search(context.Background(), q, 10)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, 2, source.loads)
+}
+
+func TestApp_SearchMemoryIndex(t *testing.T) {
+	c := cfg
+	c.Search.Driver = search.DriverMemory
+	a := newSQLiteAppWithConfig(t, "search", c)
+	ctx := context.Background()
+
+	_, err := a.Services.Search.Search(ctx, &dto.SearchReq{Query: "gramedia"})
+	assert.NotNil(t, err)
+
+	err = a.Services.Publisher.Create(ctx, &dto.PublisherCreateReq{Name: "Gramedia", City: "Jakarta"})
+	assert.Nil(t, err)
+
+	items, err := a.Services.Search.Search(ctx, &dto.SearchReq{Query: "gramedia"})
+	assert.Nil(t, err, "Indeks harus diperbarui setelah penerbit ditambahkan")
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "publisher", items[0].Type)
+		assert.Equal(t, "<em>Gramedia</em>", items[0].Highlights["name"])
+	}
+}
@@ -0,0 +1,26 @@
+package unit_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/repository"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_IsRevoked_Expired(t *testing.T) {
+	ctx := context.Background()
+	account := newUnitOfWorkAccount(t)
+	_ = accountRepo.Create(ctx, &account)
+
+	repos := repository.NewRepositories(db)
+	session := dao.Session{ID: uuid.NewString(), AccountID: account.ID, ExpiresAt: time.Now().Add(-time.Minute)}
+	_ = repos.Session.Create(ctx, &session)
+
+	revoked, err := repos.Session.IsRevoked(ctx, session.ID)
+	assert.Nil(t, err)
+	assert.True(t, revoked, "Sesi kedaluwarsa tidak boleh dipakai lagi")
+}
@@ -0,0 +1,50 @@
+package unit_test
+
+import (
+	"base-gin/server"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginThrottle_Concurrent(t *testing.T) {
+	c := cfg
+	c.AuthN.LoginMaxAttempt = 5
+	throttle := server.NewLoginThrottle(&c, server.NewMemoryThrottleStore())
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := throttle.Allow("admin", "10.0.0.1"); err == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(5), allowed.Load(), "Percobaan serentak tidak boleh melewati batas")
+}
+
+func TestLoginThrottle_Succeed(t *testing.T) {
+	c := cfg
+	c.AuthN.LoginMaxAttempt = 2
+	throttle := server.NewLoginThrottle(&c, server.NewMemoryThrottleStore())
+
+	_, err := throttle.Allow("admin", "10.0.0.1")
+	assert.Nil(t, err)
+
+	// The member's success frees the member's attempt only.
+	_, err = throttle.Allow("member", "10.0.0.1")
+	assert.Nil(t, err)
+	assert.Nil(t, throttle.Succeed("member", "10.0.0.1"))
+
+	_, err = throttle.Allow("admin", "10.0.0.1")
+	assert.Nil(t, err)
+	_, err = throttle.Allow("member", "10.0.0.1")
+	assert.ErrorIs(t, err, server.ErrRequestThrottled)
+}
@@ -0,0 +1,105 @@
+package unit_test
+
+import (
+	"base-gin/domain/dao"
+	"base-gin/exception"
+	"base-gin/repository"
+	"base-gin/util"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUnitOfWorkAccount(t *testing.T) dao.Account {
+	t.Helper()
+
+	account, err := dao.NewUser(util.RandomStringAlpha(10), password, cfg.AuthN.PasswordEncryptionSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	account := newUnitOfWorkAccount(t)
+	person := dao.Person{Fullname: util.RandomStringAlpha(8)}
+
+	err := repository.NewUnitOfWork(db, repository.NewRepositories(db)).Do(context.Background(), func(repos *repository.Repositories) error {
+		if err := repos.Account.Create(context.Background(), &account); err != nil {
+			return err
+		}
+
+		person.AccountID = &account.ID
+		return repos.Person.Create(context.Background(), &person)
+	})
+	assert.Nil(t, err)
+
+	_, err = accountRepo.GetByUsername(context.Background(), account.Username)
+	assert.Nil(t, err)
+	item, err := personRepo.GetByAccountID(context.Background(), account.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, person.ID, item.ID)
+}
+
+func TestUnitOfWork_RollbackOnError(t *testing.T) {
+	account := newUnitOfWorkAccount(t)
+	errFailed := errors.New("gagal")
+
+	err := repository.NewUnitOfWork(db, repository.NewRepositories(db)).Do(context.Background(), func(repos *repository.Repositories) error {
+		if err := repos.Account.Create(context.Background(), &account); err != nil {
+			return err
+		}
+
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+
+	_, err = accountRepo.GetByUsername(context.Background(), account.Username)
+	assert.ErrorIs(t, err, exception.ErrUserNotFound, "Akun tidak boleh tersimpan")
+}
+
+func TestUnitOfWork_RollbackOnPanic(t *testing.T) {
+	account := newUnitOfWorkAccount(t)
+
+	assert.Panics(t, func() {
+		_ = repository.NewUnitOfWork(db, repository.NewRepositories(db)).Do(context.Background(), func(repos *repository.Repositories) error {
+			if err := repos.Account.Create(context.Background(), &account); err != nil {
+				return err
+			}
+
+			panic("gagal")
+		})
+	})
+
+	_, err := accountRepo.GetByUsername(context.Background(), account.Username)
+	assert.ErrorIs(t, err, exception.ErrUserNotFound, "Akun tidak boleh tersimpan")
+}
+
+func TestUnitOfWork_SharesSessionCache(t *testing.T) {
+	ctx := context.Background()
+	account := newUnitOfWorkAccount(t)
+	_ = accountRepo.Create(ctx, &account)
+
+	repos := repository.NewRepositories(db)
+	session := dao.Session{ID: uuid.NewString(), AccountID: account.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	_ = repos.Session.Create(ctx, &session)
+
+	revoked, err := repos.Session.IsRevoked(ctx, session.ID)
+	assert.Nil(t, err)
+	assert.False(t, revoked)
+
+	err = repository.NewUnitOfWork(db, repos).Do(ctx, func(tx *repository.Repositories) error {
+		_, err := tx.Session.RevokeByAccount(ctx, account.ID, time.Now())
+		return err
+	})
+	assert.Nil(t, err)
+
+	revoked, err = repos.Session.IsRevoked(ctx, session.ID)
+	assert.Nil(t, err)
+	assert.True(t, revoked, "Pencabutan dalam unit of work harus langsung terlihat")
+}
@@ -4,7 +4,9 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"regexp"
@@ -119,6 +121,14 @@ func DecryptAESGCM(cipherText string, secret string) (string, error) {
 	return string(plainText), nil
 }
 
+// SHA256Hex returns the hex-encoded SHA-256 digest of plain. It suits
+// high-entropy secrets such as random tokens that must be looked up by
+// their hash; use PasswordHash for passwords.
+func SHA256Hex(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
 // PasswordHash Hash password with bcrypt (default cost, 10 rounds).
 func PasswordHash(plain string) (string, error) {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrISBNInvalid = errors.New("ISBN tidak valid")
+
+// NormalizeISBN checks the check digit of an ISBN-10 or ISBN-13, ignoring
+// hyphens and spaces, and returns it as the 13 digits of its ISBN-13 form.
+func NormalizeISBN(isbn string) (string, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, isbn)
+
+	switch len(digits) {
+	case 10:
+		if !isISBN10(digits) {
+			return "", ErrISBNInvalid
+		}
+		// ISBN-10 is the 978 prefix of ISBN-13 with its own check digit.
+		body := "978" + digits[:9]
+		return body + string(isbn13CheckDigit(body)), nil
+	case 13:
+		if !isDigits(digits) || isbn13CheckDigit(digits[:12]) != digits[12] {
+			return "", ErrISBNInvalid
+		}
+		return digits, nil
+	default:
+		return "", ErrISBNInvalid
+	}
+}
+
+func isISBN10(digits string) bool {
+	if !isDigits(digits[:9]) {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+	switch last := digits[9]; {
+	case last == 'X' || last == 'x':
+		sum += 10
+	case last >= '0' && last <= '9':
+		sum += int(last - '0')
+	default:
+		return false
+	}
+
+	return sum%11 == 0
+}
+
+// isbn13CheckDigit returns the check digit following the 12 digits of body.
+func isbn13CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(body[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,272 @@
+package util
+
+import (
+	"base-gin/config"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrSigningKeyInvalid = errors.New("kunci penandatangan token tidak valid")
+)
+
+// JWK is the public half of a verification key as published in the JWKS.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type verifyKey struct {
+	method jwt.SigningMethod
+	key    crypto.PublicKey
+	jwk    JWK
+}
+
+// KeySet signs tokens with one key and verifies them with any of the keys
+// it knows by kid, so a new signing key can be rolled out while tokens from
+// the previous one remain valid until they expire.
+//
+// With no signing key file the set falls back to HS256 with JWT_SECRET.
+// Once a signing key file is given JWT_SECRET no longer verifies anything,
+// unless JWT_ACCEPT_LEGACY_HS256_UNTIL lets tokens without a kid through
+// against it until that time to ease the switch.
+type KeySet struct {
+	issuer     string
+	method     jwt.SigningMethod
+	signKey    crypto.PrivateKey
+	signKID    string
+	verifyKeys map[string]verifyKey
+	hmacSecret []byte
+	hmacUntil  time.Time // zero when HS256 is the signing method
+}
+
+// NewKeySet loads the keys named in the config. Each application builds its
+// own, so instances with different keys can share a process.
+func NewKeySet(cfg config.AuthNConfig) (*KeySet, error) {
+	ks := &KeySet{
+		issuer:     cfg.JWTIssuer,
+		verifyKeys: map[string]verifyKey{},
+	}
+
+	if cfg.JWTSigningKeyFile == "" {
+		if cfg.JWTSecretKey == "" {
+			return nil, fmt.Errorf("%w: JWT_SECRET or JWT_SIGNING_KEY_FILE is required", ErrSigningKeyInvalid)
+		}
+		ks.method = jwt.SigningMethodHS256
+		ks.hmacSecret = []byte(cfg.JWTSecretKey)
+
+		return ks, nil
+	}
+
+	if cfg.JWTLegacyHS256Until != "" && cfg.JWTSecretKey != "" {
+		until, err := time.Parse(time.RFC3339, cfg.JWTLegacyHS256Until)
+		if err != nil {
+			return nil, fmt.Errorf("%w: JWT_ACCEPT_LEGACY_HS256_UNTIL: %w", ErrSigningKeyInvalid, err)
+		}
+		ks.hmacSecret = []byte(cfg.JWTSecretKey)
+		ks.hmacUntil = until
+	}
+
+	signKey, err := readPrivateKey(cfg.JWTSigningKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	ks.signKey = signKey
+
+	var public crypto.PublicKey
+	switch k := signKey.(type) {
+	case *rsa.PrivateKey:
+		public = &k.PublicKey
+	case ed25519.PrivateKey:
+		public = k.Public()
+	}
+
+	vk, err := newVerifyKey(public)
+	if err != nil {
+		return nil, err
+	}
+	ks.method = vk.method
+	ks.signKID = vk.jwk.Kid
+	ks.verifyKeys[vk.jwk.Kid] = vk
+
+	for _, path := range cfg.JWTVerifyKeyFiles {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		public, err := readPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		vk, err := newVerifyKey(public)
+		if err != nil {
+			return nil, err
+		}
+		ks.verifyKeys[vk.jwk.Kid] = vk
+	}
+
+	return ks, nil
+}
+
+// Sign signs the claims with the current signing key, naming it in the kid
+// header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	if ks.signKey == nil {
+		return token.SignedString(ks.hmacSecret)
+	}
+
+	token.Header["kid"] = ks.signKID
+
+	return token.SignedString(ks.signKey)
+}
+
+// Keyfunc picks the verification key for a token by its kid and refuses a
+// token whose algorithm does not match that key's.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || ks.hmacSecret == nil {
+			return nil, fmt.Errorf("%w: %s", ErrTokenUnknown, "signature not match")
+		}
+		if !ks.hmacUntil.IsZero() && !time.Now().Before(ks.hmacUntil) {
+			return nil, fmt.Errorf("%w: %s", ErrTokenUnknown, "legacy secret retired")
+		}
+		return ks.hmacSecret, nil
+	}
+
+	vk, ok := ks.verifyKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTokenUnknown, "unknown kid")
+	}
+	if token.Method.Alg() != vk.method.Alg() {
+		return nil, fmt.Errorf("%w: %s", ErrTokenUnknown, "signature not match")
+	}
+
+	return vk.key, nil
+}
+
+func (ks *KeySet) Issuer() string {
+	return ks.issuer
+}
+
+// JWKS lists the public verification keys. Symmetric secrets are never
+// published, so an HS256-only set has no keys.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.verifyKeys))}
+	if vk, ok := ks.verifyKeys[ks.signKID]; ok {
+		set.Keys = append(set.Keys, vk.jwk)
+	}
+	for kid, vk := range ks.verifyKeys {
+		if kid != ks.signKID {
+			set.Keys = append(set.Keys, vk.jwk)
+		}
+	}
+
+	return set
+}
+
+func readPrivateKey(path string) (crypto.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSigningKeyInvalid, err)
+	}
+
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s is not an RSA or Ed25519 private key", ErrSigningKeyInvalid, path)
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSigningKeyInvalid, err)
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s is not an RSA or Ed25519 public key", ErrSigningKeyInvalid, path)
+}
+
+// newVerifyKey describes a public key as a JWK whose kid is its RFC 7638
+// thumbprint, so the same key always gets the same kid.
+func newVerifyKey(public crypto.PublicKey) (verifyKey, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	var vk verifyKey
+	var members interface{}
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		vk.method = jwt.SigningMethodRS256
+		vk.jwk = JWK{
+			Kty: "RSA",
+			N:   b64(k.N.Bytes()),
+			E:   b64(big.NewInt(int64(k.E)).Bytes()),
+		}
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{vk.jwk.E, vk.jwk.Kty, vk.jwk.N}
+	case ed25519.PublicKey:
+		vk.method = jwt.SigningMethodEdDSA
+		vk.jwk = JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   b64(k),
+		}
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{vk.jwk.Crv, vk.jwk.Kty, vk.jwk.X}
+	default:
+		return vk, fmt.Errorf("%w: unsupported key type %T", ErrSigningKeyInvalid, public)
+	}
+
+	thumbprint, err := json.Marshal(members)
+	if err != nil {
+		return vk, err
+	}
+	sum := sha256.Sum256(thumbprint)
+
+	vk.key = public
+	vk.jwk.Use = "sig"
+	vk.jwk.Alg = vk.method.Alg()
+	vk.jwk.Kid = b64(sum[:])
+
+	return vk, nil
+}
@@ -7,9 +7,14 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
-const tokenIssuer = "plus.quranbest.com"
+const (
+	// challengeTTL bounds how long a password-verified login may wait for
+	// its second factor.
+	challengeTTL = 5 * time.Minute
+)
 
 var (
 	ErrTokenUnknown               = errors.New("token tidak dikenali")
@@ -20,45 +25,54 @@ var (
 	ErrRefreshTokenFailedToIssue  = errors.New("gagal menerbitkan token refresh")
 	ErrAccessTokenFailedToVerify  = errors.New("gagal verifikasi token access")
 	ErrRefreshTokenFailedToVerify = errors.New("gagal verifikasi token refresh")
+
+	ErrChallengeTokenFailedToIssue  = errors.New("gagal menerbitkan token verifikasi")
+	ErrChallengeTokenFailedToVerify = errors.New("gagal verifikasi token verifikasi")
 )
 
 type AuthAccessClaims struct {
-	Email string `json:"email"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func CreateAuthAccessToken(cfg config.Config, subject string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, AuthAccessClaims{
+// CreateAuthAccessToken signs an access token bound to the given session, so
+// revoking the session invalidates the token before it expires.
+func CreateAuthAccessToken(cfg config.Config, ks *KeySet, subject, role, sessionID string) (string, error) {
+	signedToken, err := ks.Sign(AuthAccessClaims{
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:      uuid.NewString(),
 			Subject: subject,
 			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().
 				Add(time.Duration(cfg.AuthN.JWTAuthTTL) * time.Second),
 			),
-			Issuer:   tokenIssuer,
+			Issuer:   ks.Issuer(),
 			Audience: jwt.ClaimStrings{"access"},
 		},
 	})
-
-	signedToken, err := token.SignedString([]byte(cfg.AuthN.JWTSecretKey))
 	if err != nil {
 		return "", fmt.Errorf("%w: %w", ErrAccessTokenFailedToIssue, err)
 	}
 	return signedToken, nil
 }
 
-func CreateAuthRefreshToken(cfg config.Config, subject string) (string, error) {
+// CreateAuthRefreshToken signs a refresh token carrying tokenID as its jti,
+// which identifies the token server-side for rotation.
+func CreateAuthRefreshToken(cfg config.Config, ks *KeySet, subject, tokenID string) (string, error) {
 	refreshClaims := &jwt.RegisteredClaims{
+		ID:      tokenID,
 		Subject: subject,
 		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().
 			Add(time.Duration(cfg.AuthN.JWTRefreshTTL) * time.Second),
 		),
-		Issuer:   tokenIssuer,
+		Issuer:   ks.Issuer(),
 		Audience: jwt.ClaimStrings{"refresh"},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-
-	signedRefreshToken, err := token.SignedString([]byte(cfg.AuthN.JWTSecretKey))
+	signedRefreshToken, err := ks.Sign(refreshClaims)
 	if err != nil {
 		return "", fmt.Errorf("%w: %s", ErrRefreshTokenFailedToIssue, err.Error())
 	}
@@ -66,13 +80,28 @@ func CreateAuthRefreshToken(cfg config.Config, subject string) (string, error) {
 	return signedRefreshToken, nil
 }
 
-func verifyAuthToken(cfg config.Config, authToken string, tokenAud string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(authToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("%w: %s", ErrTokenUnknown, "signature not match")
-		}
-		return []byte(cfg.AuthN.JWTSecretKey), nil
-	})
+// CreateAuthChallengeToken signs a short-lived token proving the subject
+// passed the password check and still owes a second factor. It is not
+// accepted where an access or refresh token is expected.
+func CreateAuthChallengeToken(ks *KeySet, subject string) (string, error) {
+	claims := &jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(challengeTTL)),
+		Issuer:    ks.Issuer(),
+		Audience:  jwt.ClaimStrings{"2fa"},
+	}
+
+	signedToken, err := ks.Sign(claims)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrChallengeTokenFailedToIssue, err.Error())
+	}
+
+	return signedToken, nil
+}
+
+func verifyAuthToken(ks *KeySet, authToken string, tokenAud string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(authToken, ks.Keyfunc)
 	if err != nil || !token.Valid {
 		return nil, ErrAuthTokenExpired
 	}
@@ -82,7 +111,7 @@ func verifyAuthToken(cfg config.Config, authToken string, tokenAud string) (jwt.
 		return nil, fmt.Errorf("%w: %s", ErrTokenUnknown, "invalid structure")
 	}
 
-	if !accessClaims.VerifyIssuer(tokenIssuer, true) ||
+	if !accessClaims.VerifyIssuer(ks.Issuer(), true) ||
 		!accessClaims.VerifyAudience(tokenAud, true) {
 		return nil, ErrTokenUnknown
 	}
@@ -90,8 +119,8 @@ func verifyAuthToken(cfg config.Config, authToken string, tokenAud string) (jwt.
 	return accessClaims, nil
 }
 
-func VerifyAuthAccessToken(cfg config.Config, token string) (jwt.MapClaims, error) {
-	accessClaims, err := verifyAuthToken(cfg, token, "access")
+func VerifyAuthAccessToken(ks *KeySet, token string) (jwt.MapClaims, error) {
+	accessClaims, err := verifyAuthToken(ks, token, "access")
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrAccessTokenFailedToVerify, err.Error())
 	}
@@ -99,11 +128,20 @@ func VerifyAuthAccessToken(cfg config.Config, token string) (jwt.MapClaims, erro
 	return accessClaims, nil
 }
 
-func VerifyAuthRefreshToken(cfg config.Config, token string) (jwt.MapClaims, error) {
-	accessClaims, err := verifyAuthToken(cfg, token, "refresh")
+func VerifyAuthRefreshToken(ks *KeySet, token string) (jwt.MapClaims, error) {
+	accessClaims, err := verifyAuthToken(ks, token, "refresh")
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrRefreshTokenFailedToVerify, err.Error())
 	}
 
 	return accessClaims, nil
 }
+
+func VerifyAuthChallengeToken(ks *KeySet, token string) (jwt.MapClaims, error) {
+	claims, err := verifyAuthToken(ks, token, "2fa")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrChallengeTokenFailedToVerify, err.Error())
+	}
+
+	return claims, nil
+}